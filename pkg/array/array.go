@@ -0,0 +1,758 @@
+// Package array provides fast immutable arrays. The elements in an array must have the same type.
+//
+// Arrays are implemented as relaxed radix balanced trees, a 32-way trie with a tail,
+// just like the Elm Array. Get, Set and Push all run in practically constant time.
+package array
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+)
+
+// The following constants define the shape of the tree.
+// Every node holds up to branchFactor children or elements.
+const (
+	branchFactor = 32
+	shiftStep    = 5
+	bitMask      = 0x1f
+)
+
+// Array is a representation of fast immutable arrays.
+type Array[T any] interface {
+	arr() *array[T]
+}
+
+/*
+Retrieve the internal tree
+*/
+func (a *array[T]) arr() *array[T] {
+	return a
+}
+
+/*
+The raw representation of an Array.
+
+  - length     - the number of elements in the array
+  - startShift - how many bits to shift the index to find the root position
+  - tree       - the nodes holding every element not in the tail
+  - tail       - the last (up to 32) elements, kept outside the tree for fast pushes
+*/
+type array[T any] struct {
+	length     int
+	startShift int
+	tree       []node[T]
+	tail       []T
+}
+
+// A node is either a subTree of more nodes or a leaf of elements.
+type node[T any] interface {
+	node_()
+}
+
+type subTree[T any] []node[T]
+
+type leaf[T any] []T
+
+func (subTree[T]) node_() {}
+
+func (leaf[T]) node_() {}
+
+// Used to build up an array from leaves in one go.
+type builder[T any] struct {
+	tail         []T
+	nodeList     []node[T]
+	nodeListSize int
+}
+
+// CREATE
+
+// Return an empty array.
+func Empty[T any]() Array[T] {
+	return &array[T]{length: 0, startShift: shiftStep, tree: []node[T]{}, tail: []T{}}
+}
+
+// Initialize an array. Initialize n f creates an array of length n with the element at index i initialized to the result of (f i).
+func Initialize[T any](n basics.Int, f func(basics.Int) T) Array[T] {
+	if n <= 0 {
+		return Empty[T]()
+	}
+	length := int(n)
+	tailLen := length % branchFactor
+	tail := initializeJs(tailLen, length-tailLen, f)
+	nodeList := []node[T]{}
+
+	for fromIndex := length - tailLen - branchFactor; fromIndex >= 0; fromIndex -= branchFactor {
+		nodeList = append(nodeList, leaf[T](initializeJs(branchFactor, fromIndex, f)))
+	}
+	// Leaves were collected from the highest index down.
+	reverseNodes(nodeList)
+
+	return builderToArray(&builder[T]{tail: tail, nodeList: nodeList, nodeListSize: length / branchFactor})
+}
+
+// Creates an array with a given length, filled with a default element.
+func Repeat[T any](n basics.Int, e T) Array[T] {
+	return Initialize(n, func(basics.Int) T { return e })
+}
+
+// Create an array from a List.
+func FromList[T any](xs list.List[T]) Array[T] {
+	if list.IsEmpty(xs) {
+		return Empty[T]()
+	}
+	b := &builder[T]{tail: []T{}, nodeList: []node[T]{}}
+
+	for ; xs.Cons() != nil; xs = xs.Cons().B {
+		b.tail = append(b.tail, xs.Cons().A)
+		if len(b.tail) == branchFactor {
+			b.nodeList = append(b.nodeList, leaf[T](b.tail))
+			b.nodeListSize++
+			b.tail = []T{}
+		}
+	}
+	return builderToArray(b)
+}
+
+// QUERY
+
+// Determine if an array is empty.
+func IsEmpty[T any](a Array[T]) bool {
+	return a.arr().length == 0
+}
+
+// Return the length of an array.
+func Length[T any](a Array[T]) basics.Int {
+	return basics.Int(a.arr().length)
+}
+
+// Return Just the element at the index or Nothing if the index is out of range.
+func Get[T any](index basics.Int, a Array[T]) maybe.Maybe[T] {
+	arr := a.arr()
+	i := int(index)
+
+	if i < 0 || i >= arr.length {
+		return maybe.Nothing{}
+	} else if i >= tailIndex(arr.length) {
+		return maybe.Just[T]{Value: arr.tail[i&bitMask]}
+	} else {
+		return maybe.Just[T]{Value: getHelp(arr.startShift, i, arr.tree)}
+	}
+}
+
+func getHelp[T any](shift int, index int, tree []node[T]) T {
+getHelpL:
+	for {
+		pos := (index >> shift) & bitMask
+		switch n := tree[pos].(type) {
+		case subTree[T]:
+			shift = shift - shiftStep
+			tree = n
+			continue getHelpL
+		case leaf[T]:
+			return n[index&bitMask]
+		default:
+			panic("unreachable")
+		}
+	}
+}
+
+// Given an array length, return the index of the first element in the tail.
+// Used to check if a given index references something in the tail.
+func tailIndex(length int) int {
+	return (length >> shiftStep) << shiftStep
+}
+
+// MANIPULATE
+
+// Set the element at a particular index. Returns an updated array.
+// If the index is out of range, the array is unaltered.
+func Set[T any](index basics.Int, value T, a Array[T]) Array[T] {
+	arr := a.arr()
+	i := int(index)
+
+	if i < 0 || i >= arr.length {
+		return a
+	} else if i >= tailIndex(arr.length) {
+		return &array[T]{
+			length:     arr.length,
+			startShift: arr.startShift,
+			tree:       arr.tree,
+			tail:       setJs(i&bitMask, value, arr.tail),
+		}
+	} else {
+		return &array[T]{
+			length:     arr.length,
+			startShift: arr.startShift,
+			tree:       setHelp(arr.startShift, i, value, arr.tree),
+			tail:       arr.tail,
+		}
+	}
+}
+
+func setHelp[T any](shift int, index int, value T, tree []node[T]) []node[T] {
+	pos := (index >> shift) & bitMask
+
+	switch n := tree[pos].(type) {
+	case subTree[T]:
+		newSub := setHelp(shift-shiftStep, index, value, n)
+		return setJs[node[T]](pos, subTree[T](newSub), tree)
+	case leaf[T]:
+		newLeaf := setJs(index&bitMask, value, n)
+		return setJs[node[T]](pos, leaf[T](newLeaf), tree)
+	default:
+		panic("unreachable")
+	}
+}
+
+// Push an element onto the end of an array.
+func Push[T any](a T, arr Array[T]) Array[T] {
+	return unsafeReplaceTail(pushJs(a, arr.arr().tail), arr.arr())
+}
+
+// Replaces the tail of an array. If the length of the tail equals the
+// branchFactor, it is inserted into the tree, and the tail is cleared.
+//
+// WARNING: For performance reasons, this function does not check if the new tail
+// has a length equal to or beneath the branchFactor. Make sure this is the case
+// before using this function.
+func unsafeReplaceTail[T any](newTail []T, arr *array[T]) *array[T] {
+	originalTailLen := len(arr.tail)
+	newTailLen := len(newTail)
+	newArrayLen := arr.length + (newTailLen - originalTailLen)
+
+	if newTailLen == branchFactor {
+		overflow := (newArrayLen >> shiftStep) > (1 << arr.startShift)
+
+		if overflow {
+			newShift := arr.startShift + shiftStep
+			newTree := insertTailInTree(newShift, arr.length, newTail, []node[T]{subTree[T](arr.tree)})
+			return &array[T]{length: newArrayLen, startShift: newShift, tree: newTree, tail: []T{}}
+		} else {
+			return &array[T]{
+				length:     newArrayLen,
+				startShift: arr.startShift,
+				tree:       insertTailInTree(arr.startShift, arr.length, newTail, arr.tree),
+				tail:       []T{},
+			}
+		}
+	} else {
+		return &array[T]{length: newArrayLen, startShift: arr.startShift, tree: arr.tree, tail: newTail}
+	}
+}
+
+func insertTailInTree[T any](shift int, index int, tail []T, tree []node[T]) []node[T] {
+	pos := (index >> shift) & bitMask
+
+	if pos >= len(tree) {
+		if shift == shiftStep {
+			return pushJs[node[T]](leaf[T](tail), tree)
+		} else {
+			newSub := subTree[T](insertTailInTree(shift-shiftStep, index, tail, []node[T]{}))
+			return pushJs[node[T]](newSub, tree)
+		}
+	} else {
+		switch n := tree[pos].(type) {
+		case subTree[T]:
+			newSub := subTree[T](insertTailInTree(shift-shiftStep, index, tail, n))
+			return setJs[node[T]](pos, newSub, tree)
+		case leaf[T]:
+			// Leaf is replaced with a SubTree
+			newSub := subTree[T](insertTailInTree(shift-shiftStep, index, tail, []node[T]{n}))
+			return setJs[node[T]](pos, newSub, tree)
+		default:
+			panic("unreachable")
+		}
+	}
+}
+
+// Append two arrays to a new one.
+func Append[T any](a Array[T], b Array[T]) Array[T] {
+	var foldHelper func(node[T], *array[T]) *array[T]
+	foldHelper = func(n node[T], acc *array[T]) *array[T] {
+		switch n := n.(type) {
+		case subTree[T]:
+			for _, sub := range n {
+				acc = foldHelper(sub, acc)
+			}
+			return acc
+		case leaf[T]:
+			return appendHelpTree(n, acc)
+		default:
+			panic("unreachable")
+		}
+	}
+	acc := a.arr()
+	for _, n := range b.arr().tree {
+		acc = foldHelper(n, acc)
+	}
+	return appendHelpTree(b.arr().tail, acc)
+}
+
+func appendHelpTree[T any](toAppend []T, arr *array[T]) *array[T] {
+	appended := appendN(branchFactor, arr.tail, toAppend)
+	itemsToAppend := len(toAppend)
+	notAppended := branchFactor - len(arr.tail) - itemsToAppend
+	newArray := unsafeReplaceTail(appended, arr)
+
+	if notAppended < 0 {
+		nextTail := toAppend[itemsToAppend+notAppended:]
+		return unsafeReplaceTail(copyJs(nextTail), newArray)
+	} else {
+		return newArray
+	}
+}
+
+func appendHelpBuilder[T any](tail []T, b *builder[T]) *builder[T] {
+	appended := appendN(branchFactor, b.tail, tail)
+	tailLen := len(tail)
+	notAppended := branchFactor - len(b.tail) - tailLen
+
+	if notAppended < 0 {
+		return &builder[T]{
+			tail:         copyJs(tail[tailLen+notAppended:]),
+			nodeList:     append(b.nodeList, leaf[T](appended)),
+			nodeListSize: b.nodeListSize + 1,
+		}
+	} else if notAppended == 0 {
+		return &builder[T]{
+			tail:         []T{},
+			nodeList:     append(b.nodeList, leaf[T](appended)),
+			nodeListSize: b.nodeListSize + 1,
+		}
+	} else {
+		return &builder[T]{tail: appended, nodeList: b.nodeList, nodeListSize: b.nodeListSize}
+	}
+}
+
+// Get a sub-section of an array: (slice start end array). The start is a zero-based index where we will start our slice.
+// The end is a zero-based index that indicates the end of the slice. The slice extracts up to but not including end.
+// Negative indexes are taken starting from the end of the array.
+func Slice[T any](from basics.Int, to basics.Int, a Array[T]) Array[T] {
+	arr := a.arr()
+	correctFrom := translateIndex(int(from), arr)
+	correctTo := translateIndex(int(to), arr)
+
+	if correctFrom > correctTo {
+		return Empty[T]()
+	} else {
+		return sliceLeft(correctFrom, sliceRight(correctTo, arr))
+	}
+}
+
+// Given a relative array index, convert it into an absolute one.
+func translateIndex[T any](index int, arr *array[T]) int {
+	posIndex := index
+	if index < 0 {
+		posIndex = arr.length + index
+	}
+
+	if posIndex < 0 {
+		return 0
+	} else if posIndex > arr.length {
+		return arr.length
+	} else {
+		return posIndex
+	}
+}
+
+// This function slices the tree from the right.
+//
+// First, two things are tested:
+//  1. If the array does not need slicing, return the original array.
+//  2. If the array can be sliced by only slicing the tail, slice the tail.
+//
+// Otherwise, we do the following:
+//  1. Find the new tail in the tree, promote it to the root tail position and slice it.
+//  2. Slice every sub tree.
+//  3. Promote subTrees until the tree has the correct height.
+func sliceRight[T any](end int, arr *array[T]) *array[T] {
+	if end == arr.length {
+		return arr
+	} else if end >= tailIndex(arr.length) {
+		return &array[T]{
+			length:     end,
+			startShift: arr.startShift,
+			tree:       arr.tree,
+			tail:       copyJs(arr.tail[:end&bitMask]),
+		}
+	} else {
+		endIdx := tailIndex(end)
+		depth := treeDepth(max(1, endIdx-1))
+		newShift := max(shiftStep, depth*shiftStep)
+
+		return &array[T]{
+			length:     end,
+			startShift: newShift,
+			tree:       hoistTree(arr.startShift, newShift, sliceTree(arr.startShift, endIdx, arr.tree)),
+			tail:       fetchNewTail(arr.startShift, end, endIdx, arr.tree),
+		}
+	}
+}
+
+// Slice and return the Leaf node after what is to be the last node in the sliced tree.
+func fetchNewTail[T any](shift int, end int, treeEnd int, tree []node[T]) []T {
+fetchNewTailL:
+	for {
+		pos := (treeEnd >> shift) & bitMask
+
+		switch n := tree[pos].(type) {
+		case subTree[T]:
+			shift = shift - shiftStep
+			tree = n
+			continue fetchNewTailL
+		case leaf[T]:
+			return copyJs(n[:end&bitMask])
+		default:
+			panic("unreachable")
+		}
+	}
+}
+
+// Shorten the root Node of the tree so it is long enough to contain the Node
+// indicated by endIdx. Then recursively perform the same operation to the last
+// node of each SubTree.
+func sliceTree[T any](shift int, endIdx int, tree []node[T]) []node[T] {
+	lastPos := (endIdx >> shift) & bitMask
+
+	switch n := tree[lastPos].(type) {
+	case subTree[T]:
+		newSub := sliceTree(shift-shiftStep, endIdx, n)
+		if len(newSub) == 0 {
+			// The sub is empty, slice it away
+			return copyJs(tree[:lastPos])
+		} else {
+			return setJs[node[T]](lastPos, subTree[T](newSub), copyJs(tree[:lastPos+1]))
+		}
+	case leaf[T]:
+		// This is supposed to be the new tail. Fetched by fetchNewTail.
+		// Slice up to, but not including, this point.
+		return copyJs(tree[:lastPos])
+	default:
+		panic("unreachable")
+	}
+}
+
+// The tree is supposed to be of a certain depth. Since slicing removes
+// elements, it could be that the tree should have a smaller depth
+// than it had originally. This function shortens the height if it is necessary
+// to do so.
+func hoistTree[T any](oldShift int, newShift int, tree []node[T]) []node[T] {
+hoistTreeL:
+	for {
+		if oldShift <= newShift || len(tree) == 0 {
+			return tree
+		} else {
+			switch n := tree[0].(type) {
+			case subTree[T]:
+				oldShift = oldShift - shiftStep
+				tree = n
+				continue hoistTreeL
+			default:
+				return tree
+			}
+		}
+	}
+}
+
+// This function slices the tree from the left. Such an operation will change
+// the index of every element after the slice. Which means that we will have to
+// rebuild the array.
+//
+// First, two things are tested:
+//  1. If the array does not need slicing, return the original array.
+//  2. If the slice removes every element but those in the tail, slice the tail and
+//     set the tree to the empty array.
+//
+// Otherwise, we do the following:
+//  1. basics.Add every leaf node in the tree to a list.
+//  2. Drop the nodes which are supposed to be sliced away.
+//  3. Slice the head node of the list, which represents the start of the new array.
+//  4. Create a builder with the tail set as the node from the previous step.
+//  5. Append the remaining nodes into this builder, and create the array.
+func sliceLeft[T any](from int, arr *array[T]) Array[T] {
+	if from == 0 {
+		return arr
+	} else if from >= tailIndex(arr.length) {
+		return &array[T]{
+			length:     arr.length - from,
+			startShift: shiftStep,
+			tree:       []node[T]{},
+			tail:       copyJs(arr.tail[from-tailIndex(arr.length):]),
+		}
+	} else {
+		leafNodes := append(leaves(arr.tree), arr.tail)
+		skipNodes := from / branchFactor
+		nodesToInsert := leafNodes[skipNodes:]
+
+		if len(nodesToInsert) == 0 {
+			return Empty[T]()
+		}
+		head := nodesToInsert[0]
+		firstSlice := from - (skipNodes * branchFactor)
+		b := &builder[T]{tail: copyJs(head[firstSlice:]), nodeList: []node[T]{}, nodeListSize: 0}
+
+		for _, l := range nodesToInsert[1:] {
+			b = appendHelpBuilder(l, b)
+		}
+		return builderToArray(b)
+	}
+}
+
+// Collect every leaf in the tree from left to right.
+func leaves[T any](tree []node[T]) [][]T {
+	acc := [][]T{}
+	var helper func([]node[T])
+	helper = func(tree []node[T]) {
+		for _, n := range tree {
+			switch n := n.(type) {
+			case subTree[T]:
+				helper(n)
+			case leaf[T]:
+				acc = append(acc, n)
+			}
+		}
+	}
+	helper(tree)
+	return acc
+}
+
+// Construct an array with the information in a given builder.
+//
+// Due to the nature of the builder, the list of leaves is always built in the
+// order they appear in the array.
+func builderToArray[T any](b *builder[T]) Array[T] {
+	if b.nodeListSize == 0 {
+		return &array[T]{length: len(b.tail), startShift: shiftStep, tree: []node[T]{}, tail: b.tail}
+	} else {
+		treeLen := b.nodeListSize * branchFactor
+		depth := treeDepth(treeLen - 1)
+		tree := treeFromBuilder(b.nodeList, b.nodeListSize)
+
+		return &array[T]{
+			length:     len(b.tail) + treeLen,
+			startShift: max(shiftStep, depth*shiftStep),
+			tree:       tree,
+			tail:       b.tail,
+		}
+	}
+}
+
+// Takes a list of leaves and an basics.Int specifying how many leaves there are,
+// and builds a tree structure to be used in an Array.
+func treeFromBuilder[T any](nodeList []node[T], nodeListSize int) []node[T] {
+treeFromBuilderL:
+	for {
+		newNodeSize := (nodeListSize + branchFactor - 1) / branchFactor
+
+		if newNodeSize == 1 {
+			return nodeList
+		} else {
+			nodeList = compressNodes(nodeList)
+			nodeListSize = newNodeSize
+			continue treeFromBuilderL
+		}
+	}
+}
+
+// Takes a list of nodes and return a list of SubTrees containing those nodes.
+func compressNodes[T any](nodes []node[T]) []node[T] {
+	acc := []node[T]{}
+
+	for len(nodes) > 0 {
+		n := min(branchFactor, len(nodes))
+		acc = append(acc, subTree[T](nodes[:n:n]))
+		nodes = nodes[n:]
+	}
+	return acc
+}
+
+// The floor of the base 32 logarithm of n.
+func treeDepth(n int) int {
+	depth := 0
+	for n >= branchFactor {
+		n = n >> shiftStep
+		depth++
+	}
+	return depth
+}
+
+// LISTS
+
+// Create a list of elements from an array.
+func ToList[T any](a Array[T]) list.List[T] {
+	return Foldr(list.Cons[T], list.Empty[T](), a)
+}
+
+// Create an indexed list from an array. Each element of the array will be paired with its index.
+func ToIndexedList[T any](a Array[T]) list.List[tuple.Tuple2[basics.Int, T]] {
+	helper := func(entry T, acc tuple.Tuple2[basics.Int, list.List[tuple.Tuple2[basics.Int, T]]]) tuple.Tuple2[basics.Int, list.List[tuple.Tuple2[basics.Int, T]]] {
+		index := tuple.First(acc)
+		return tuple.Pair(index-1, list.Cons(tuple.Pair(index, entry), tuple.Second(acc)))
+	}
+	return tuple.Second(Foldr(helper, tuple.Pair(Length(a)-1, list.Empty[tuple.Tuple2[basics.Int, T]]()), a))
+}
+
+// TRANSFORM
+
+// Apply a function on every element in an array.
+func Map[A, B any](f func(A) B, a Array[A]) Array[B] {
+	var helper func(node[A]) node[B]
+	helper = func(n node[A]) node[B] {
+		switch n := n.(type) {
+		case subTree[A]:
+			return subTree[B](mapJs(helper, n))
+		case leaf[A]:
+			return leaf[B](mapJs(f, n))
+		default:
+			panic("unreachable")
+		}
+	}
+	arr := a.arr()
+
+	return &array[B]{
+		length:     arr.length,
+		startShift: arr.startShift,
+		tree:       mapJs(helper, arr.tree),
+		tail:       mapJs(f, arr.tail),
+	}
+}
+
+// Apply a function on every element with its index as first argument.
+func IndexedMap[A, B any](f func(basics.Int, A) B, a Array[A]) Array[B] {
+	arr := a.arr()
+	b := &builder[B]{
+		tail:         indexedMapJs(f, tailIndex(arr.length), arr.tail),
+		nodeList:     []node[B]{},
+		nodeListSize: 0,
+	}
+	for _, l := range leaves(arr.tree) {
+		offset := b.nodeListSize * branchFactor
+		b.nodeList = append(b.nodeList, leaf[B](indexedMapJs(f, offset, l)))
+		b.nodeListSize++
+	}
+	return builderToArray(b)
+}
+
+// Reduce an array from the left.
+func Foldl[A, B any](f func(A, B) B, acc B, a Array[A]) B {
+	var helper func(node[A], B) B
+	helper = func(n node[A], acc B) B {
+		switch n := n.(type) {
+		case subTree[A]:
+			return foldlJs(helper, acc, n)
+		case leaf[A]:
+			return foldlJs(f, acc, n)
+		default:
+			panic("unreachable")
+		}
+	}
+	arr := a.arr()
+	return foldlJs(f, foldlJs(helper, acc, arr.tree), arr.tail)
+}
+
+// Reduce an array from the right.
+func Foldr[A, B any](f func(A, B) B, acc B, a Array[A]) B {
+	var helper func(node[A], B) B
+	helper = func(n node[A], acc B) B {
+		switch n := n.(type) {
+		case subTree[A]:
+			return foldrJs(helper, acc, n)
+		case leaf[A]:
+			return foldrJs(f, acc, n)
+		default:
+			panic("unreachable")
+		}
+	}
+	arr := a.arr()
+	return foldrJs(helper, foldrJs(f, acc, arr.tail), arr.tree)
+}
+
+// Keep elements that pass the test.
+func Filter[T any](isGood func(T) bool, a Array[T]) Array[T] {
+	return FromList(
+		Foldr(func(x T, xs list.List[T]) list.List[T] {
+			if isGood(x) {
+				return list.Cons(x, xs)
+			} else {
+				return xs
+			}
+		}, list.Empty[T](), a),
+	)
+}
+
+// Slice helpers
+//
+// These mirror the JsArray kernel functions in Elm. Every function returns a
+// new slice and never mutates its input, which is what makes structural sharing safe.
+
+func initializeJs[T any](size int, offset int, f func(basics.Int) T) []T {
+	result := make([]T, size)
+	for i := 0; i < size; i++ {
+		result[i] = f(basics.Int(offset + i))
+	}
+	return result
+}
+
+func copyJs[T any](xs []T) []T {
+	result := make([]T, len(xs))
+	copy(result, xs)
+	return result
+}
+
+func setJs[T any](index int, value T, xs []T) []T {
+	result := copyJs(xs)
+	result[index] = value
+	return result
+}
+
+func pushJs[T any](value T, xs []T) []T {
+	result := make([]T, len(xs), len(xs)+1)
+	copy(result, xs)
+	return append(result, value)
+}
+
+// Appends up to n elements of ys onto xs.
+func appendN[T any](n int, xs []T, ys []T) []T {
+	take := min(n-len(xs), len(ys))
+	result := make([]T, len(xs), len(xs)+take)
+	copy(result, xs)
+	return append(result, ys[:take]...)
+}
+
+func mapJs[A, B any](f func(A) B, xs []A) []B {
+	result := make([]B, len(xs))
+	for i, x := range xs {
+		result[i] = f(x)
+	}
+	return result
+}
+
+func indexedMapJs[A, B any](f func(basics.Int, A) B, offset int, xs []A) []B {
+	result := make([]B, len(xs))
+	for i, x := range xs {
+		result[i] = f(basics.Int(offset+i), x)
+	}
+	return result
+}
+
+func foldlJs[A, B any](f func(A, B) B, acc B, xs []A) B {
+	for _, x := range xs {
+		acc = f(x, acc)
+	}
+	return acc
+}
+
+func foldrJs[A, B any](f func(A, B) B, acc B, xs []A) B {
+	for i := len(xs) - 1; i >= 0; i-- {
+		acc = f(xs[i], acc)
+	}
+	return acc
+}
+
+func reverseNodes[T any](xs []node[T]) {
+	for i, j := 0, len(xs)-1; i < j; i, j = i+1, j-1 {
+		xs[i], xs[j] = xs[j], xs[i]
+	}
+}
@@ -0,0 +1,218 @@
+package array
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func rangeSlice(lo, hi int) []basics.Int {
+	xs := []basics.Int{}
+	for i := lo; i <= hi; i++ {
+		xs = append(xs, basics.Int(i))
+	}
+	return xs
+}
+
+func toSlice[T any](a Array[T]) []T {
+	return list.ToSlice(ToList(a))
+}
+
+func TestCreate(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Empty", func(t *testing.T) {
+		asserts.Equal(basics.Int(0), Length(Empty[basics.Int]()))
+		asserts.True(IsEmpty(Empty[basics.Int]()))
+	})
+	t.Run("Initialize", func(t *testing.T) {
+		SUT := Initialize(4, basics.Identity[basics.Int])
+
+		asserts.Equal([]basics.Int{0, 1, 2, 3}, toSlice(SUT))
+	})
+	t.Run("Initialize with negative length", func(t *testing.T) {
+		asserts.Equal(Empty[basics.Int](), Initialize(-2, basics.Identity[basics.Int]))
+	})
+	t.Run("Initialize spanning many levels", func(t *testing.T) {
+		for _, n := range []int{31, 32, 33, 1024, 1056, 1057, 40000} {
+			SUT := Initialize(basics.Int(n), basics.Identity[basics.Int])
+
+			asserts.Equal(basics.Int(n), Length(SUT))
+			asserts.Equal(rangeSlice(0, n-1), toSlice(SUT))
+		}
+	})
+	t.Run("Repeat", func(t *testing.T) {
+		asserts.Equal([]basics.Int{0, 0, 0}, toSlice(Repeat[basics.Int](3, 0)))
+	})
+	t.Run("FromList", func(t *testing.T) {
+		for _, n := range []int{0, 1, 32, 33, 1024, 1057, 40000} {
+			SUT := FromList(list.FromSlice(rangeSlice(1, n)))
+
+			asserts.Equal(basics.Int(n), Length(SUT))
+			asserts.Equal(rangeSlice(1, n), toSlice(SUT))
+		}
+	})
+	t.Run("FromList and Initialize agree", func(t *testing.T) {
+		asserts.Equal(Initialize(3000, basics.Identity[basics.Int]), FromList(list.Range(0, 2999)))
+	})
+}
+
+func TestQuery(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Get", func(t *testing.T) {
+		SUT := FromList(list.FromSlice([]basics.Int{0, 1, 2}))
+
+		asserts.Equal(maybe.Just[basics.Int]{Value: 0}, Get(0, SUT))
+		asserts.Equal(maybe.Just[basics.Int]{Value: 2}, Get(2, SUT))
+		asserts.Equal(maybe.Nothing{}, Get(5, SUT))
+		asserts.Equal(maybe.Nothing{}, Get(-1, SUT))
+	})
+	t.Run("Get from tree and tail", func(t *testing.T) {
+		SUT := Initialize(40000, basics.Identity[basics.Int])
+
+		for _, i := range []basics.Int{0, 31, 32, 1023, 1024, 32767, 32768, 39999} {
+			asserts.Equal(maybe.Just[basics.Int]{Value: i}, Get(i, SUT))
+		}
+		asserts.Equal(maybe.Nothing{}, Get(40000, SUT))
+	})
+}
+
+func TestManipulate(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Set", func(t *testing.T) {
+		SUT := FromList(list.FromSlice([]basics.Int{1, 2, 3}))
+
+		asserts.Equal([]basics.Int{1, 7, 3}, toSlice(Set(1, 7, SUT)))
+		asserts.Equal([]basics.Int{1, 2, 3}, toSlice(Set(3, 7, SUT)))
+		asserts.Equal([]basics.Int{1, 2, 3}, toSlice(SUT))
+	})
+	t.Run("Set in tree keeps original intact", func(t *testing.T) {
+		original := Initialize(5000, basics.Identity[basics.Int])
+		SUT := Set(1500, -1, original)
+
+		asserts.Equal(maybe.Just[basics.Int]{Value: -1}, Get(1500, SUT))
+		asserts.Equal(maybe.Just[basics.Int]{Value: 1500}, Get(1500, original))
+	})
+	t.Run("Push", func(t *testing.T) {
+		asserts.Equal([]basics.Int{1, 2, 3}, toSlice(Push(3, FromList(list.FromSlice([]basics.Int{1, 2})))))
+	})
+	t.Run("Push past many levels", func(t *testing.T) {
+		SUT := Empty[basics.Int]()
+		for i := 0; i < 40000; i++ {
+			SUT = Push(basics.Int(i), SUT)
+		}
+
+		asserts.Equal(Initialize(40000, basics.Identity[basics.Int]), SUT)
+	})
+	t.Run("Push keeps original intact", func(t *testing.T) {
+		original := Initialize(32, basics.Identity[basics.Int])
+		SUT := Push(32, original)
+
+		asserts.Equal(basics.Int(32), Length(original))
+		asserts.Equal(rangeSlice(0, 31), toSlice(original))
+		asserts.Equal(rangeSlice(0, 32), toSlice(SUT))
+	})
+	t.Run("Append", func(t *testing.T) {
+		asserts.Equal(
+			[]basics.Int{1, 1, 2, 2, 2},
+			toSlice(Append(Repeat[basics.Int](2, 1), Repeat[basics.Int](3, 2))),
+		)
+	})
+	t.Run("Append large arrays", func(t *testing.T) {
+		for _, sizes := range [][2]int{{0, 40}, {31, 1}, {33, 1000}, {1000, 33}, {1056, 1057}, {5000, 5000}} {
+			a := Initialize(basics.Int(sizes[0]), basics.Identity[basics.Int])
+			b := Initialize(basics.Int(sizes[1]), func(i basics.Int) basics.Int { return i + basics.Int(sizes[0]) })
+
+			asserts.Equal(Initialize(basics.Int(sizes[0]+sizes[1]), basics.Identity[basics.Int]), Append(a, b))
+		}
+	})
+	t.Run("Slice", func(t *testing.T) {
+		SUT := FromList(list.FromSlice([]basics.Int{0, 1, 2, 3, 4}))
+
+		asserts.Equal([]basics.Int{0, 1, 2}, toSlice(Slice(0, 3, SUT)))
+		asserts.Equal([]basics.Int{1, 2, 3}, toSlice(Slice(1, 4, SUT)))
+		asserts.Equal([]basics.Int{1, 2, 3}, toSlice(Slice(1, -1, SUT)))
+		asserts.Equal([]basics.Int{2}, toSlice(Slice(-3, -2, SUT)))
+		asserts.Equal([]basics.Int{}, toSlice(Slice(4, 1, SUT)))
+	})
+	t.Run("Slice large arrays", func(t *testing.T) {
+		n := 40000
+		SUT := Initialize(basics.Int(n), basics.Identity[basics.Int])
+		bounds := [][2]int{{0, n}, {0, 1}, {0, 32}, {0, 33}, {1, 1057}, {31, 1025}, {1024, 33000}, {32767, 32769}, {39990, n}, {100, 36000}}
+
+		for _, b := range bounds {
+			sliced := Slice(basics.Int(b[0]), basics.Int(b[1]), SUT)
+
+			asserts.Equal(basics.Int(b[1]-b[0]), Length(sliced))
+			asserts.Equal(rangeSlice(b[0], b[1]-1), toSlice(sliced))
+			// A sliced array must still behave like any other array
+			asserts.Equal(Initialize(basics.Int(b[1]-b[0]), func(i basics.Int) basics.Int { return i + basics.Int(b[0]) }), sliced)
+		}
+	})
+	t.Run("Push onto sliced array", func(t *testing.T) {
+		SUT := Push(-1, Slice(10, 1100, Initialize(2000, basics.Identity[basics.Int])))
+
+		asserts.Equal(append(rangeSlice(10, 1099), -1), toSlice(SUT))
+	})
+}
+
+func TestLists(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("ToList", func(t *testing.T) {
+		asserts.Equal(list.FromSlice([]basics.Int{3, 2, 1}), ToList(FromList(list.FromSlice([]basics.Int{3, 2, 1}))))
+	})
+	t.Run("ToIndexedList", func(t *testing.T) {
+		SUT := ToIndexedList(FromList(list.FromSlice([]string{"cat", "dog"})))
+
+		asserts.Equal(
+			list.FromSlice([]tuple.Tuple2[basics.Int, string]{tuple.Pair[basics.Int](0, "cat"), tuple.Pair[basics.Int](1, "dog")}),
+			SUT,
+		)
+	})
+}
+
+func TestTransform(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Map", func(t *testing.T) {
+		SUT := Map(basics.Sqrt, FromList(list.FromSlice([]basics.Float{1, 4, 9})))
+
+		asserts.Equal([]basics.Float{1, 2, 3}, toSlice(SUT))
+	})
+	t.Run("Map large array", func(t *testing.T) {
+		SUT := Map(func(i basics.Int) basics.Int { return i * 2 }, Initialize(3000, basics.Identity[basics.Int]))
+
+		asserts.Equal(Initialize(3000, func(i basics.Int) basics.Int { return i * 2 }), SUT)
+	})
+	t.Run("IndexedMap", func(t *testing.T) {
+		SUT := IndexedMap(basics.Mul[basics.Int], FromList(list.FromSlice([]basics.Int{5, 5, 5})))
+
+		asserts.Equal([]basics.Int{0, 5, 10}, toSlice(SUT))
+	})
+	t.Run("IndexedMap large array", func(t *testing.T) {
+		SUT := IndexedMap(func(i basics.Int, _ basics.Int) basics.Int { return i }, Repeat[basics.Int](3000, 0))
+
+		asserts.Equal(Initialize(3000, basics.Identity[basics.Int]), SUT)
+	})
+	t.Run("Foldl", func(t *testing.T) {
+		SUT := Foldl(list.Cons[basics.Int], list.Empty[basics.Int](), FromList(list.FromSlice([]basics.Int{1, 2, 3})))
+
+		asserts.Equal(list.FromSlice([]basics.Int{3, 2, 1}), SUT)
+	})
+	t.Run("Foldr", func(t *testing.T) {
+		asserts.Equal(basics.Int(6), Foldr(basics.Add[basics.Int], 0, FromList(list.FromSlice([]basics.Int{1, 2, 3}))))
+		asserts.Equal(basics.Int(499500), Foldr(basics.Add[basics.Int], 0, Initialize(1000, basics.Identity[basics.Int])))
+	})
+	t.Run("Filter", func(t *testing.T) {
+		isEven := func(i basics.Int) bool { return basics.ModBy(2, i) == 0 }
+		SUT := Filter(isEven, Initialize(7, basics.Identity[basics.Int]))
+
+		asserts.Equal([]basics.Int{0, 2, 4, 6}, toSlice(SUT))
+	})
+}
@@ -0,0 +1,50 @@
+package array
+
+import (
+	"fmt"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+)
+
+func ExampleInitialize() {
+	Initialize(4, basics.Identity[basics.Int])                                              // [0,1,2,3]
+	Initialize(4, func(n basics.Int) basics.Int { return n * n })                           // [0,1,4,9]
+	Initialize(4, func(n basics.Int) basics.Int { return basics.Always[basics.Int](0, n) }) // [0,0,0,0]
+}
+
+func ExampleRepeat() {
+	Repeat(5, 0) // [0,0,0,0,0]
+}
+
+func ExampleLength() {
+	fmt.Println(Length(FromList(list.FromSlice([]basics.Int{1, 2, 3}))))
+
+	// Output:
+	// 3
+}
+
+func ExampleGet() {
+	Get(0, FromList(list.FromSlice([]basics.Int{0, 1, 2}))) // Just 0
+	Get(5, FromList(list.FromSlice([]basics.Int{0, 1, 2}))) // Nothing
+}
+
+func ExampleSet() {
+	Set(1, 7, FromList(list.FromSlice([]basics.Int{1, 2, 3}))) // [1,7,3]
+}
+
+func ExamplePush() {
+	Push(3, FromList(list.FromSlice([]basics.Int{1, 2}))) // [1,2,3]
+}
+
+func ExampleSlice() {
+	arr := FromList(list.FromSlice([]basics.Int{0, 1, 2, 3, 4}))
+
+	Slice(0, 3, arr)   // [0,1,2]
+	Slice(1, 4, arr)   // [1,2,3]
+	Slice(1, -1, arr)  // [1,2,3]
+	Slice(-3, -2, arr) // [2]
+}
+
+func ExampleToIndexedList() {
+	ToIndexedList(FromList(list.FromSlice([]string{"cat", "dog"}))) // [(0,"cat"), (1,"dog")]
+}
@@ -3,16 +3,31 @@ package basics
 
 import (
 	"cmp"
+	"encoding/json"
+	"github.com/Confidenceman02/scion-tools/pkg/internal/hashing"
 	"math"
 	"reflect"
+	"strconv"
 )
 
 type Number interface {
-	Int | Float
+	Int | Float | Int64 | Float64
+}
+
+// Floating point number types, Float is 32-bit and Float64 is 64-bit.
+type Floating interface {
+	Float | Float64
+}
+
+// Integer number types, Int is platform sized and Int64 is always 64-bit.
+type Integer interface {
+	Int | Int64
 }
 
 type Int int
 type Float float32
+type Int64 int64
+type Float64 float64
 
 func (i Int) Cmp(y Comparable[Int]) int {
 	return cmp.Compare(i, y.T())
@@ -20,12 +35,48 @@ func (i Int) Cmp(y Comparable[Int]) int {
 func (i Float) Cmp(y Comparable[Float]) int {
 	return cmp.Compare(i, y.T())
 }
+func (i Int64) Cmp(y Comparable[Int64]) int {
+	return cmp.Compare(i, y.T())
+}
+func (i Float64) Cmp(y Comparable[Float64]) int {
+	return cmp.Compare(i, y.T())
+}
 func (i Int) T() Int {
 	return i
 }
 func (i Float) T() Float {
 	return i
 }
+func (i Int64) T() Int64 {
+	return i
+}
+func (i Float64) T() Float64 {
+	return i
+}
+func (i Int) Equal(y Int) bool {
+	return i == y
+}
+func (i Float) Equal(y Float) bool {
+	return i == y
+}
+func (i Int64) Equal(y Int64) bool {
+	return i == y
+}
+func (i Float64) Equal(y Float64) bool {
+	return i == y
+}
+func (i Int) Hash() uint64 {
+	return hashing.Uint64(uint64(i))
+}
+func (i Float) Hash() uint64 {
+	return hashing.Float64(float64(i))
+}
+func (i Int64) Hash() uint64 {
+	return hashing.Uint64(uint64(i))
+}
+func (i Float64) Hash() uint64 {
+	return hashing.Float64(float64(i))
+}
 
 type Comparable[T any] interface {
 	Cmp(Comparable[T]) int
@@ -37,6 +88,92 @@ type Appendable[T any] interface {
 	T() T
 }
 
+// Equatable values know when they are the same as another value, which lets Eq compare
+// them by what they hold instead of how they are laid out in memory.
+// A type that is Equatable has to be Hashable too, so that Hash agrees with Eq.
+type Equatable[T any] interface {
+	Equal(T) bool
+}
+
+// Hashable values can be hashed, and values that are Equal always have the same hash.
+// Hashes are only stable within one process.
+type Hashable interface {
+	Hash() uint64
+}
+
+// Native wraps a value of a native Go ordered type, like int, string or time.Duration, to make it Comparable.
+// It has the same size as the value it wraps, so it can be a key of a Dict or a value of a Set.
+type Native[T cmp.Ordered] struct {
+	Value T
+}
+
+// Wrap a native Go ordered value to make it Comparable.
+func Wrap[T cmp.Ordered](v T) Native[T] {
+	return Native[T]{Value: v}
+}
+
+func (o Native[T]) Cmp(y Comparable[Native[T]]) int {
+	return cmp.Compare(o.Value, y.T().Value)
+}
+func (o Native[T]) T() Native[T] {
+	return o
+}
+
+// Native values are equal when Cmp says they are, so NaN is equal to NaN.
+func (o Native[T]) Equal(y Native[T]) bool {
+	return cmp.Compare(o.Value, y.Value) == 0
+}
+func (o Native[T]) Hash() uint64 {
+	if o.Value != o.Value {
+		// Only NaN is not equal to itself, and every NaN is equal by Cmp
+		return hashing.Float64(math.NaN())
+	}
+	return hashValue(reflect.ValueOf(o.Value), nil)
+}
+
+// Native values are written to JSON as the value they wrap.
+func (o Native[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Value)
+}
+func (o *Native[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &o.Value)
+}
+
+// Native values can be written as text, so a dictionary keyed by them is a JSON object.
+func (o Native[T]) MarshalText() ([]byte, error) {
+	v := reflect.ValueOf(o.Value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(nil, v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(nil, v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(nil, v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return []byte(v.String()), nil
+	}
+}
+func (o *Native[T]) UnmarshalText(text []byte) error {
+	v := reflect.ValueOf(&o.Value).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(string(text), 10, v.Type().Bits())
+		v.SetInt(n)
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(string(text), 10, v.Type().Bits())
+		v.SetUint(n)
+		return err
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(string(text), v.Type().Bits())
+		v.SetFloat(f)
+		return err
+	default:
+		v.SetString(string(text))
+		return nil
+	}
+}
+
 // Math
 
 // Add two numbers. The number type variable means this operation can be specialized to any Number type.
@@ -55,8 +192,37 @@ func Mul[T Number](a, b T) T {
 }
 
 // Floating-point division:
-func Fdiv(a Float, b Float) Float {
-	return Float(float32(a) / float32(b))
+func Fdiv[F Floating](a F, b F) F {
+	return a / b
+}
+
+// Integer division, the remainder is discarded. Division by zero results in zero, like Elm.
+func IntDiv[I Integer](a I, b I) I {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+// Exponentiation
+func Pow[T Number](base T, exponent T) T {
+	switch any(base).(type) {
+	case Int, Int64:
+		if exponent < 0 {
+			break
+		}
+		// exponentiation by squaring keeps full Int precision
+		result := T(1)
+		for exponent > 0 {
+			if Int64(exponent)%2 == 1 {
+				result *= base
+			}
+			base *= base
+			exponent = T(Int64(exponent) / 2)
+		}
+		return result
+	}
+	return T(math.Pow(float64(base), float64(exponent)))
 }
 
 // Int to Float / Float to Int
@@ -66,36 +232,122 @@ func ToFloat[T Int](x T) Float {
 	return Float(x)
 }
 
+// ToFloat64 - Convert an integer into a 64-bit float. Useful when mixing Int or Int64 and Float64 values.
+func ToFloat64[T Integer](x T) Float64 {
+	return Float64(x)
+}
+
 // Round a number to the nearest integer.
-func Round(x Float) Int {
-	return Int(math.Round(float64(x)))
+// The integer type comes first, so Round[Int](x) gives an Int and Round[Int64](x) an Int64.
+func Round[I Integer, F Floating](x F) I {
+	return I(math.Round(float64(x)))
 }
 
 // Floor function, rounding down.
-func Floor(x Float) Int {
-	return Int(math.Floor(float64(x)))
+func Floor[I Integer, F Floating](x F) I {
+	return I(math.Floor(float64(x)))
 }
 
 // Ceiling function, rounding up.
-func Ceiling(x Float) Int {
-	return Int(math.Ceil(float64(x)))
+func Ceiling[I Integer, F Floating](x F) I {
+	return I(math.Ceil(float64(x)))
 }
 
 // Truncate a number, rounding towards zero
-func Truncate(x Float) Int {
-	return Int(math.Trunc(float64(x)))
+func Truncate[I Integer, F Floating](x F) I {
+	return I(math.Trunc(float64(x)))
 }
 
 // EQUALITY
 
 // Check if values are structurally the same.
+// Values that are Equatable decide for themselves, so two dictionaries with the same key-value pairs
+// are the same even when their trees are shaped differently. Other values are compared with reflect.DeepEqual.
 func Eq[T any](x, y T) bool {
-	switch reflect.TypeOf(x).Kind() {
-	case reflect.Func:
+	if e, ok := any(x).(Equatable[T]); ok {
+		return e.Equal(y)
+	}
+	if t := reflect.TypeOf(x); t != nil && t.Kind() == reflect.Func {
 		// mimic Elm's behavior
 		panic("Can't compare functions")
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// Hash a value, so that values that are the same according to Eq have the same hash.
+// Values that are Hashable hash themselves, and other values are hashed by walking them with reflection.
+// Hash panics on a value that is Equatable but not Hashable, because walking it could give equal values different hashes.
+func Hash[T any](x T) uint64 {
+	switch h := any(x).(type) {
+	case Hashable:
+		return h.Hash()
+	case Equatable[T]:
+		panic("basics: cannot hash " + reflect.TypeOf(x).String() + ", which is Equatable but not Hashable")
+	}
+	return hashValue(reflect.ValueOf(x), map[uintptr]bool{})
+}
+
+// Hash a value the way reflect.DeepEqual compares it. A pointer back to a value that is still
+// being hashed counts as 0, so that cyclic values end.
+func hashValue(v reflect.Value, seen map[uintptr]bool) uint64 {
+	if !v.IsValid() {
+		return 0
+	}
+	if v.CanInterface() {
+		if h, ok := v.Interface().(Hashable); ok {
+			return h.Hash()
+		}
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return hashing.Uint64(1)
+		}
+		return hashing.Uint64(0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return hashing.Uint64(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return hashing.Uint64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return hashing.Float64(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return hashing.Combine(hashing.Float64(real(c)), hashing.Float64(imag(c)))
+	case reflect.String:
+		return hashing.String(v.String())
+	case reflect.Array, reflect.Slice:
+		h := hashing.Uint64(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			h = hashing.Combine(h, hashValue(v.Index(i), seen))
+		}
+		return h
+	case reflect.Struct:
+		h := hashing.Uint64(uint64(v.NumField()))
+		for i := 0; i < v.NumField(); i++ {
+			h = hashing.Combine(h, hashValue(v.Field(i), seen))
+		}
+		return h
+	case reflect.Map:
+		// Map entries have no order, so their hashes are added up
+		h := hashing.Uint64(uint64(v.Len()))
+		for it := v.MapRange(); it.Next(); {
+			h += hashing.Combine(hashValue(it.Key(), seen), hashValue(it.Value(), seen))
+		}
+		return h
+	case reflect.Pointer:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		defer delete(seen, v.Pointer())
+		return hashValue(v.Elem(), seen)
+	case reflect.Interface:
+		return hashValue(v.Elem(), seen)
+	case reflect.Chan, reflect.UnsafePointer:
+		return hashing.Uint64(uint64(v.Pointer()))
 	default:
-		return reflect.DeepEqual(x, y)
+		// Functions are only equal when they are both nil
+		return 0
 	}
 }
 
@@ -184,6 +436,11 @@ func Not(pred bool) bool {
 	return !pred
 }
 
+// The exclusive-or operator. True if exactly one input is True.
+func Xor(a bool, b bool) bool {
+	return a != b
+}
+
 // Append Strings and Lists
 
 // Put two appendable things together. This includes strings and lists.
@@ -195,17 +452,26 @@ func Append[T any](a Appendable[T], b Appendable[T]) Appendable[T] {
 
 // Perform modular arithmetic.
 // A common trick is to use (n mod 2) to detect even and odd numbers:
-func ModBy(modulus Int, x Int) Int {
-	answer := math.Mod(float64(x), float64(modulus))
+func ModBy[I Integer](modulus I, x I) I {
 	if modulus == 0 {
 		panic("ModBy: modulus cannot be zero")
 	}
+	answer := x % modulus
 
 	if (answer > 0 && modulus < 0) || (answer < 0 && modulus > 0) {
-		return Int(answer) + modulus
+		return answer + modulus
 	} else {
-		return Int(answer)
+		return answer
+	}
+}
+
+// Get the remainder after division.
+// The sign of the answer follows the dividend, which is different to ModBy.
+func RemainderBy[I Integer](divisor I, x I) I {
+	if divisor == 0 {
+		panic("RemainderBy: divisor cannot be zero")
 	}
+	return x % divisor
 }
 
 // Negate a number.
@@ -213,9 +479,115 @@ func Negate[A Number](n A) A {
 	return -n
 }
 
+// Get the absolute value of a number.
+func Abs[A Number](n A) A {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Clamps a number within a given range.
+func Clamp[A Number](low A, high A, n A) A {
+	if n < low {
+		return low
+	} else if n > high {
+		return high
+	} else {
+		return n
+	}
+}
+
 // Take the square root of a number.
-func Sqrt(n Float) Float {
-	return Float(math.Sqrt(float64(n)))
+func Sqrt[F Floating](n F) F {
+	return F(math.Sqrt(float64(n)))
+}
+
+// Calculate the logarithm of a number with a given base.
+func LogBase[F Floating](base F, n F) F {
+	return F(math.Log(float64(n)) / math.Log(float64(base)))
+}
+
+// An approximation of e.
+const E Float = math.E
+
+// An approximation of e as a 64-bit float, which holds more of its digits than E.
+const E64 Float64 = math.E
+
+// Angles
+
+// Convert degrees to standard Elm angles (radians).
+func Degrees[F Floating](angleInDegrees F) F {
+	return angleInDegrees * math.Pi / 180
+}
+
+// Convert radians to standard Elm angles (radians).
+func Radians[F Floating](angleInRadians F) F {
+	return angleInRadians
+}
+
+// Convert turns to standard Elm angles (radians). One turn is equal to 360°.
+func Turns[F Floating](angleInTurns F) F {
+	return 2 * math.Pi * angleInTurns
+}
+
+// Trigonometry
+
+// An approximation of pi.
+const Pi Float = math.Pi
+
+// An approximation of pi as a 64-bit float, which holds more of its digits than Pi.
+const Pi64 Float64 = math.Pi
+
+// Figure out the cosine given an angle in radians.
+func Cos[F Floating](x F) F {
+	return F(math.Cos(float64(x)))
+}
+
+// Figure out the sine given an angle in radians.
+func Sin[F Floating](x F) F {
+	return F(math.Sin(float64(x)))
+}
+
+// Figure out the tangent given an angle in radians.
+func Tan[F Floating](x F) F {
+	return F(math.Tan(float64(x)))
+}
+
+// Figure out the arccosine for adjacent / hypotenuse in radians.
+func Acos[F Floating](x F) F {
+	return F(math.Acos(float64(x)))
+}
+
+// Figure out the arcsine for opposite / hypotenuse in radians.
+func Asin[F Floating](x F) F {
+	return F(math.Asin(float64(x)))
+}
+
+// This helps you find the angle (in radians) to an (x,y) coordinate, but in a way that is rarely useful in
+// programming. You probably want Atan2 instead!
+func Atan[F Floating](x F) F {
+	return F(math.Atan(float64(x)))
+}
+
+// This helps you find the angle (in radians) to an (x,y) coordinate.
+// So rather than Atan(y / x) you say Atan2(y, x) and you can get a full range of angles.
+func Atan2[F Floating](y F, x F) F {
+	return F(math.Atan2(float64(y), float64(x)))
+}
+
+// Floating Point Checks
+
+// Determine whether a float is an undefined or unrepresentable number.
+// NaN stands for not a number and it is a standardized part of floating point numbers.
+func IsNaN[F Floating](n F) bool {
+	return math.IsNaN(float64(n))
+}
+
+// Determine whether a float is positive or negative infinity.
+// Notice that NaN is not infinite!
+func IsInfinite[F Floating](n F) bool {
+	return math.IsInf(float64(n), 0)
 }
 
 // Function helpers
@@ -234,3 +606,18 @@ func Always[A, B any](a A, b B) A {
 func ComposeL[A, B, C any](g func(B) C, f func(A) B) func(A) C {
 	return func(x A) C { return g(f(x)) }
 }
+
+// Function composition, passing results along to the right direction.
+func ComposeR[A, B, C any](f func(A) B, g func(B) C) func(A) C {
+	return func(x A) C { return g(f(x)) }
+}
+
+// Saying ApL(f, x) is exactly the same as f(x). It is Elm's backward pipe operator (<|).
+func ApL[A, B any](f func(A) B, x A) B {
+	return f(x)
+}
+
+// Saying ApR(x, f) is exactly the same as f(x). It is Elm's forward pipe operator (|>).
+func ApR[A, B any](x A, f func(A) B) B {
+	return f(x)
+}
@@ -2,7 +2,10 @@ package basics
 
 import (
 	"github.com/stretchr/testify/assert"
+	"math"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestMath(t *testing.T) {
@@ -16,13 +19,13 @@ func TestMath(t *testing.T) {
 	})
 
 	t.Run("Fdiv", func(t *testing.T) {
-		SUT1 := Fdiv(10, 4)
-		SUT2 := Fdiv(11, 4)
-		SUT3 := Fdiv(12, 4)
-		SUT4 := Fdiv(13, 4)
-		SUT5 := Fdiv(14, 4)
-		SUT6 := Fdiv(-1, 4)
-		SUT7 := Fdiv(-5, 4)
+		SUT1 := Fdiv(Float(10), 4)
+		SUT2 := Fdiv(Float(11), 4)
+		SUT3 := Fdiv(Float(12), 4)
+		SUT4 := Fdiv(Float(13), 4)
+		SUT5 := Fdiv(Float(14), 4)
+		SUT6 := Fdiv(Float(-1), 4)
+		SUT7 := Fdiv(Float(-5), 4)
 
 		asserts.Equal(Float(2.5), SUT1)
 		asserts.Equal(Float(2.75), SUT2)
@@ -33,11 +36,65 @@ func TestMath(t *testing.T) {
 		asserts.Equal(Float(-1.25), SUT7)
 
 	})
+
+	t.Run("IntDiv", func(t *testing.T) {
+		asserts.Equal(Int(2), IntDiv[Int](10, 4))
+		asserts.Equal(Int(2), IntDiv[Int](11, 4))
+		asserts.Equal(Int(3), IntDiv[Int](12, 4))
+		asserts.Equal(Int(0), IntDiv[Int](-1, 4))
+		asserts.Equal(Int(-1), IntDiv[Int](-5, 4))
+		asserts.Equal(Int(0), IntDiv[Int](5, 0))
+	})
+
+	t.Run("Float64", func(t *testing.T) {
+		asserts.Equal(Float64(0.1)+Float64(0.2), Add(Float64(0.1), Float64(0.2)))
+		asserts.Equal(Float64(1.0/3.0), Fdiv(Float64(1), 3))
+		asserts.Equal(Float(0.33333334), Fdiv(Float(1), 3))
+	})
+
+	t.Run("Int64", func(t *testing.T) {
+		asserts.Equal(Int64(9007199254740993), Add(Int64(9007199254740992), 1))
+		asserts.Equal(Int64(-6), Mul(Int64(-2), 3))
+	})
+
+	t.Run("Pow", func(t *testing.T) {
+		asserts.Equal(Int(9), Pow(Int(3), 2))
+		asserts.Equal(Int(27), Pow(Int(3), 3))
+		asserts.Equal(Int(1), Pow(Int(3), 0))
+		asserts.Equal(Int(-8), Pow(Int(-2), 3))
+		asserts.Equal(Int(4052555153018976267), Pow(Int(3), 39))
+		asserts.Equal(Float(0.5), Pow(Float(2), -1))
+		asserts.Equal(Float(1.5), Pow(Float(2.25), 0.5))
+		asserts.Equal(Int64(4052555153018976267), Pow(Int64(3), 39))
+		asserts.Equal(Float64(0.25), Pow(Float64(2), -2))
+	})
 }
 
 func TestIntToFloatFloatToInt(t *testing.T) {
 	asserts := assert.New(t)
 
+	t.Run("ToFloat64", func(t *testing.T) {
+		asserts.Equal(Float64(1), ToFloat64(Int(1)))
+		asserts.Equal(Float64(9007199254740993), ToFloat64(Int64(9007199254740993)))
+	})
+
+	t.Run("Float64 keeps precision", func(t *testing.T) {
+		asserts.Equal(Int64(1000000000000000), Floor[Int64](Float64(1000000000000000.5)))
+		asserts.Equal(Int64(1000000000000001), Ceiling[Int64](Float64(1000000000000000.5)))
+		asserts.Equal(Int64(1700000000123), Round[Int64](Float64(1700000000123.4)))
+		asserts.Equal(Int64(-1700000000123), Truncate[Int64](Float64(-1700000000123.9)))
+	})
+
+	t.Run("Int64 division", func(t *testing.T) {
+		big := Int64(1) << 60
+
+		asserts.Equal(Int64(1)<<58, IntDiv(big, 4))
+		asserts.Equal(Int64(1), ModBy(4, big+1))
+		asserts.Equal(Int64(3), ModBy(4, -big-1))
+		asserts.Equal(Int64(-1), RemainderBy(4, -big-1))
+		asserts.Equal(Int64(7), ModBy(1<<53+8, Int64(1)<<53+15))
+	})
+
 	t.Run("ToFloat", func(t *testing.T) {
 		var SUT1 Int = 23
 		var SUT2 Int = 20
@@ -50,112 +107,112 @@ func TestIntToFloatFloatToInt(t *testing.T) {
 		var SUT Float
 		SUT = 1.0
 
-		asserts.Equal(Int(1), Round(SUT))
+		asserts.Equal(Int(1), Round[Int](SUT))
 	})
 
 	t.Run("Round 1.2", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.2
 
-		asserts.Equal(Int(1), Round(SUT))
+		asserts.Equal(Int(1), Round[Int](SUT))
 	})
 
 	t.Run("Round 1.5", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.5
 
-		asserts.Equal(Int(2), Round(SUT))
+		asserts.Equal(Int(2), Round[Int](SUT))
 	})
 
 	t.Run("Round 1.8", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.8
 
-		asserts.Equal(Int(2), Round(SUT))
+		asserts.Equal(Int(2), Round[Int](SUT))
 	})
 
 	t.Run("Floor 1.0", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.0
 
-		asserts.Equal(Int(1), Floor(SUT))
+		asserts.Equal(Int(1), Floor[Int](SUT))
 	})
 
 	t.Run("Floor 1.2", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.2
 
-		asserts.Equal(Int(1), Floor(SUT))
+		asserts.Equal(Int(1), Floor[Int](SUT))
 	})
 
 	t.Run("Floor 1.5", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.5
 
-		asserts.Equal(Int(1), Floor(SUT))
+		asserts.Equal(Int(1), Floor[Int](SUT))
 	})
 
 	t.Run("Floor 1.8", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.8
 
-		asserts.Equal(Int(1), Floor(SUT))
+		asserts.Equal(Int(1), Floor[Int](SUT))
 	})
 
 	t.Run("Ceiling 1.0", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.0
 
-		asserts.Equal(Int(1), Ceiling(SUT))
+		asserts.Equal(Int(1), Ceiling[Int](SUT))
 	})
 
 	t.Run("Ceiling 1.2", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.2
 
-		asserts.Equal(Int(2), Ceiling(SUT))
+		asserts.Equal(Int(2), Ceiling[Int](SUT))
 	})
 
 	t.Run("Ceiling 1.5", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.5
 
-		asserts.Equal(Int(2), Ceiling(SUT))
+		asserts.Equal(Int(2), Ceiling[Int](SUT))
 	})
 
 	t.Run("Ceiling 1.8", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.8
 
-		asserts.Equal(Int(2), Ceiling(SUT))
+		asserts.Equal(Int(2), Ceiling[Int](SUT))
 	})
 
 	t.Run("Truncate 1.0", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.0
 
-		asserts.Equal(Int(1), Truncate(SUT))
+		asserts.Equal(Int(1), Truncate[Int](SUT))
 	})
 
 	t.Run("Truncate 1.2", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.2
 
-		asserts.Equal(Int(1), Truncate(SUT))
+		asserts.Equal(Int(1), Truncate[Int](SUT))
 	})
 
 	t.Run("Truncate 1.5", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.5
 
-		asserts.Equal(Int(1), Truncate(SUT))
+		asserts.Equal(Int(1), Truncate[Int](SUT))
 	})
 
 	t.Run("Truncate 1.8", func(t *testing.T) {
 		var SUT Float
 		SUT = 1.8
 
-		asserts.Equal(Int(1), Truncate(SUT))
+		asserts.Equal(Int(1), Truncate[Int](SUT))
 	})
 }
 
@@ -198,7 +255,83 @@ func TestEquality(t *testing.T) {
 			}
 			asserts.True(Eq(map_1, map_2))
 		})
+		t.Run("When Equatable", func(t *testing.T) {
+			asserts.True(Eq(caseless("Go"), caseless("GO")))
+			asserts.False(Eq(caseless("Go"), caseless("Elm")))
+		})
+		t.Run("When nil", func(t *testing.T) {
+			var x, y Equatable[Int]
+
+			asserts.True(Eq(x, y))
+			asserts.False(Eq[Equatable[Int]](x, Int(1)))
+		})
+		t.Run("Numbers", func(t *testing.T) {
+			asserts.True(Eq(Int(2), Int(2)))
+			asserts.True(Eq(Float(0), Float(math.Copysign(0, -1))))
+			asserts.False(Eq(Float64(math.NaN()), Float64(math.NaN())))
+		})
 	})
+	t.Run("Hash", func(t *testing.T) {
+		t.Run("Equal numbers have the same hash", func(t *testing.T) {
+			asserts.Equal(Hash(Int(42)), Hash(Int(42)))
+			asserts.Equal(Hash(Float(0)), Hash(Float(math.Copysign(0, -1))))
+			asserts.Equal(Hash(Float64(1.5)), Hash(Float64(1.5)))
+			asserts.NotEqual(Hash(Int(1)), Hash(Int(2)))
+		})
+		t.Run("Hashable values hash themselves", func(t *testing.T) {
+			asserts.Equal(Hash(caseless("Go")), Hash(caseless("GO")))
+		})
+		t.Run("Other values are hashed by their contents", func(t *testing.T) {
+			type user struct {
+				Name string
+				Tags []string
+				Age  *int
+			}
+			age1, age2 := 30, 30
+			u1 := user{Name: "Ana", Tags: []string{"a", "b"}, Age: &age1}
+			u2 := user{Name: "Ana", Tags: []string{"a", "b"}, Age: &age2}
+			u3 := user{Name: "Ana", Tags: []string{"b", "a"}, Age: &age1}
+
+			asserts.True(Eq(u1, u2))
+			asserts.Equal(Hash(u1), Hash(u2))
+			asserts.NotEqual(Hash(u1), Hash(u3))
+			asserts.Equal(Hash(map[string]int{"a": 1, "b": 2}), Hash(map[string]int{"b": 2, "a": 1}))
+		})
+		t.Run("Cyclic values", func(t *testing.T) {
+			type node struct {
+				Next *node
+			}
+			n := &node{}
+			n.Next = n
+
+			asserts.NotPanics(func() { Hash(n) })
+		})
+		t.Run("Equatable values that are not Hashable cannot be hashed", func(t *testing.T) {
+			asserts.True(Eq(unhashable("Go"), unhashable("GO")))
+			asserts.PanicsWithValue(
+				"basics: cannot hash basics.unhashable, which is Equatable but not Hashable",
+				func() { Hash(unhashable("Go")) },
+			)
+		})
+	})
+}
+
+// A caseless string without a Hash method, so walking it would hash "Go" and "GO" differently.
+type unhashable string
+
+func (c unhashable) Equal(y unhashable) bool {
+	return strings.EqualFold(string(c), string(y))
+}
+
+// A string that is equal to the same string in any case.
+type caseless string
+
+func (c caseless) Equal(y caseless) bool {
+	return strings.EqualFold(string(c), string(y))
+}
+
+func (c caseless) Hash() uint64 {
+	return Hash(strings.ToLower(string(c)))
 }
 
 func TestComparisons(t *testing.T) {
@@ -228,6 +361,28 @@ func TestComparisons(t *testing.T) {
 		asserts.False(Ge(Int(2), Int(3)))
 	})
 
+	t.Run("Int64 and Float64 are Comparable", func(t *testing.T) {
+		asserts.Equal(LT{}, Compare(Int64(1), Int64(2)))
+		asserts.Equal(GT{}, Compare(Float64(0.2), Float64(0.1)))
+		asserts.Equal(Float64(0.2), Max(Float64(0.2), Float64(0.1)))
+		asserts.True(Lt(Int64(-1<<62), Int64(1<<62)))
+	})
+
+	t.Run("Native wraps native Go types", func(t *testing.T) {
+		asserts.Equal(LT{}, Compare(Wrap(1), Wrap(2)))
+		asserts.Equal(GT{}, Compare(Wrap("b"), Wrap("a")))
+		asserts.Equal(Wrap(time.Second), Max(Wrap(time.Second), Wrap(time.Millisecond)))
+	})
+
+	t.Run("Native NaN is equal to itself", func(t *testing.T) {
+		nan := Wrap(math.NaN())
+
+		asserts.Equal(EQ{}, Compare(nan, nan))
+		asserts.True(Eq(nan, nan))
+		asserts.Equal(Hash(nan), Hash(Wrap(-math.NaN())))
+		asserts.Equal(Hash(Wrap(0.0)), Hash(Wrap(math.Copysign(0, -1))))
+	})
+
 	t.Run("Max", func(t *testing.T) {
 		asserts.Equal(Int(2), Max(Int(1), Int(2)))
 		asserts.Equal(Int(3), Max(Int(1), Int(3)))
@@ -246,27 +401,34 @@ func TestBooleans(t *testing.T) {
 		asserts.True(Not(false))
 		asserts.False(Not(true))
 	})
+
+	t.Run("Xor", func(t *testing.T) {
+		asserts.False(Xor(true, true))
+		asserts.True(Xor(true, false))
+		asserts.True(Xor(false, true))
+		asserts.False(Xor(false, false))
+	})
 }
 
 func TestFancierMath(t *testing.T) {
 	asserts := assert.New(t)
 
 	t.Run("ModBy", func(t *testing.T) {
-		asserts.Equal(Int(0), ModBy(2, 0))
-		asserts.Equal(Int(1), ModBy(2, 1))
-		asserts.Equal(Int(0), ModBy(2, 2))
-		asserts.Equal(Int(1), ModBy(2, 3))
-		asserts.Equal(Int(3), ModBy(4, -5))
-		asserts.Equal(Int(0), ModBy(4, -4))
-		asserts.Equal(Int(1), ModBy(4, -3))
-		asserts.Equal(Int(2), ModBy(4, -2))
-		asserts.Equal(Int(3), ModBy(4, -1))
-		asserts.Equal(Int(0), ModBy(4, 0))
-		asserts.Equal(Int(1), ModBy(4, 1))
-		asserts.Equal(Int(2), ModBy(4, 2))
-		asserts.Equal(Int(3), ModBy(4, 3))
-		asserts.Equal(Int(0), ModBy(4, 4))
-		asserts.Equal(Int(1), ModBy(4, 5))
+		asserts.Equal(Int(0), ModBy[Int](2, 0))
+		asserts.Equal(Int(1), ModBy[Int](2, 1))
+		asserts.Equal(Int(0), ModBy[Int](2, 2))
+		asserts.Equal(Int(1), ModBy[Int](2, 3))
+		asserts.Equal(Int(3), ModBy[Int](4, -5))
+		asserts.Equal(Int(0), ModBy[Int](4, -4))
+		asserts.Equal(Int(1), ModBy[Int](4, -3))
+		asserts.Equal(Int(2), ModBy[Int](4, -2))
+		asserts.Equal(Int(3), ModBy[Int](4, -1))
+		asserts.Equal(Int(0), ModBy[Int](4, 0))
+		asserts.Equal(Int(1), ModBy[Int](4, 1))
+		asserts.Equal(Int(2), ModBy[Int](4, 2))
+		asserts.Equal(Int(3), ModBy[Int](4, 3))
+		asserts.Equal(Int(0), ModBy[Int](4, 4))
+		asserts.Equal(Int(1), ModBy[Int](4, 5))
 	})
 
 	t.Run("Negate", func(t *testing.T) {
@@ -280,7 +442,136 @@ func TestFancierMath(t *testing.T) {
 	})
 
 	t.Run("Sqrt", func(t *testing.T) {
-		asserts.Equal(Float(6), Sqrt(36))
+		asserts.Equal(Float(6), Sqrt(Float(36)))
+		asserts.Equal(Float64(math.Sqrt2), Sqrt(Float64(2)))
+	})
+
+	t.Run("RemainderBy", func(t *testing.T) {
+		asserts.Equal(Int(-1), RemainderBy[Int](4, -5))
+		asserts.Equal(Int(0), RemainderBy[Int](4, -4))
+		asserts.Equal(Int(-3), RemainderBy[Int](4, -3))
+		asserts.Equal(Int(1), RemainderBy[Int](4, 5))
+		asserts.Equal(Int(1), RemainderBy[Int](-4, 5))
+	})
+
+	t.Run("RemainderBy zero", func(t *testing.T) {
+		asserts.Panics(func() { RemainderBy[Int](0, 5) })
+	})
+
+	t.Run("Abs", func(t *testing.T) {
+		asserts.Equal(Int(4), Abs(Int(-4)))
+		asserts.Equal(Int(4), Abs(Int(4)))
+		asserts.Equal(Float(3.5), Abs(Float(-3.5)))
+		asserts.Equal(Float(0), Abs(Float(0)))
+	})
+
+	t.Run("Clamp", func(t *testing.T) {
+		asserts.Equal(Int(100), Clamp(100, 200, Int(50)))
+		asserts.Equal(Int(150), Clamp(100, 200, Int(150)))
+		asserts.Equal(Int(200), Clamp(100, 200, Int(250)))
+		asserts.Equal(Float(0.5), Clamp(0, 1, Float(0.5)))
+	})
+
+	t.Run("LogBase", func(t *testing.T) {
+		asserts.Equal(Float(2), LogBase(Float(10), 100))
+		asserts.Equal(Float(8), LogBase(Float(2), 256))
+	})
+
+	t.Run("E", func(t *testing.T) {
+		asserts.InDelta(2.718281, float64(E), 1e-6)
+	})
+
+	t.Run("E64", func(t *testing.T) {
+		asserts.Equal(math.E, float64(E64))
+		asserts.Equal(Float64(1), LogBase(E64, E64))
+	})
+}
+
+func TestAngles(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Degrees", func(t *testing.T) {
+		asserts.Equal(Pi, Degrees(Float(180)))
+		asserts.Equal(Pi/2, Degrees(Float(90)))
+	})
+
+	t.Run("Radians", func(t *testing.T) {
+		asserts.Equal(Pi, Radians(Pi))
+	})
+
+	t.Run("Turns", func(t *testing.T) {
+		asserts.Equal(Pi, Turns(Float(0.5)))
+		asserts.Equal(2*Pi, Turns(Float(1)))
+	})
+}
+
+func TestTrigonometry(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Pi", func(t *testing.T) {
+		asserts.InDelta(3.141592, float64(Pi), 1e-6)
+	})
+
+	t.Run("Pi64", func(t *testing.T) {
+		asserts.Equal(math.Pi, float64(Pi64))
+		asserts.Equal(Pi64, Degrees(Float64(180)))
+		asserts.Equal(Pi64, Turns(Float64(0.5)))
+	})
+
+	t.Run("Cos", func(t *testing.T) {
+		asserts.InDelta(0.5, float64(Cos(Degrees(Float(60)))), 1e-6)
+		asserts.InDelta(0.5, float64(Cos(Turns(Float(1.0/6)))), 1e-6)
+		asserts.InDelta(-1, float64(Cos(Pi)), 1e-6)
+	})
+
+	t.Run("Sin", func(t *testing.T) {
+		asserts.InDelta(0.5, float64(Sin(Degrees(Float(30)))), 1e-6)
+		asserts.InDelta(1, float64(Sin(Pi/2)), 1e-6)
+	})
+
+	t.Run("Tan", func(t *testing.T) {
+		asserts.InDelta(1, float64(Tan(Degrees(Float(45)))), 1e-6)
+		asserts.InDelta(1, float64(Tan(Pi/4)), 1e-6)
+	})
+
+	t.Run("Acos", func(t *testing.T) {
+		asserts.InDelta(1.047197, float64(Acos(Float(0.5))), 1e-6)
+	})
+
+	t.Run("Asin", func(t *testing.T) {
+		asserts.InDelta(0.523598, float64(Asin(Float(0.5))), 1e-6)
+	})
+
+	t.Run("Atan", func(t *testing.T) {
+		asserts.InDelta(0.785398, float64(Atan(Float(1))), 1e-6)
+		asserts.InDelta(-0.785398, float64(Atan(Float(-1))), 1e-6)
+	})
+
+	t.Run("Atan2", func(t *testing.T) {
+		asserts.InDelta(0.785398, float64(Atan2(Float(1), 1)), 1e-6)
+		asserts.InDelta(2.356194, float64(Atan2(Float(1), -1)), 1e-6)
+		asserts.InDelta(-2.356194, float64(Atan2(Float(-1), -1)), 1e-6)
+		asserts.InDelta(-0.785398, float64(Atan2(Float(-1), 1)), 1e-6)
+	})
+}
+
+func TestFloatingPointChecks(t *testing.T) {
+	asserts := assert.New(t)
+	zero := Float(0)
+
+	t.Run("IsNaN", func(t *testing.T) {
+		asserts.True(IsNaN(zero / zero))
+		asserts.True(IsNaN(Sqrt(Float(-1))))
+		asserts.False(IsNaN(1 / zero))
+		asserts.False(IsNaN(Float(1)))
+	})
+
+	t.Run("IsInfinite", func(t *testing.T) {
+		asserts.False(IsInfinite(zero / zero))
+		asserts.False(IsInfinite(Sqrt(Float(-1))))
+		asserts.True(IsInfinite(1 / zero))
+		asserts.True(IsInfinite(-1 / zero))
+		asserts.False(IsInfinite(Float(1)))
 	})
 }
 
@@ -288,7 +579,7 @@ func TestFunctionHelpers(t *testing.T) {
 	asserts := assert.New(t)
 
 	t.Run("ComposeL", func(t *testing.T) {
-		isEven := func(i Float) bool { return ModBy(2, Int(i)) == 0 }
+		isEven := func(i Float) bool { return ModBy[Int](2, Int(i)) == 0 }
 
 		SUT1 := ComposeL(Not, ComposeL(isEven, Sqrt))
 		SUT2 := ComposeL(isEven, Sqrt)
@@ -299,4 +590,24 @@ func TestFunctionHelpers(t *testing.T) {
 		asserts.True(SUT2(4))
 		asserts.True(SUT2(36))
 	})
+
+	t.Run("ComposeR", func(t *testing.T) {
+		isEven := func(i Float) bool { return ModBy[Int](2, Int(i)) == 0 }
+
+		SUT := ComposeR(Sqrt, ComposeR(isEven, Not))
+
+		asserts.False(SUT(4))
+		asserts.True(SUT(2))
+	})
+
+	t.Run("ApL", func(t *testing.T) {
+		asserts.Equal(Float(3), ApL(Sqrt, Float(9)))
+		asserts.Equal(Float(2), ApL(Sqrt, ApL(Sqrt, Float(16))))
+	})
+
+	t.Run("ApR", func(t *testing.T) {
+		SUT := ApR(ApR(Float(16), Sqrt), Sqrt)
+
+		asserts.Equal(Float(2), SUT)
+	})
 }
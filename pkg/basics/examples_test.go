@@ -16,24 +16,37 @@ func ExampleFdiv() {
 	Fdiv(Float(10), Float(4)) // 2.5
 }
 
+func ExampleIntDiv() {
+	IntDiv(Int(12), Int(4)) // 3
+	IntDiv(Int(13), Int(4)) // 3
+	IntDiv(Int(-1), Int(4)) // 0
+	IntDiv(Int(-5), Int(4)) // -1
+}
+
+func ExamplePow() {
+	Pow(Int(3), Int(2))      // 9
+	Pow(Int(3), Int(3))      // 27
+	Pow(Float(2), Float(-1)) // 0.5
+}
+
 func ExampleToFloat() {
 	ToFloat(1) // 1.0
 }
 
 func ExampleRound() {
-	Round(1.0) // 1
+	Round[Int](Float(1.0)) // 1
 }
 
 func ExampleFloor() {
-	Floor(1.0) // 1
+	Floor[Int](Float(1.0)) // 1
 }
 
 func ExampleCeiling() {
-	Ceiling(1.0) // 1
+	Ceiling[Int](Float(1.0)) // 1
 }
 
 func ExampleTruncate() {
-	Truncate(1.0) // 1
+	Truncate[Int](Float(1.0)) // 1
 }
 
 func ExampleEq() {
@@ -73,10 +86,22 @@ func ExampleNot() {
 	Not(false) // true
 }
 
+func ExampleXor() {
+	Xor(true, true)   // false
+	Xor(true, false)  // true
+	Xor(false, true)  // true
+	Xor(false, false) // false
+}
+
 func ExampleModBy() {
 	ModBy(Int(2), Int(2)) // 0
 }
 
+func ExampleRemainderBy() {
+	RemainderBy(Int(4), Int(-5)) // -1
+	RemainderBy(Int(4), Int(5))  // 1
+}
+
 func ExampleNegate() {
 	Negate(Int(42)) // -42
 }
@@ -88,6 +113,73 @@ func ExampleSqrt() {
 	Sqrt(Float(25)) // 5
 }
 
+func ExampleAbs() {
+	Abs(Int(-4))     // 4
+	Abs(Float(-4.2)) // 4.2
+	Abs(Float(3.14)) // 3.14
+}
+
+func ExampleClamp() {
+	Clamp(Int(100), Int(200), Int(50))  // 100
+	Clamp(Int(100), Int(200), Int(150)) // 150
+	Clamp(Int(100), Int(200), Int(250)) // 200
+}
+
+func ExampleLogBase() {
+	LogBase(Float(10), Float(100)) // 2
+	LogBase(Float(2), Float(256))  // 8
+}
+
+func ExampleDegrees() {
+	Degrees(Float(180)) // 3.1415927
+}
+
+func ExampleTurns() {
+	Turns(Float(0.5)) // 3.1415927
+}
+
+func ExampleCos() {
+	Cos(Degrees(Float(60))) // 0.5
+}
+
+func ExampleSin() {
+	Sin(Degrees(Float(30))) // 0.5
+}
+
+func ExampleTan() {
+	Tan(Degrees(Float(45))) // 1
+}
+
+func ExampleAcos() {
+	Acos(Float(0.5)) // 1.0471976
+}
+
+func ExampleAsin() {
+	Asin(Float(0.5)) // 0.5235988
+}
+
+func ExampleAtan() {
+	Atan(Float(1))  // 0.7853982
+	Atan(Float(-1)) // -0.7853982
+}
+
+func ExampleAtan2() {
+	Atan2(Float(1), Float(1))   // 0.7853982
+	Atan2(Float(1), Float(-1))  // 2.3561945
+	Atan2(Float(-1), Float(-1)) // -2.3561945
+	Atan2(Float(-1), Float(1))  // -0.7853982
+}
+
+func ExampleIsNaN() {
+	IsNaN(Sqrt(Float(-1))) // true
+	IsNaN(Float(1))        // false
+}
+
+func ExampleIsInfinite() {
+	IsInfinite(Sqrt(Float(-1))) // false
+	IsInfinite(Float(1))        // false
+}
+
 func ExampleIdentity() {
 	Identity(Float(4)) // 4
 }
@@ -96,3 +188,16 @@ func ExampleComposeL() {
 	composed := ComposeL(func(i Int) Int { return i + 1 }, Identity)
 	composed(1) // 2
 }
+
+func ExampleComposeR() {
+	composed := ComposeR(Identity, func(i Int) Int { return i + 1 })
+	composed(1) // 2
+}
+
+func ExampleApL() {
+	ApL(Sqrt, Float(16)) // 4
+}
+
+func ExampleApR() {
+	ApR(ApR(Float(16), Sqrt), Sqrt) // 2
+}
@@ -1,23 +1,58 @@
+// Package bitwise has low-level bit manipulation functions, inspired by the Elm Bitwise module.
+//
+// Like Elm, every operation works on 32-bit signed integers. The arguments are truncated to
+// their lowest 32 bits before the operation and the result is a 32-bit signed integer, with
+// the exception of ShiftRightZfBy which results in a 32-bit unsigned integer.
+//
+// Shift offsets only use their lowest 5 bits, so an offset of 32 behaves like 0, an offset of 33
+// like 1 and a negative offset of -1 like 31.
 package bitwise
 
 import (
-	. "github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
 )
 
-func And(a, b Int) Int {
-	return a & b
+// Bitwise AND
+func And(a, b basics.Int) basics.Int {
+	return basics.Int(int32(a) & int32(b))
+}
+
+// Bitwise OR
+func Or(a, b basics.Int) basics.Int {
+	return basics.Int(int32(a) | int32(b))
+}
+
+// Bitwise XOR
+func Xor(a, b basics.Int) basics.Int {
+	return basics.Int(int32(a) ^ int32(b))
+}
+
+// Flip each bit individually, often called bitwise NOT
+func Complement(a basics.Int) basics.Int {
+	return basics.Int(^int32(a))
 }
 
 // Bit Shifts
 
 // Shift bits to the right by a given offset, filling new bits with whatever is the topmost bit. This can be used to
 // divide numbers by powers of two.
-func ShiftRightBy(offset Int, a Int) Int {
-	return a >> offset
+func ShiftRightBy(offset basics.Int, a basics.Int) basics.Int {
+	return basics.Int(int32(a) >> shiftCount(offset))
 }
 
 // Shift bits to the left by a given offset, filling new bits with zeros. This can be used to
 // multiply numbers by powers of two.
-func ShiftLeftBy(offset Int, a Int) Int {
-	return a << offset
+func ShiftLeftBy(offset basics.Int, a basics.Int) basics.Int {
+	return basics.Int(int32(a) << shiftCount(offset))
+}
+
+// Shift bits to the right by a given offset, filling new bits with zeros.
+// The result is interpreted as an unsigned 32-bit integer, so ShiftRightZfBy(0, -1) is 4294967295.
+func ShiftRightZfBy(offset basics.Int, a basics.Int) basics.Int {
+	return basics.Int(uint32(a) >> shiftCount(offset))
+}
+
+// Only the lowest 5 bits of a shift offset are used, matching JavaScript shift operators.
+func shiftCount(offset basics.Int) uint {
+	return uint(offset) & 31
 }
@@ -1,9 +1,10 @@
 package bitwise
 
 import (
-	. "github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"testing/quick"
 )
 
 func TestBasicOperations(t *testing.T) {
@@ -13,8 +14,31 @@ func TestBasicOperations(t *testing.T) {
 		SUT1 := And(1, 1)
 		SUT2 := And(1, 2)
 
-		asserts.Equal(Int(1), SUT1)
-		asserts.Equal(Int(0), SUT2)
+		asserts.Equal(basics.Int(1), SUT1)
+		asserts.Equal(basics.Int(0), SUT2)
+	})
+
+	t.Run("Or", func(t *testing.T) {
+		asserts.Equal(basics.Int(3), Or(1, 2))
+		asserts.Equal(basics.Int(-1), Or(-1, 7))
+	})
+
+	t.Run("Xor", func(t *testing.T) {
+		asserts.Equal(basics.Int(6), Xor(5, 3))
+		asserts.Equal(basics.Int(0), Xor(-7, -7))
+	})
+
+	t.Run("Complement", func(t *testing.T) {
+		asserts.Equal(basics.Int(-1), Complement(0))
+		asserts.Equal(basics.Int(-8), Complement(7))
+		asserts.Equal(basics.Int(0), Complement(-1))
+	})
+
+	t.Run("Operands are truncated to 32 bits", func(t *testing.T) {
+		asserts.Equal(basics.Int(0), And(1<<32, 1<<32))
+		asserts.Equal(basics.Int(-2147483648), Or(1<<31, 0))
+		asserts.Equal(basics.Int(1), Xor(1<<32|1, 0))
+		asserts.Equal(basics.Int(2147483647), Complement(1<<31))
 	})
 }
 
@@ -24,12 +48,150 @@ func TestBitShifts(t *testing.T) {
 	t.Run("ShiftRightBy", func(t *testing.T) {
 		SUT := ShiftRightBy(1, 2)
 
-		asserts.Equal(Int(1), SUT)
+		asserts.Equal(basics.Int(1), SUT)
+		asserts.Equal(basics.Int(-16), ShiftRightBy(1, -32))
+		asserts.Equal(basics.Int(-1), ShiftRightBy(31, -1))
 	})
 
 	t.Run("ShiftLeftBy", func(t *testing.T) {
 		SUT := ShiftLeftBy(1, 5)
 
-		asserts.Equal(Int(10), SUT)
+		asserts.Equal(basics.Int(10), SUT)
+		asserts.Equal(basics.Int(-2147483648), ShiftLeftBy(31, 1))
+		asserts.Equal(basics.Int(0), ShiftLeftBy(1, 1<<31))
+	})
+
+	t.Run("ShiftRightZfBy", func(t *testing.T) {
+		asserts.Equal(basics.Int(8), ShiftRightZfBy(1, 16))
+		asserts.Equal(basics.Int(2147483632), ShiftRightZfBy(1, -32))
+		asserts.Equal(basics.Int(4294967295), ShiftRightZfBy(0, -1))
+		asserts.Equal(basics.Int(1), ShiftRightZfBy(31, -1))
+	})
+
+	t.Run("Offsets of 32 or more wrap around", func(t *testing.T) {
+		asserts.Equal(basics.Int(5), ShiftLeftBy(32, 5))
+		asserts.Equal(basics.Int(10), ShiftLeftBy(33, 5))
+		asserts.Equal(basics.Int(-16), ShiftRightBy(33, -32))
+		asserts.Equal(basics.Int(8), ShiftRightZfBy(65, 16))
+	})
+
+	t.Run("Negative offsets use their lowest 5 bits", func(t *testing.T) {
+		asserts.Equal(basics.Int(-2147483648), ShiftLeftBy(-1, 1))
+		asserts.Equal(basics.Int(-1), ShiftRightBy(-1, -5))
+		asserts.Equal(basics.Int(1), ShiftRightZfBy(-1, -5))
+		asserts.Equal(basics.Int(4), ShiftLeftBy(-30, 1))
+	})
+}
+
+// Reference implementation following the ECMAScript ToInt32, ToUint32 and shift operator definitions,
+// computed with plain arithmetic instead of fixed width integers.
+
+const two32 = 1 << 32
+
+func refToUint32(x basics.Int) basics.Int {
+	m := x % two32
+	if m < 0 {
+		m += two32
+	}
+	return m
+}
+
+func refToInt32(x basics.Int) basics.Int {
+	m := refToUint32(x)
+	if m >= two32/2 {
+		return m - two32
+	}
+	return m
+}
+
+func refPow2(n basics.Int) basics.Int {
+	p := basics.Int(1)
+	for i := basics.Int(0); i < n; i++ {
+		p *= 2
+	}
+	return p
+}
+
+func refBitwise(op func(bool, bool) bool, a, b basics.Int) basics.Int {
+	x, y := refToUint32(a), refToUint32(b)
+	r := basics.Int(0)
+	for i := basics.Int(0); i < 32; i++ {
+		p := refPow2(i)
+		if op((x/p)%2 == 1, (y/p)%2 == 1) {
+			r += p
+		}
+	}
+	return refToInt32(r)
+}
+
+func refShiftCount(offset basics.Int) basics.Int {
+	return refToUint32(offset) % 32
+}
+
+func refShiftLeftBy(offset, a basics.Int) basics.Int {
+	return refToInt32(refToUint32(a) * refPow2(refShiftCount(offset)))
+}
+
+func refShiftRightBy(offset, a basics.Int) basics.Int {
+	x, p := refToInt32(a), refPow2(refShiftCount(offset))
+	q := x / p
+	if x%p != 0 && x < 0 {
+		q--
+	}
+	return q
+}
+
+func refShiftRightZfBy(offset, a basics.Int) basics.Int {
+	return refToUint32(a) / refPow2(refShiftCount(offset))
+}
+
+func TestProperties(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("And matches the reference implementation", func(t *testing.T) {
+		prop := func(a, b basics.Int) bool {
+			return And(a, b) == refBitwise(func(x, y bool) bool { return x && y }, a, b)
+		}
+
+		asserts.NoError(quick.Check(prop, nil))
+	})
+
+	t.Run("Or matches the reference implementation", func(t *testing.T) {
+		prop := func(a, b basics.Int) bool {
+			return Or(a, b) == refBitwise(func(x, y bool) bool { return x || y }, a, b)
+		}
+
+		asserts.NoError(quick.Check(prop, nil))
+	})
+
+	t.Run("Xor matches the reference implementation", func(t *testing.T) {
+		prop := func(a, b basics.Int) bool {
+			return Xor(a, b) == refBitwise(func(x, y bool) bool { return x != y }, a, b)
+		}
+
+		asserts.NoError(quick.Check(prop, nil))
+	})
+
+	t.Run("Complement matches the reference implementation", func(t *testing.T) {
+		prop := func(a basics.Int) bool {
+			return Complement(a) == refBitwise(func(x, _ bool) bool { return !x }, a, 0)
+		}
+
+		asserts.NoError(quick.Check(prop, nil))
+	})
+
+	t.Run("Shifts match the reference implementation", func(t *testing.T) {
+		prop := func(offset, a basics.Int) bool {
+			return ShiftLeftBy(offset, a) == refShiftLeftBy(offset, a) &&
+				ShiftRightBy(offset, a) == refShiftRightBy(offset, a) &&
+				ShiftRightZfBy(offset, a) == refShiftRightZfBy(offset, a)
+		}
+
+		asserts.NoError(quick.Check(prop, nil))
+		for offset := basics.Int(-70); offset <= 70; offset++ {
+			for _, a := range []basics.Int{0, 1, -1, 5, -5, 1 << 31, 1<<31 - 1, -1 << 31, 1 << 40} {
+				asserts.True(prop(offset, a), "offset %d, value %d", offset, a)
+			}
+		}
 	})
 }
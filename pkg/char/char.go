@@ -1,12 +1,134 @@
+// Package char has functions for working with characters, inspired by the Elm Char module.
 package char
 
-import "unicode"
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/internal/hashing"
+	"unicode"
+	"unicode/utf8"
+)
 
 type Char rune
 
+func (c Char) Cmp(y basics.Comparable[Char]) int {
+	return cmp.Compare(c, y.T())
+}
+
+func (c Char) T() Char {
+	return c
+}
+
+func (c Char) Equal(y Char) bool {
+	return c == y
+}
+
+func (c Char) Hash() uint64 {
+	return hashing.Uint64(uint64(c))
+}
+
+// Characters are written to JSON as a string holding the character, not its code point.
+func (c Char) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(c))
+}
+func (c *Char) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return c.UnmarshalText([]byte(s))
+}
+
+// Characters are written as text as the character, so a dictionary keyed by them is a JSON object.
+func (c Char) MarshalText() ([]byte, error) {
+	return []byte(string(c)), nil
+}
+func (c *Char) UnmarshalText(text []byte) error {
+	r, size := utf8.DecodeRune(text)
+	if size == 0 || size != len(text) || (r == utf8.RuneError && size == 1) {
+		return fmt.Errorf("char: %q is not a single character", text)
+	}
+	*c = Char(r)
+	return nil
+}
+
+// ASCII Letters
+
+// Detect upper case ASCII characters.
+func IsUpper(c Char) bool {
+	return 'A' <= c && c <= 'Z'
+}
+
+// Detect lower case ASCII characters.
+func IsLower(c Char) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+// Detect upper case and lower case ASCII characters.
+func IsAlpha(c Char) bool {
+	return IsLower(c) || IsUpper(c)
+}
+
+// Detect upper case and lower case ASCII characters, or digits.
+func IsAlphaNum(c Char) bool {
+	return IsAlpha(c) || ('0' <= c && c <= '9')
+}
+
 // Digits
 
 // Detect digits 0123456789
 func IsDigit(c Char) bool {
-	return unicode.IsDigit(rune(c))
+	return '0' <= c && c <= '9'
+}
+
+// Detect octal digits 01234567
+func IsOctDigit(c Char) bool {
+	return '0' <= c && c <= '7'
+}
+
+// Detect hexadecimal digits 0123456789abcdefABCDEF
+func IsHexDigit(c Char) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+// Conversion
+
+// Convert to upper case.
+func ToUpper(c Char) Char {
+	return Char(unicode.ToUpper(rune(c)))
+}
+
+// Convert to lower case.
+func ToLower(c Char) Char {
+	return Char(unicode.ToLower(rune(c)))
+}
+
+// Convert to upper case, according to any locale-specific case mappings.
+// Go has no notion of the current locale, so this uses the default Unicode mappings.
+func ToLocaleUpper(c Char) Char {
+	return ToUpper(c)
+}
+
+// Convert to lower case, according to any locale-specific case mappings.
+// Go has no notion of the current locale, so this uses the default Unicode mappings.
+func ToLocaleLower(c Char) Char {
+	return ToLower(c)
+}
+
+// Unicode Code Points
+
+// Convert to the corresponding Unicode code point.
+func ToCode(c Char) basics.Int {
+	return basics.Int(c)
+}
+
+// Convert a Unicode code point to a character.
+// Codes that are not valid code points become the replacement character '�'.
+func FromCode(code basics.Int) Char {
+	if code < 0 || code > utf8.MaxRune || !utf8.ValidRune(rune(code)) {
+		return utf8.RuneError
+	}
+	return Char(code)
 }
@@ -0,0 +1,167 @@
+package char
+
+import (
+	"encoding/json"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCmp(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Compare", func(t *testing.T) {
+		asserts.Equal(basics.LT{}, basics.Compare[Char]('a', 'b'))
+		asserts.Equal(basics.EQ{}, basics.Compare[Char]('a', 'a'))
+		asserts.Equal(basics.GT{}, basics.Compare[Char]('b', 'A'))
+	})
+	t.Run("Max", func(t *testing.T) {
+		asserts.Equal(Char('z'), basics.Max[Char]('z', 'a'))
+	})
+}
+
+func TestLetters(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("IsUpper", func(t *testing.T) {
+		asserts.True(IsUpper('A'))
+		asserts.True(IsUpper('Z'))
+		asserts.False(IsUpper('a'))
+		asserts.False(IsUpper('0'))
+		asserts.False(IsUpper('Σ'))
+	})
+	t.Run("IsLower", func(t *testing.T) {
+		asserts.True(IsLower('a'))
+		asserts.True(IsLower('z'))
+		asserts.False(IsLower('A'))
+		asserts.False(IsLower('0'))
+		asserts.False(IsLower('ω'))
+	})
+	t.Run("IsAlpha", func(t *testing.T) {
+		asserts.True(IsAlpha('z'))
+		asserts.True(IsAlpha('A'))
+		asserts.False(IsAlpha('1'))
+		asserts.False(IsAlpha('_'))
+	})
+	t.Run("IsAlphaNum", func(t *testing.T) {
+		asserts.True(IsAlphaNum('z'))
+		asserts.True(IsAlphaNum('A'))
+		asserts.True(IsAlphaNum('1'))
+		asserts.False(IsAlphaNum('_'))
+		asserts.False(IsAlphaNum(' '))
+		asserts.False(IsAlphaNum('٣'))
+	})
+}
+
+func TestDigits(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("IsDigit", func(t *testing.T) {
+		asserts.True(IsDigit('0'))
+		asserts.True(IsDigit('9'))
+		asserts.False(IsDigit('a'))
+		asserts.False(IsDigit('٣'))
+	})
+	t.Run("IsOctDigit", func(t *testing.T) {
+		asserts.True(IsOctDigit('0'))
+		asserts.True(IsOctDigit('7'))
+		asserts.False(IsOctDigit('8'))
+		asserts.False(IsOctDigit('a'))
+	})
+	t.Run("IsHexDigit", func(t *testing.T) {
+		asserts.True(IsHexDigit('0'))
+		asserts.True(IsHexDigit('9'))
+		asserts.True(IsHexDigit('a'))
+		asserts.True(IsHexDigit('F'))
+		asserts.False(IsHexDigit('g'))
+		asserts.False(IsHexDigit('G'))
+	})
+}
+
+func TestConversion(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("ToUpper", func(t *testing.T) {
+		asserts.Equal(Char('A'), ToUpper('a'))
+		asserts.Equal(Char('A'), ToUpper('A'))
+		asserts.Equal(Char('Ω'), ToUpper('ω'))
+		asserts.Equal(Char('1'), ToUpper('1'))
+	})
+	t.Run("ToLower", func(t *testing.T) {
+		asserts.Equal(Char('a'), ToLower('A'))
+		asserts.Equal(Char('a'), ToLower('a'))
+		asserts.Equal(Char('ω'), ToLower('Ω'))
+		asserts.Equal(Char('1'), ToLower('1'))
+	})
+	t.Run("ToLocaleUpper", func(t *testing.T) {
+		asserts.Equal(Char('A'), ToLocaleUpper('a'))
+	})
+	t.Run("ToLocaleLower", func(t *testing.T) {
+		asserts.Equal(Char('a'), ToLocaleLower('A'))
+	})
+}
+
+func TestCodePoints(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("ToCode", func(t *testing.T) {
+		asserts.Equal(basics.Int(65), ToCode('A'))
+		asserts.Equal(basics.Int(66), ToCode('B'))
+		asserts.Equal(basics.Int(0x6728), ToCode('木'))
+		asserts.Equal(basics.Int(0x1D306), ToCode('𝌆'))
+	})
+	t.Run("FromCode", func(t *testing.T) {
+		asserts.Equal(Char('A'), FromCode(65))
+		asserts.Equal(Char('B'), FromCode(66))
+		asserts.Equal(Char('木'), FromCode(0x6728))
+		asserts.Equal(Char('𝌆'), FromCode(0x1D306))
+	})
+	t.Run("FromCode with invalid code points", func(t *testing.T) {
+		asserts.Equal(Char('�'), FromCode(-1))
+		asserts.Equal(Char('�'), FromCode(0xD800))
+		asserts.Equal(Char('�'), FromCode(0x110000))
+	})
+	t.Run("Round trip", func(t *testing.T) {
+		asserts.Equal(Char('z'), FromCode(ToCode('z')))
+	})
+}
+
+func TestEquality(t *testing.T) {
+	asserts := assert.New(t)
+
+	asserts.True(basics.Eq[Char]('a', 'a'))
+	asserts.False(basics.Eq[Char]('a', 'A'))
+	asserts.Equal(basics.Hash(Char('木')), basics.Hash(FromCode(0x6728)))
+	asserts.NotEqual(basics.Hash(Char('a')), basics.Hash(Char('b')))
+}
+
+func TestEncoding(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("JSON is the character", func(t *testing.T) {
+		data, err := json.Marshal([]Char{'a', '木', '𝌆'})
+		asserts.NoError(err)
+		asserts.Equal(`["a","木","𝌆"]`, string(data))
+
+		var SUT []Char
+		asserts.NoError(json.Unmarshal(data, &SUT))
+		asserts.Equal([]Char{'a', '木', '𝌆'}, SUT)
+	})
+	t.Run("Text is the character", func(t *testing.T) {
+		text, err := Char('木').MarshalText()
+		asserts.NoError(err)
+
+		var SUT Char
+		asserts.NoError(SUT.UnmarshalText(text))
+		asserts.Equal("木", string(text))
+		asserts.Equal(Char('木'), SUT)
+	})
+	t.Run("Decoding anything but one character fails", func(t *testing.T) {
+		var SUT Char
+
+		asserts.Error(json.Unmarshal([]byte(`""`), &SUT))
+		asserts.Error(json.Unmarshal([]byte(`"ab"`), &SUT))
+		asserts.Error(json.Unmarshal([]byte(`97`), &SUT))
+		asserts.Error(SUT.UnmarshalText([]byte{0xff}))
+	})
+}
@@ -1,14 +1,20 @@
 // Package dict implements an immutable dictionary, mapping unique keys to values.
 // The keys can be any [cmp.Ordered] type.
 // Insert, remove, and query operations all take O(log n) time.
+//
+// Ordered is the one exception to the data-last functions of this package. Its operations are
+// methods, like d.Insert(k, v), because Go has no overloading, so as functions they would need
+// names of their own next to Insert, Get and the rest.
 package dict
 
 import (
 	"errors"
-	. "github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/internal/hashing"
 	"github.com/Confidenceman02/scion-tools/pkg/list"
 	"github.com/Confidenceman02/scion-tools/pkg/maybe"
 	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"iter"
 )
 
 const (
@@ -36,7 +42,7 @@ The rules are as follows for Red-Black trees:
 
 // Dict represents a dictionary of keys and values.
 // So a Dict[string, User] is a dictionary that lets you look up a string (such as user names) and find the associated User.
-type Dict[K Comparable[K], V any] interface {
+type Dict[K basics.Comparable[K], V any] interface {
 	rbt() *dict[K, V]
 }
 
@@ -50,24 +56,26 @@ func (d *dict[K, V]) rbt() *dict[K, V] {
 // The raw constructor you can instantiate a Dict with.
 // This should have limited use, the Empty or Singleton
 // functions are better ways to contstruct a Dict.
-type dict[K Comparable[K], V any] struct {
+type dict[K basics.Comparable[K], V any] struct {
 	root *node[K, V]
 }
 
-type node[K Comparable[K], V any] struct {
+type node[K basics.Comparable[K], V any] struct {
 	key   K
 	value V
 	color int
+	size  int
+	owner *owner
 	left  *node[K, V]
 	right *node[K, V]
 }
 
-type stack[K Comparable[K], V any] struct {
+type stack[K basics.Comparable[K], V any] struct {
 	pp *stack[K, V]
 	p  *node[K, V]
 }
 
-type nodeStack[K Comparable[K], V any] struct {
+type nodeStack[K basics.Comparable[K], V any] struct {
 	stack *stack[K, V]
 	node  *node[K, V]
 }
@@ -75,12 +83,12 @@ type nodeStack[K Comparable[K], V any] struct {
 // BUILD
 
 // Create an empty dictionary.
-func Empty[K Comparable[K], V any]() Dict[K, V] {
+func Empty[K basics.Comparable[K], V any]() Dict[K, V] {
 	return &dict[K, V]{}
 }
 
 // Update the value of a dictionary for a specific key with a given function.
-func Update[K Comparable[K], V any](targetKey K, f func(maybe.Maybe[V]) maybe.Maybe[V], d Dict[K, V]) Dict[K, V] {
+func Update[K basics.Comparable[K], V any](targetKey K, f func(maybe.Maybe[V]) maybe.Maybe[V], d Dict[K, V]) Dict[K, V] {
 	return maybe.MaybeWith(
 		f(Get(targetKey, d)),
 		func(j maybe.Just[V]) Dict[K, V] { return Insert(targetKey, j.Value, d) },
@@ -89,13 +97,14 @@ func Update[K Comparable[K], V any](targetKey K, f func(maybe.Maybe[V]) maybe.Ma
 }
 
 // Create a dictionary with one key-value pair.
-func Singleton[K Comparable[K], V any](key K, value V) Dict[K, V] {
+func Singleton[K basics.Comparable[K], V any](key K, value V) Dict[K, V] {
 	// Root nodes are always black
 	return &dict[K, V]{
 		root: &node[K, V]{
 			key:   key,
 			value: value,
 			color: black,
+			size:  1,
 			left:  nil,
 			right: nil},
 	}
@@ -132,22 +141,24 @@ Case 4 - Parent is red and uncle is Black
 */
 
 // Insert a key-value pair into a dictionary. Replaces the value when there is a collision.
-func Insert[K Comparable[K], V any](key K, v V, d Dict[K, V]) Dict[K, V] {
-	rbt := d.rbt()
+func Insert[K basics.Comparable[K], V any](key K, v V, d Dict[K, V]) Dict[K, V] {
+	return &dict[K, V]{root: insert(nil, key, v, d.rbt().root)}
+}
 
-	if rbt.root == nil {
-		return &dict[K, V]{
-			root: &node[K, V]{
-				key: key, value: v, color: black, left: nil, right: nil,
-			},
+// Insert into the tree at root, giving back the new root.
+// Nodes belonging to the owner are changed in place, any other node is copied.
+func insert[K basics.Comparable[K], V any](o *owner, key K, v V, root *node[K, V]) *node[K, V] {
+	if root == nil {
+		return &node[K, V]{
+			key: key, value: v, color: black, size: 1, owner: o, left: nil, right: nil,
 		}
 	} else {
-		valRoot := *rbt.root
-		ns := &nodeStack[K, V]{node: &valRoot, stack: &stack[K, V]{p: nil, pp: nil}}
-		insertedNs := insertHelp(key, v, ns)
-		newNs := balance(insertedNs)
+		ns := &nodeStack[K, V]{node: edit(o, root), stack: &stack[K, V]{p: nil, pp: nil}}
+		insertedNs := insertHelp(o, key, v, ns)
+		newNs := balance(o, insertedNs)
 		rootNs := getNodeStackRoot(newNs)
-		return &dict[K, V]{root: rootNs.node}
+		fixSize(rootNs.node)
+		return rootNs.node
 	}
 }
 
@@ -188,41 +199,55 @@ Case 6 - DB sibling is black and far nephew is red
 */
 
 // Remove a key-value pair from a dictionary. If the key is not found, no changes are made.
-func Remove[K Comparable[K], V any](key K, d Dict[K, V]) Dict[K, V] {
+func Remove[K basics.Comparable[K], V any](key K, d Dict[K, V]) Dict[K, V] {
 	rbt := d.rbt()
-	if rbt.root == nil {
-		// Empty tree
+	root := remove(nil, key, rbt.root)
+	if root == rbt.root {
 		return d
 	}
+	return &dict[K, V]{root: root}
+}
+
+// Remove from the tree at root, giving back the new root.
+// Nodes belonging to the owner are changed in place, any other node is copied.
+func remove[K basics.Comparable[K], V any](o *owner, key K, root *node[K, V]) *node[K, V] {
+	if root == nil {
+		// Empty tree
+		return nil
+	}
 
 	// Find nodeStack to delete
-	maybeNodeStack := getNodeStack(rbt.root, key)
+	maybeNodeStack := getNodeStack(o, root, key)
 
 	return maybe.MaybeWith(
 		maybeNodeStack,
-		func(j maybe.Just[*nodeStack[K, V]]) Dict[K, V] {
-			ns := removeHelp(j.Value)
+		func(j maybe.Just[*nodeStack[K, V]]) *node[K, V] {
+			ns := removeHelp(o, j.Value)
 			rootNs := getNodeStackRoot(ns)
-			return &dict[K, V]{root: rootNs.node}
+			fixSize(rootNs.node)
+			return rootNs.node
+		},
+		func(n maybe.Nothing) *node[K, V] {
+			// Owned nodes on the searched path may have been marked for a new size
+			fixSize(root)
+			return root
 		},
-		func(n maybe.Nothing) Dict[K, V] { return d },
 	)
 }
 
 // Get a Just nodeStack or Nothing if node doesn't exist
-func getNodeStack[K Comparable[K], V any](n *node[K, V], targetKey K) maybe.Maybe[*nodeStack[K, V]] {
+func getNodeStack[K basics.Comparable[K], V any](o *owner, n *node[K, V], targetKey K) maybe.Maybe[*nodeStack[K, V]] {
 	if n == nil {
 		return maybe.Nothing{}
 	} else {
-		valRoot := *n
-		return getNodeStackHelp(targetKey, &nodeStack[K, V]{stack: &stack[K, V]{p: nil, pp: nil}, node: &valRoot})
+		return getNodeStackHelp(o, targetKey, &nodeStack[K, V]{stack: &stack[K, V]{p: nil, pp: nil}, node: edit(o, n)})
 	}
 }
 
 /*
 Gets a 'Just' nodeStack or 'Nothing' if it doesn't exist
 */
-func getNodeStackHelp[K Comparable[K], V any](targetKey K, ns *nodeStack[K, V]) maybe.Maybe[*node[K, V]] {
+func getNodeStackHelp[K basics.Comparable[K], V any](o *owner, targetKey K, ns *nodeStack[K, V]) maybe.Maybe[*node[K, V]] {
 getNodeStackHelpL:
 	for {
 		switch targetKey.Cmp(ns.node.key) {
@@ -231,8 +256,7 @@ getNodeStackHelpL:
 				return maybe.Nothing{}
 			} else {
 				newStack := &stack[K, V]{pp: ns.stack, p: ns.node}
-				valL := *ns.node.left
-				ns.node.left = &valL
+				ns.node.left = edit(o, ns.node.left)
 				newNs := &nodeStack[K, V]{node: ns.node.left, stack: newStack}
 				ns = newNs
 				continue getNodeStackHelpL
@@ -244,8 +268,7 @@ getNodeStackHelpL:
 				return maybe.Nothing{}
 			} else {
 				newStack := &stack[K, V]{pp: ns.stack, p: ns.node}
-				valR := *ns.node.right
-				ns.node.right = &valR
+				ns.node.right = edit(o, ns.node.right)
 				newNs := &nodeStack[K, V]{node: ns.node.right, stack: newStack}
 				ns = newNs
 				continue getNodeStackHelpL
@@ -254,20 +277,19 @@ getNodeStackHelpL:
 	}
 }
 
-func insertHelp[K Comparable[K], V any](key K, value V, ns *nodeStack[K, V]) *nodeStack[K, V] {
+func insertHelp[K basics.Comparable[K], V any](o *owner, key K, value V, ns *nodeStack[K, V]) *nodeStack[K, V] {
 insertHelpL:
 	for {
 		nKey := ns.node.key
 		switch key.Cmp(nKey) {
 		case -1:
 			if ns.node.left == nil {
-				ns.node.left = &node[K, V]{key: key, value: value, color: red, left: nil, right: nil}
+				ns.node.left = &node[K, V]{key: key, value: value, color: red, owner: o, left: nil, right: nil}
 				newStk := &stack[K, V]{p: ns.node, pp: ns.stack}
 				newNs := &nodeStack[K, V]{node: ns.node.left, stack: newStk}
 				return newNs
 			} else {
-				valL := *ns.node.left
-				ns.node.left = &valL
+				ns.node.left = edit(o, ns.node.left)
 				newStk := &stack[K, V]{p: ns.node, pp: ns.stack}
 				tempNs := &nodeStack[K, V]{node: ns.node.left, stack: newStk}
 				ns = tempNs
@@ -278,13 +300,12 @@ insertHelpL:
 			return ns
 		case +1:
 			if ns.node.right == nil {
-				ns.node.right = &node[K, V]{key: key, value: value, color: red, left: nil, right: nil}
+				ns.node.right = &node[K, V]{key: key, value: value, color: red, owner: o, left: nil, right: nil}
 				newStk := &stack[K, V]{p: ns.node, pp: ns.stack}
 				newNs := &nodeStack[K, V]{node: ns.node.right, stack: newStk}
 				return newNs
 			} else {
-				valR := *ns.node.right
-				ns.node.right = &valR
+				ns.node.right = edit(o, ns.node.right)
 				newStk := &stack[K, V]{p: ns.node, pp: ns.stack}
 				newNs := &nodeStack[K, V]{node: ns.node.right, stack: newStk}
 				ns = newNs
@@ -294,20 +315,19 @@ insertHelpL:
 	}
 }
 
-func removeHelp[K Comparable[K], V any](ns *nodeStack[K, V]) *nodeStack[K, V] {
+func removeHelp[K basics.Comparable[K], V any](o *owner, ns *nodeStack[K, V]) *nodeStack[K, V] {
 removeHelpL:
 	for {
 		// 2 non-nil children
 		if ns.node.left != nil && ns.node.right != nil {
 			// Copy right node
-			valR := *ns.node.right
-			ns.node.right = &valR
+			ns.node.right = edit(o, ns.node.right)
 
 			// Create new stack for right child
 			rightStack := &nodeStack[K, V]{node: ns.node.right, stack: &stack[K, V]{p: ns.node, pp: ns.stack}}
 
 			// Find in order successor
-			succStk := findSuccessor(rightStack)
+			succStk := findSuccessor(o, rightStack)
 
 			// Swap key and values
 			ns.node.key = succStk.node.key
@@ -340,14 +360,13 @@ removeHelpL:
 					return ns
 				}
 			case black:
-				return fixDB(ns)
+				return fixDB(o, ns)
 			}
 		}
 		// Black node with red child
 		if ns.node.left == nil {
 			// Copy right node
-			valR := *ns.node.right
-			ns.node.right = &valR
+			ns.node.right = edit(o, ns.node.right)
 
 			// Replace node with right node
 			ns.node.key = ns.node.right.key
@@ -360,8 +379,7 @@ removeHelpL:
 			continue removeHelpL
 		} else {
 			// Copy left node
-			valL := *ns.node.left
-			ns.node.left = &valL
+			ns.node.left = edit(o, ns.node.left)
 
 			// Replace node with right node
 			ns.node.key = ns.node.left.key
@@ -376,7 +394,7 @@ removeHelpL:
 	}
 }
 
-func fixDB[K Comparable[K], V any](ns *nodeStack[K, V]) *nodeStack[K, V] {
+func fixDB[K basics.Comparable[K], V any](o *owner, ns *nodeStack[K, V]) *nodeStack[K, V] {
 fixDBL:
 	for {
 		// Case 2 - DB is root
@@ -385,7 +403,7 @@ fixDBL:
 		}
 		pColor := ns.stack.p.color
 		pSide := parentSide(ns)
-		sNs := findSibling(ns)
+		sNs := findSibling(o, ns)
 
 		// DB sibling is Black
 		if sNs.node.color == black {
@@ -418,10 +436,9 @@ fixDBL:
 				if sNs.node.left.isRed() && sNs.node.right.isBlack() {
 					// Case 5 - far nephew is black - near nephew is red
 					// Copy near nephew
-					valSL := *sNs.node.left
+					sNs.node.left = edit(o, sNs.node.left)
 					// 5.1 - Swap colors of sibling and near nephew
-					valSL.color = black
-					sNs.node.left = &valSL
+					sNs.node.left.color = black
 					sNs.node.color = red
 
 					// 5.2 Rotate sibling of DB node in opposite direction of DB node
@@ -430,7 +447,6 @@ fixDBL:
 					continue fixDBL
 				} else {
 					// Case 6 - Far nephew is Red
-					valSR := *sNs.node.right
 					// 6.1 Swap the colors of the DB parent and sibling
 					ns.stack.p.color = sNs.node.color
 					sNs.node.color = pColor
@@ -439,8 +455,8 @@ fixDBL:
 					newRoot := slRotationV2(ns.stack.p, grandparentStk)
 					newNs := &nodeStack[K, V]{stack: grandparentStk, node: newRoot}
 					// 6.3 Turn far nephew's color to black
-					valSR.color = black
-					newNs.node.right = &valSR
+					newNs.node.right = edit(o, newNs.node.right)
+					newNs.node.right.color = black
 					// 6.4 Remove DB node to single black
 					if ns.node.hasNilChildren() {
 						ns.stack.p.left = nil
@@ -451,10 +467,9 @@ fixDBL:
 				if sNs.node.left.isBlack() && sNs.node.right.isRed() {
 					// Case 5 - far nephew is black - near nephew is red
 					// Copy near nephew
-					valSR := *sNs.node.right
+					sNs.node.right = edit(o, sNs.node.right)
 					// 5.1 - Swap colors of sibling and near nephew
-					valSR.color = black
-					sNs.node.right = &valSR
+					sNs.node.right.color = black
 					sNs.node.color = red
 
 					// 5.2 Rotate sibling of DB node in opposite direction of DB node
@@ -463,7 +478,6 @@ fixDBL:
 					continue fixDBL
 				} else {
 					// Case 6 - Far nephew is Red
-					valSL := *sNs.node.left
 					// 6.1 Swap the colors of the DB parent and sibling
 					ns.stack.p.color = sNs.node.color
 					sNs.node.color = pColor
@@ -472,8 +486,8 @@ fixDBL:
 					newRoot := srRotationV2(ns.stack.p, grandparentStk)
 					newNs := &nodeStack[K, V]{stack: grandparentStk, node: newRoot}
 					// 6.3 Turn far nephew's color to black
-					valSL.color = black
-					newNs.node.left = &valSL
+					newNs.node.left = edit(o, newNs.node.left)
+					newNs.node.left.color = black
 					// 6.4 Remove DB node to single black
 					if ns.node.hasNilChildren() {
 						ns.stack.p.right = nil
@@ -520,32 +534,67 @@ func (n *node[K, V]) isRed() bool {
 	return n != nil && n.color == red
 }
 
-func findSuccessor[K Comparable[K], V any](ns *nodeStack[K, V]) *nodeStack[K, V] {
+/*
+Every node knows the size of its subtree.
+
+Insert and Remove edit the nodes they change, and edited nodes have a size of 0 until
+the tree is balanced. Unchanged subtrees keep their sizes, so fixSize only has to
+walk the copied nodes, which all sit along the path that was changed.
+*/
+
+// Get a node that can be changed without touching any other tree. A node that already
+// belongs to the owner is given back as is, any other node is copied for the owner.
+func edit[K basics.Comparable[K], V any](o *owner, n *node[K, V]) *node[K, V] {
+	if o == nil || n.owner != o {
+		c := *n
+		c.owner = o
+		n = &c
+	}
+	n.size = 0
+	return n
+}
+
+// Compute the size of any node with a size of 0.
+func fixSize[K basics.Comparable[K], V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	if n.size == 0 {
+		n.size = 1 + fixSize(n.left) + fixSize(n.right)
+	}
+	return n.size
+}
+
+func sizeOf[K basics.Comparable[K], V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func findSuccessor[K basics.Comparable[K], V any](o *owner, ns *nodeStack[K, V]) *nodeStack[K, V] {
 	if ns.node.left == nil {
 		return ns
 	} else {
-		valL := *ns.node.left
-		ns.node.left = &valL
+		ns.node.left = edit(o, ns.node.left)
 		newStack := &nodeStack[K, V]{node: ns.node.left, stack: &stack[K, V]{p: ns.node, pp: ns.stack}}
-		return findSuccessor(newStack)
+		return findSuccessor(o, newStack)
 	}
 }
 
 // Find sibling nodeStack
-func findSibling[K Comparable[K], V any](ns *nodeStack[K, V]) *nodeStack[K, V] {
+func findSibling[K basics.Comparable[K], V any](o *owner, ns *nodeStack[K, V]) *nodeStack[K, V] {
 	pDir := parentSide(ns)
 	if pDir == left {
-		valR := *ns.stack.p.right
-		ns.stack.p.right = &valR
+		ns.stack.p.right = edit(o, ns.stack.p.right)
 		return &nodeStack[K, V]{stack: ns.stack, node: ns.stack.p.right}
 	} else {
-		valL := *ns.stack.p.left
-		ns.stack.p.left = &valL
+		ns.stack.p.left = edit(o, ns.stack.p.left)
 		return &nodeStack[K, V]{stack: ns.stack, node: ns.stack.p.left}
 	}
 }
 
-func balance[K Comparable[K], V any](ns *nodeStack[K, V]) *nodeStack[K, V] {
+func balance[K basics.Comparable[K], V any](o *owner, ns *nodeStack[K, V]) *nodeStack[K, V] {
 balanceL:
 	for {
 		// Root case
@@ -553,6 +602,10 @@ balanceL:
 			ns.node.color = black
 			return ns
 		}
+		if ns.node.color == black {
+			// Replaced values and rotated subtrees with a black root are already balanced
+			return ns
+		}
 		pColor := ns.stack.p.color
 		if pColor == black {
 			// Nothing more to do
@@ -568,8 +621,7 @@ balanceL:
 			// Red uncle - push down blackness from grandparent - balance root
 
 			// Copy uncle for mutation
-			valU := *uncle
-			cpU := &valU
+			cpU := edit(o, uncle)
 
 			cpU.color = grandparent.color
 			ns.stack.p.color = grandparent.color
@@ -636,7 +688,7 @@ balanceL:
 	}
 }
 
-func srRotationV2[K Comparable[K], V any](x *node[K, V], stk *stack[K, V]) *node[K, V] {
+func srRotationV2[K basics.Comparable[K], V any](x *node[K, V], stk *stack[K, V]) *node[K, V] {
 	leftNode := x.left
 
 	// Handle x's parent
@@ -659,7 +711,7 @@ func srRotationV2[K Comparable[K], V any](x *node[K, V], stk *stack[K, V]) *node
 	return leftNode
 }
 
-func slRotationV2[K Comparable[K], V any](x *node[K, V], stk *stack[K, V]) *node[K, V] {
+func slRotationV2[K basics.Comparable[K], V any](x *node[K, V], stk *stack[K, V]) *node[K, V] {
 	rightNode := x.right
 
 	// Handle x's parent
@@ -682,16 +734,16 @@ func slRotationV2[K Comparable[K], V any](x *node[K, V], stk *stack[K, V]) *node
 	return rightNode
 }
 
-func parentSide[K Comparable[K], V any](ns *nodeStack[K, V]) int {
+func parentSide[K basics.Comparable[K], V any](ns *nodeStack[K, V]) int {
 	p := ns.stack.p
-	if p.left != nil && Eq(ns.node.key, p.left.key) {
+	if p.left != nil && basics.Eq(ns.node.key, p.left.key) {
 		return left
 	} else {
 		return right
 	}
 }
 
-func setUncle[K Comparable[K], V any](ns *nodeStack[K, V], unc *node[K, V]) {
+func setUncle[K basics.Comparable[K], V any](ns *nodeStack[K, V], unc *node[K, V]) {
 	parent := ns.stack.p
 	gp := ns.stack.pp.p
 
@@ -703,7 +755,7 @@ func setUncle[K Comparable[K], V any](ns *nodeStack[K, V], unc *node[K, V]) {
 	}
 }
 
-func getUncle[K Comparable[K], V any](ns *nodeStack[K, V]) *node[K, V] {
+func getUncle[K basics.Comparable[K], V any](ns *nodeStack[K, V]) *node[K, V] {
 	parent := ns.stack.p
 	gp := ns.stack.pp.p
 	if parentSide(&nodeStack[K, V]{node: parent, stack: ns.stack.pp}) == left {
@@ -714,7 +766,7 @@ func getUncle[K Comparable[K], V any](ns *nodeStack[K, V]) *node[K, V] {
 	}
 }
 
-func getNodeStackRoot[K Comparable[K], V any](ns *nodeStack[K, V]) *nodeStack[K, V] {
+func getNodeStackRoot[K basics.Comparable[K], V any](ns *nodeStack[K, V]) *nodeStack[K, V] {
 	if ns.stack.p == nil {
 		return ns
 	} else {
@@ -723,7 +775,7 @@ func getNodeStackRoot[K Comparable[K], V any](ns *nodeStack[K, V]) *nodeStack[K,
 	}
 }
 
-func getStackHelp[K Comparable[K], V any](k K, n *node[K, V], st *stack[K, V]) (*stack[K, V], error) {
+func getStackHelp[K basics.Comparable[K], V any](k K, n *node[K, V], st *stack[K, V]) (*stack[K, V], error) {
 getNodeStackHelpL:
 	for {
 		switch k.Cmp(n.key) {
@@ -749,15 +801,72 @@ getNodeStackHelpL:
 	}
 }
 
+// BUILDER
+
+// A Builder makes a dictionary by changing it in place, skipping the copying done by Insert and Remove.
+// This is useful when building a fresh dictionary inside one function. Call Freeze to get the
+// dictionary, which never changes afterwards, even when the builder is used again.
+//
+// A Builder is not safe for use by multiple goroutines at the same time.
+type Builder[K basics.Comparable[K], V any] struct {
+	owner *owner
+	root  *node[K, V]
+}
+
+// Nodes made or copied by a builder belong to its owner and can be changed in place until
+// the builder is frozen. The byte keeps every owner at a different address.
+type owner struct {
+	_ byte
+}
+
+// Create an empty builder.
+func NewBuilder[K basics.Comparable[K], V any]() *Builder[K, V] {
+	return &Builder[K, V]{owner: &owner{}}
+}
+
+// Create a builder starting with the key-value pairs of a dictionary.
+// The dictionary is left untouched.
+func NewBuilderFrom[K basics.Comparable[K], V any](d Dict[K, V]) *Builder[K, V] {
+	return &Builder[K, V]{owner: &owner{}, root: d.rbt().root}
+}
+
+// Insert a key-value pair. Replaces the value when there is a collision.
+func (b *Builder[K, V]) Insert(key K, v V) {
+	b.root = insert(b.owner, key, v, b.root)
+}
+
+// Remove a key-value pair. If the key is not found, no changes are made.
+func (b *Builder[K, V]) Remove(key K) {
+	b.root = remove(b.owner, key, b.root)
+}
+
+// Get the value associated with a key.
+func (b *Builder[K, V]) Get(key K) maybe.Maybe[V] {
+	return getHelp(key, b.root)
+}
+
+// Determine the number of key-value pairs in the builder.
+func (b *Builder[K, V]) Size() basics.Int {
+	return basics.Int(sizeOf(b.root))
+}
+
+// Get the dictionary built so far. The builder can keep being used, but
+// its changes will not show up in the frozen dictionary.
+func (b *Builder[K, V]) Freeze() Dict[K, V] {
+	// A new owner means every node built so far is copied before it changes again
+	b.owner = &owner{}
+	return &dict[K, V]{root: b.root}
+}
+
 // QUERY
 
 // Determine if a dictionary is empty.
-func IsEmpty[K Comparable[K], V any](d Dict[K, V]) bool {
+func IsEmpty[K basics.Comparable[K], V any](d Dict[K, V]) bool {
 	return d.rbt().root == nil
 }
 
 // Member - Determine if a key is in a dictionary.
-func Member[K Comparable[K], V any](k K, d Dict[K, V]) bool {
+func Member[K basics.Comparable[K], V any](k K, d Dict[K, V]) bool {
 	rbt := d.rbt()
 
 	if rbt.root == nil {
@@ -767,7 +876,7 @@ func Member[K Comparable[K], V any](k K, d Dict[K, V]) bool {
 	}
 }
 
-func memberHelp[K Comparable[K], V any](k K, n *node[K, V]) bool {
+func memberHelp[K basics.Comparable[K], V any](k K, n *node[K, V]) bool {
 memberHelpL:
 	for {
 		if n == nil {
@@ -790,7 +899,7 @@ memberHelpL:
 // Get the value associated with a key.
 // If the key is not found, return [Nothing].
 // This is useful when you are not sure if a key will be in the dictionary.
-func Get[K Comparable[K], V any](targetKey K, d Dict[K, V]) maybe.Maybe[V] {
+func Get[K basics.Comparable[K], V any](targetKey K, d Dict[K, V]) maybe.Maybe[V] {
 	rbt := d.rbt()
 	if rbt.root == nil {
 		return maybe.Nothing{}
@@ -800,32 +909,12 @@ func Get[K Comparable[K], V any](targetKey K, d Dict[K, V]) maybe.Maybe[V] {
 }
 
 // Determine the number of key-value pairs in the dictionary.
-func Size[K Comparable[K], V any](d Dict[K, V]) Int {
-	rbt := d.rbt()
-
-	if rbt.root == nil {
-		return 0
-	}
-	queue := []*node[K, V]{rbt.root}
-	count := 0
-
-	for len(queue) > 0 {
-		node := queue[0]
-		queue = queue[1:] // Dequeue
-		count++
-
-		if node.left != nil {
-			queue = append(queue, node.left)
-		}
-		if node.right != nil {
-			queue = append(queue, node.right)
-		}
-	}
-
-	return Int(count)
+// This takes O(1) time.
+func Size[K basics.Comparable[K], V any](d Dict[K, V]) basics.Int {
+	return basics.Int(sizeOf(d.rbt().root))
 }
 
-func getHelp[K Comparable[K], V any](targetKey K, n *node[K, V]) maybe.Maybe[V] {
+func getHelp[K basics.Comparable[K], V any](targetKey K, n *node[K, V]) maybe.Maybe[V] {
 getHelpL:
 	for {
 		if n == nil {
@@ -845,10 +934,443 @@ getHelpL:
 	}
 }
 
+// NAVIGATION
+
+// Bound is one end of a key range given to [Range] or [FoldlRange].
+type Bound[K basics.Comparable[K]] interface {
+	bound() _bound
+}
+
+type _bound struct{}
+
+func (b _bound) bound() _bound {
+	return b
+}
+
+// Inclusive - A bound that includes its key in the range.
+type Inclusive[K basics.Comparable[K]] struct {
+	_bound
+	Key K
+}
+
+// Exclusive - A bound that excludes its key from the range.
+type Exclusive[K basics.Comparable[K]] struct {
+	_bound
+	Key K
+}
+
+// Unbounded - No bound, the range extends to the lowest or highest key.
+type Unbounded struct {
+	_bound
+}
+
+// Get the key-value pair with the lowest key, or Nothing if the dictionary is empty.
+func Min[K basics.Comparable[K], V any](d Dict[K, V]) maybe.Maybe[tuple.Tuple2[K, V]] {
+	n := d.rbt().root
+	if n == nil {
+		return maybe.Nothing{}
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return toMaybePair(n)
+}
+
+// Get the key-value pair with the highest key, or Nothing if the dictionary is empty.
+func Max[K basics.Comparable[K], V any](d Dict[K, V]) maybe.Maybe[tuple.Tuple2[K, V]] {
+	n := d.rbt().root
+	if n == nil {
+		return maybe.Nothing{}
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return toMaybePair(n)
+}
+
+// Get the key-value pair with the highest key less than or equal to the given key.
+func Floor[K basics.Comparable[K], V any](k K, d Dict[K, V]) maybe.Maybe[tuple.Tuple2[K, V]] {
+	return toMaybePair(floorHelp(k, true, d.rbt().root))
+}
+
+// Get the key-value pair with the lowest key greater than or equal to the given key.
+func Ceiling[K basics.Comparable[K], V any](k K, d Dict[K, V]) maybe.Maybe[tuple.Tuple2[K, V]] {
+	return toMaybePair(ceilingHelp(k, true, d.rbt().root))
+}
+
+// Get the key-value pair with the highest key strictly less than the given key.
+func Lower[K basics.Comparable[K], V any](k K, d Dict[K, V]) maybe.Maybe[tuple.Tuple2[K, V]] {
+	return toMaybePair(floorHelp(k, false, d.rbt().root))
+}
+
+// Get the key-value pair with the lowest key strictly greater than the given key.
+func Higher[K basics.Comparable[K], V any](k K, d Dict[K, V]) maybe.Maybe[tuple.Tuple2[K, V]] {
+	return toMaybePair(ceilingHelp(k, false, d.rbt().root))
+}
+
+func floorHelp[K basics.Comparable[K], V any](k K, inclusive bool, n *node[K, V]) *node[K, V] {
+	var found *node[K, V]
+	for n != nil {
+		ord := k.Cmp(n.key)
+		if ord == 0 && inclusive {
+			return n
+		} else if ord > 0 {
+			found = n
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return found
+}
+
+func ceilingHelp[K basics.Comparable[K], V any](k K, inclusive bool, n *node[K, V]) *node[K, V] {
+	var found *node[K, V]
+	for n != nil {
+		ord := k.Cmp(n.key)
+		if ord == 0 && inclusive {
+			return n
+		} else if ord < 0 {
+			found = n
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return found
+}
+
+func toMaybePair[K basics.Comparable[K], V any](n *node[K, V]) maybe.Maybe[tuple.Tuple2[K, V]] {
+	if n == nil {
+		return maybe.Nothing{}
+	}
+	return maybe.Just[tuple.Tuple2[K, V]]{Value: tuple.Pair(n.key, n.value)}
+}
+
+// Split a dictionary at a key. Gives back the dictionary of keys lower than the key,
+// the value at the key if there is one, and the dictionary of keys higher than the key.
+// This takes O(log n) time.
+func Split[K basics.Comparable[K], V any](k K, d Dict[K, V]) tuple.Tuple3[Dict[K, V], maybe.Maybe[V], Dict[K, V]] {
+	l, m, r := split(k, d.rbt().root)
+	var value maybe.Maybe[V] = maybe.Nothing{}
+	if m != nil {
+		value = maybe.Just[V]{Value: m.value}
+	}
+	return tuple.Triple(fromRoot(l), value, fromRoot(r))
+}
+
+// Keep only the keys between the lower and upper bounds.
+// This takes O(log n) time, regardless of how many keys are in the range.
+//
+//	Range(Inclusive[Int]{Key: 2}, Exclusive[Int]{Key: 5}, d) // keys 2, 3 and 4
+//	Range(Exclusive[Int]{Key: 2}, Unbounded{}, d)            // keys higher than 2
+func Range[K basics.Comparable[K], V any](lo Bound[K], hi Bound[K], d Dict[K, V]) Dict[K, V] {
+	return fromRoot(dropAbove(hi, dropBelow(lo, d.rbt().root)))
+}
+
+func dropBelow[K basics.Comparable[K], V any](lo Bound[K], t *node[K, V]) *node[K, V] {
+	switch b := lo.(type) {
+	case Inclusive[K]:
+		_, m, r := split(b.Key, t)
+		if m != nil {
+			return join(nil, m.key, m.value, r)
+		}
+		return r
+	case Exclusive[K]:
+		_, _, r := split(b.Key, t)
+		return r
+	default:
+		return t
+	}
+}
+
+func dropAbove[K basics.Comparable[K], V any](hi Bound[K], t *node[K, V]) *node[K, V] {
+	switch b := hi.(type) {
+	case Inclusive[K]:
+		l, m, _ := split(b.Key, t)
+		if m != nil {
+			return join(l, m.key, m.value, nil)
+		}
+		return l
+	case Exclusive[K]:
+		l, _, _ := split(b.Key, t)
+		return l
+	default:
+		return t
+	}
+}
+
+// Fold over the key-value pairs between the lower and upper bounds, from lowest key to highest key.
+// Only the part of the tree inside the range is visited, so this takes O(log n + k) time for k pairs in the range.
+func FoldlRange[K basics.Comparable[K], V, B any](f func(K, V, B) B, acc B, lo Bound[K], hi Bound[K], d Dict[K, V]) B {
+	return foldlRangeHelp(f, acc, lo, hi, d.rbt().root)
+}
+
+func foldlRangeHelp[K basics.Comparable[K], V, B any](f func(K, V, B) B, acc B, lo Bound[K], hi Bound[K], t *node[K, V]) B {
+	if t == nil {
+		return acc
+	}
+	aboveLo, belowHi := isAboveLower(lo, t.key), isBelowUpper(hi, t.key)
+	if aboveLo {
+		acc = foldlRangeHelp(f, acc, lo, hi, t.left)
+	}
+	if aboveLo && belowHi {
+		acc = f(t.key, t.value, acc)
+	}
+	if belowHi {
+		acc = foldlRangeHelp(f, acc, lo, hi, t.right)
+	}
+	return acc
+}
+
+func isAboveLower[K basics.Comparable[K]](lo Bound[K], k K) bool {
+	switch b := lo.(type) {
+	case Inclusive[K]:
+		return k.Cmp(b.Key) >= 0
+	case Exclusive[K]:
+		return k.Cmp(b.Key) > 0
+	default:
+		return true
+	}
+}
+
+func isBelowUpper[K basics.Comparable[K]](hi Bound[K], k K) bool {
+	switch b := hi.(type) {
+	case Inclusive[K]:
+		return k.Cmp(b.Key) <= 0
+	case Exclusive[K]:
+		return k.Cmp(b.Key) < 0
+	default:
+		return true
+	}
+}
+
+// ORDER STATISTICS
+
+// Get the number of keys in a dictionary that are lower than the given key.
+// The key does not have to be in the dictionary. This takes O(log n) time.
+func Rank[K basics.Comparable[K], V any](k K, d Dict[K, V]) basics.Int {
+	n := d.rbt().root
+	rank := 0
+	for n != nil {
+		if k.Cmp(n.key) <= 0 {
+			n = n.left
+		} else {
+			rank += sizeOf(n.left) + 1
+			n = n.right
+		}
+	}
+	return basics.Int(rank)
+}
+
+// Get the key-value pair at an index, where index 0 is the lowest key.
+// Returns Nothing when the index is out of range. This takes O(log n) time.
+func At[K basics.Comparable[K], V any](i basics.Int, d Dict[K, V]) maybe.Maybe[tuple.Tuple2[K, V]] {
+	return toMaybePair(atHelp(int(i), d.rbt().root))
+}
+
+func atHelp[K basics.Comparable[K], V any](i int, n *node[K, V]) *node[K, V] {
+	if i < 0 || i >= sizeOf(n) {
+		return nil
+	}
+	for {
+		leftSize := sizeOf(n.left)
+		if i < leftSize {
+			n = n.left
+		} else if i == leftSize {
+			return n
+		} else {
+			i -= leftSize + 1
+			n = n.right
+		}
+	}
+}
+
+// Keep the n key-value pairs with the lowest keys. This takes O(log n) time.
+func TakeSmallest[K basics.Comparable[K], V any](n basics.Int, d Dict[K, V]) Dict[K, V] {
+	l, _ := splitAt(int(n), d.rbt().root)
+	return fromRoot(l)
+}
+
+// Drop the n key-value pairs with the lowest keys. This takes O(log n) time.
+func DropSmallest[K basics.Comparable[K], V any](n basics.Int, d Dict[K, V]) Dict[K, V] {
+	_, r := splitAt(int(n), d.rbt().root)
+	return fromRoot(r)
+}
+
+// Get the key-value pair at a percentile of the keys, using the nearest-rank method.
+// The percentile is clamped between 0 and 100, where 0 is the lowest key and 100 is the highest.
+// Returns Nothing if the dictionary is empty.
+func Percentile[K basics.Comparable[K], V any](p basics.Float, d Dict[K, V]) maybe.Maybe[tuple.Tuple2[K, V]] {
+	size := Size(d)
+	rank := basics.Ceiling[basics.Int](basics.Clamp(0, 100, p) / 100 * basics.ToFloat(size))
+	return At(basics.Max(rank-1, 0), d)
+}
+
+// Get the key-value pair at the median key. When there is an even number of keys,
+// this is the lower of the two middle keys.
+func Median[K basics.Comparable[K], V any](d Dict[K, V]) maybe.Maybe[tuple.Tuple2[K, V]] {
+	return At((Size(d)-1)/2, d)
+}
+
+/*
+Split and join
+
+Join takes two trees and a key that sits between them and builds a balanced tree,
+walking down the spine of the taller tree until the black heights match.
+Split walks down to a key joining the subtrees it passes on either side.
+
+Both take O(log n) time and build new nodes along the walked path, leaving the
+original trees untouched.
+*/
+
+func newNode[K basics.Comparable[K], V any](key K, value V, color int, l *node[K, V], r *node[K, V]) *node[K, V] {
+	return &node[K, V]{key: key, value: value, color: color, size: 1 + sizeOf(l) + sizeOf(r), left: l, right: r}
+}
+
+func withColor[K basics.Comparable[K], V any](color int, n *node[K, V]) *node[K, V] {
+	return newNode(n.key, n.value, color, n.left, n.right)
+}
+
+// Roots of a Dict are always black.
+func fromRoot[K basics.Comparable[K], V any](n *node[K, V]) Dict[K, V] {
+	if n.isRed() {
+		n = withColor(black, n)
+	}
+	return &dict[K, V]{root: n}
+}
+
+func blackHeight[K basics.Comparable[K], V any](n *node[K, V]) int {
+	h := 0
+	for ; n != nil; n = n.left {
+		if n.color == black {
+			h++
+		}
+	}
+	return h
+}
+
+func join[K basics.Comparable[K], V any](l *node[K, V], key K, value V, r *node[K, V]) *node[K, V] {
+	lh, rh := blackHeight(l), blackHeight(r)
+	if lh > rh {
+		t := joinRight(l, lh, key, value, r, rh)
+		if t.isRed() && t.right.isRed() {
+			return withColor(black, t)
+		}
+		return t
+	} else if lh < rh {
+		t := joinLeft(l, lh, key, value, r, rh)
+		if t.isRed() && t.left.isRed() {
+			return withColor(black, t)
+		}
+		return t
+	} else if l.isBlack() && r.isBlack() {
+		return newNode(key, value, red, l, r)
+	} else {
+		return newNode(key, value, black, l, r)
+	}
+}
+
+// Join a shorter right tree onto the right spine of the left tree.
+func joinRight[K basics.Comparable[K], V any](l *node[K, V], lh int, key K, value V, r *node[K, V], rh int) *node[K, V] {
+	if lh == rh && l.isBlack() {
+		return newNode(key, value, red, l, r)
+	}
+	childHeight := lh
+	if l.color == black {
+		childHeight--
+	}
+	t := newNode(l.key, l.value, l.color, l.left, joinRight(l.right, childHeight, key, value, r, rh))
+	if l.color == black && t.right.isRed() && t.right.right.isRed() {
+		t.right = newNode(t.right.key, t.right.value, red, t.right.left, withColor(black, t.right.right))
+		return rotateLeft(t)
+	}
+	return t
+}
+
+// Join a shorter left tree onto the left spine of the right tree.
+func joinLeft[K basics.Comparable[K], V any](l *node[K, V], lh int, key K, value V, r *node[K, V], rh int) *node[K, V] {
+	if lh == rh && r.isBlack() {
+		return newNode(key, value, red, l, r)
+	}
+	childHeight := rh
+	if r.color == black {
+		childHeight--
+	}
+	t := newNode(r.key, r.value, r.color, joinLeft(l, lh, key, value, r.left, childHeight), r.right)
+	if r.color == black && t.left.isRed() && t.left.left.isRed() {
+		t.left = newNode(t.left.key, t.left.value, red, withColor(black, t.left.left), t.left.right)
+		return rotateRight(t)
+	}
+	return t
+}
+
+func rotateLeft[K basics.Comparable[K], V any](t *node[K, V]) *node[K, V] {
+	r := t.right
+	return newNode(r.key, r.value, r.color, newNode(t.key, t.value, t.color, t.left, r.left), r.right)
+}
+
+func rotateRight[K basics.Comparable[K], V any](t *node[K, V]) *node[K, V] {
+	l := t.left
+	return newNode(l.key, l.value, l.color, l.left, newNode(t.key, t.value, t.color, l.right, t.right))
+}
+
+// Split a tree into the keys lower than k, the node at k if there is one, and the keys higher than k.
+func split[K basics.Comparable[K], V any](k K, t *node[K, V]) (*node[K, V], *node[K, V], *node[K, V]) {
+	if t == nil {
+		return nil, nil, nil
+	}
+	ord := k.Cmp(t.key)
+	if ord == 0 {
+		return t.left, t, t.right
+	} else if ord < 0 {
+		l, m, r := split(k, t.left)
+		return l, m, join(r, t.key, t.value, t.right)
+	} else {
+		l, m, r := split(k, t.right)
+		return join(t.left, t.key, t.value, l), m, r
+	}
+}
+
+// Join two trees where every key of the left tree is lower than every key of the right tree.
+func join2[K basics.Comparable[K], V any](l *node[K, V], r *node[K, V]) *node[K, V] {
+	if l == nil {
+		return r
+	}
+	rest, last := splitLast(l)
+	return join(rest, last.key, last.value, r)
+}
+
+// Split the node with the highest key from the rest of a tree.
+func splitLast[K basics.Comparable[K], V any](t *node[K, V]) (*node[K, V], *node[K, V]) {
+	if t.right == nil {
+		return t.left, t
+	}
+	rest, last := splitLast(t.right)
+	return join(t.left, t.key, t.value, rest), last
+}
+
+// Split a tree into its lowest i nodes and the rest.
+func splitAt[K basics.Comparable[K], V any](i int, t *node[K, V]) (*node[K, V], *node[K, V]) {
+	if i <= 0 {
+		return nil, t
+	} else if i >= sizeOf(t) {
+		return t, nil
+	}
+	leftSize := sizeOf(t.left)
+	if i <= leftSize {
+		l, r := splitAt(i, t.left)
+		return l, join(r, t.key, t.value, t.right)
+	} else {
+		l, r := splitAt(i-leftSize-1, t.right)
+		return join(t.left, t.key, t.value, l), r
+	}
+}
+
 // LISTS
 
 // Get all of the keys in a dictionary, sorted from lowest to highest.
-func Keys[K Comparable[K], V any](d Dict[K, V]) list.List[K] {
+func Keys[K basics.Comparable[K], V any](d Dict[K, V]) list.List[K] {
 	return Foldr(
 		func(key K, _ V, keyList list.List[K]) list.List[K] {
 			return list.Cons(key, keyList)
@@ -859,7 +1381,7 @@ func Keys[K Comparable[K], V any](d Dict[K, V]) list.List[K] {
 }
 
 // Get all of the values in a dictionary, in the order of their keys.
-func Values[K Comparable[K], V any](d Dict[K, V]) list.List[V] {
+func Values[K basics.Comparable[K], V any](d Dict[K, V]) list.List[V] {
 	return Foldr(
 		func(_ K, value V, valueList list.List[V]) list.List[V] {
 			return list.Cons(value, valueList)
@@ -870,7 +1392,7 @@ func Values[K Comparable[K], V any](d Dict[K, V]) list.List[V] {
 }
 
 // Convert a dictionary into an association list of key-value pairs, sorted by keys.
-func ToList[K Comparable[K], V any](d Dict[K, V]) list.List[tuple.Tuple2[K, V]] {
+func ToList[K basics.Comparable[K], V any](d Dict[K, V]) list.List[tuple.Tuple2[K, V]] {
 	return Foldr(
 		func(key K, value V, xs list.List[tuple.Tuple2[K, V]]) list.List[tuple.Tuple2[K, V]] {
 			return list.Cons(tuple.Pair(key, value), xs)
@@ -881,7 +1403,13 @@ func ToList[K Comparable[K], V any](d Dict[K, V]) list.List[tuple.Tuple2[K, V]]
 }
 
 // Convert an association list into a dictionary.
-func FromList[K Comparable[K], V any](l list.List[tuple.Tuple2[K, V]]) Dict[K, V] {
+// When the list is already sorted by key this takes O(n) time, otherwise O(n log n).
+// If a key appears more than once, the last value is kept.
+func FromList[K basics.Comparable[K], V any](l list.List[tuple.Tuple2[K, V]]) Dict[K, V] {
+	xs := list.ToSlice(l)
+	if sorted, ok := dedupeSorted(xs); ok {
+		return fromSorted(sorted)
+	}
 	return list.Foldl(
 		func(t tuple.Tuple2[K, V], d Dict[K, V]) Dict[K, V] {
 			return Insert(tuple.First(t), tuple.Second(t), d)
@@ -891,10 +1419,183 @@ func FromList[K Comparable[K], V any](l list.List[tuple.Tuple2[K, V]]) Dict[K, V
 	)
 }
 
+// Convert an association list that is sorted by key, from lowest to highest and
+// without duplicate keys, into a dictionary. This takes O(n) time.
+// Panics if the keys are not sorted or have duplicates.
+func FromSortedList[K basics.Comparable[K], V any](l list.List[tuple.Tuple2[K, V]]) Dict[K, V] {
+	return FromSortedSlice(list.ToSlice(l))
+}
+
+// Convert a slice of key-value pairs that is sorted by key, from lowest to highest and
+// without duplicate keys, into a dictionary. This takes O(n) time.
+// Panics if the keys are not sorted or have duplicates.
+func FromSortedSlice[K basics.Comparable[K], V any](xs []tuple.Tuple2[K, V]) Dict[K, V] {
+	for i := 1; i < len(xs); i++ {
+		if tuple.First(xs[i-1]).Cmp(tuple.First(xs[i])) >= 0 {
+			panic("FromSortedSlice: keys must be sorted from lowest to highest without duplicates")
+		}
+	}
+	return fromSorted(xs)
+}
+
+// Drop all but the last pair of each key, if the keys are in ascending order.
+// The slice is changed in place.
+func dedupeSorted[K basics.Comparable[K], V any](xs []tuple.Tuple2[K, V]) ([]tuple.Tuple2[K, V], bool) {
+	n := 0
+	for i, x := range xs {
+		if i == 0 {
+			n++
+			continue
+		}
+		switch tuple.First(xs[n-1]).Cmp(tuple.First(x)) {
+		case -1:
+			xs[n] = x
+			n++
+		case 0:
+			xs[n-1] = x
+		default:
+			return nil, false
+		}
+	}
+	return xs[:n], true
+}
+
+/*
+Building from sorted pairs
+
+The middle pair becomes the root and each half is built the same way, so every
+path from the root is either h or h+1 nodes long. Making every node at depth h+1
+red keeps the black height equal along all paths.
+*/
+
+func fromSorted[K basics.Comparable[K], V any](xs []tuple.Tuple2[K, V]) Dict[K, V] {
+	return &dict[K, V]{root: fromSortedHelp(xs, 0, redDepth(len(xs)))}
+}
+
+func fromSortedHelp[K basics.Comparable[K], V any](xs []tuple.Tuple2[K, V], depth int, redAt int) *node[K, V] {
+	if len(xs) == 0 {
+		return nil
+	}
+	mid := len(xs) / 2
+	l := fromSortedHelp(xs[:mid], depth+1, redAt)
+	r := fromSortedHelp(xs[mid+1:], depth+1, redAt)
+	color := black
+	if depth == redAt {
+		color = red
+	}
+	return newNode(tuple.First(xs[mid]), tuple.Second(xs[mid]), color, l, r)
+}
+
+// The depth of the lowest level of a tree of n nodes built from the middle out,
+// which is only full when n is one less than a power of two.
+func redDepth(n int) int {
+	depth := -1
+	for m := n; m > 0; m /= 2 {
+		depth++
+	}
+	if n&(n+1) == 0 {
+		// A perfect tree has no partial level
+		return -1
+	}
+	return depth
+}
+
+// EQUALITY
+
+// Dictionaries are equal when they have the same keys with equal values, however their trees are shaped.
+// Keys are compared with Cmp and values with basics.Eq. This takes O(n) time.
+func (d *dict[K, V]) Equal(y Dict[K, V]) bool {
+	if sizeOf(d.root) != sizeOf(y.rbt().root) {
+		return false
+	}
+	left, right := newIterator(d.root), newIterator(y.rbt().root)
+	for l, r := left.next(), right.next(); l != nil; l, r = left.next(), right.next() {
+		if l.key.Cmp(r.key) != 0 || !basics.Eq(l.value, r.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// The hash only depends on the key-value pairs, so equal dictionaries have the same hash.
+func (d *dict[K, V]) Hash() uint64 {
+	h := hashing.Uint64(uint64(sizeOf(d.root)))
+	for k, v := range All[K, V](d) {
+		h = hashing.Combine(h, hashing.Combine(basics.Hash(k), basics.Hash(v)))
+	}
+	return h
+}
+
+// ITERATORS
+
+// Get a sequence of the key-value pairs in a dictionary, from lowest key to highest key.
+// The tree is walked lazily, so stopping early skips the rest of the dictionary.
+//
+//	for k, v := range All(d) {
+//		fmt.Println(k, v)
+//	}
+func All[K basics.Comparable[K], V any](d Dict[K, V]) iter.Seq2[K, V] {
+	return RangeSeq(Unbounded{}, Unbounded{}, d)
+}
+
+// Get a sequence of the keys in a dictionary, sorted from lowest to highest.
+func KeysSeq[K basics.Comparable[K], V any](d Dict[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range All(d) {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Get a sequence of the values in a dictionary, in the order of their keys.
+func ValuesSeq[K basics.Comparable[K], V any](d Dict[K, V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range All(d) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Get a sequence of the key-value pairs between the lower and upper bounds, from lowest key to highest key.
+// Finding the first pair takes O(log n) time, and each pair after it takes O(1) amortized time.
+func RangeSeq[K basics.Comparable[K], V any](lo Bound[K], hi Bound[K], d Dict[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		it := &iterator[K, V]{}
+		// Keep the path to the lowest key inside the lower bound
+		for n := d.rbt().root; n != nil; {
+			if isAboveLower(lo, n.key) {
+				it.path = append(it.path, n)
+				n = n.left
+			} else {
+				n = n.right
+			}
+		}
+		for n := it.next(); n != nil && isBelowUpper(hi, n.key); n = it.next() {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Build a dictionary from a sequence of key-value pairs.
+// If a key appears more than once, the last value is kept.
+func Collect[K basics.Comparable[K], V any](seq iter.Seq2[K, V]) Dict[K, V] {
+	b := NewBuilder[K, V]()
+	for k, v := range seq {
+		b.Insert(k, v)
+	}
+	return b.Freeze()
+}
+
 // TRANSFORM
 
 // Apply a function to all values in a dictionary.
-func Map[K Comparable[K], V, B any](f func(key K, value V) B, d Dict[K, V]) Dict[K, B] {
+func Map[K basics.Comparable[K], V, B any](f func(key K, value V) B, d Dict[K, V]) Dict[K, B] {
 	if d.rbt().root == nil {
 		return Empty[K, B]()
 	} else {
@@ -902,20 +1603,20 @@ func Map[K Comparable[K], V, B any](f func(key K, value V) B, d Dict[K, V]) Dict
 	}
 }
 
-func mapHelp[K Comparable[K], V, B any](f func(K, V) B, t *node[K, V]) *node[K, B] {
+func mapHelp[K basics.Comparable[K], V, B any](f func(K, V) B, t *node[K, V]) *node[K, B] {
 	if t == nil {
 		return nil
 	} else {
-		return &node[K, B]{key: t.key, value: f(t.key, t.value), color: t.color, left: mapHelp(f, t.left), right: mapHelp(f, t.right)}
+		return &node[K, B]{key: t.key, value: f(t.key, t.value), color: t.color, size: t.size, left: mapHelp(f, t.left), right: mapHelp(f, t.right)}
 	}
 }
 
 // Fold over the key-value pairs in a dictionary from lowest key to highest key.
-func Foldl[K Comparable[K], V, B any](f func(K, V, B) B, acc B, d Dict[K, V]) B {
+func Foldl[K basics.Comparable[K], V, B any](f func(K, V, B) B, acc B, d Dict[K, V]) B {
 	return foldlHelp(f, acc, d.rbt().root)
 }
 
-func foldlHelp[K Comparable[K], V, B any](f func(K, V, B) B, acc B, t *node[K, V]) B {
+func foldlHelp[K basics.Comparable[K], V, B any](f func(K, V, B) B, acc B, t *node[K, V]) B {
 foldlHelpL:
 	for {
 		if t == nil {
@@ -935,11 +1636,11 @@ foldlHelpL:
 }
 
 // Fold over the key-value pairs in a dictionary from highest key to lowest key.
-func Foldr[K Comparable[K], V, B any](f func(K, V, B) B, acc B, d Dict[K, V]) B {
+func Foldr[K basics.Comparable[K], V, B any](f func(K, V, B) B, acc B, d Dict[K, V]) B {
 	return foldrHelp(f, acc, d.rbt().root)
 }
 
-func foldrHelp[K Comparable[K], V, B any](f func(K, V, B) B, acc B, t *node[K, V]) B {
+func foldrHelp[K basics.Comparable[K], V, B any](f func(K, V, B) B, acc B, t *node[K, V]) B {
 foldrHelpL:
 	for {
 		if t == nil {
@@ -959,60 +1660,91 @@ foldrHelpL:
 }
 
 // Keep only the key-value pairs that pass the given test.
-func Filter[K Comparable[K], V any](isGood func(K, V) bool, d Dict[K, V]) Dict[K, V] {
-	return Foldl(
-		func(k K, v V, d Dict[K, V]) Dict[K, V] {
+func Filter[K basics.Comparable[K], V any](isGood func(K, V) bool, d Dict[K, V]) Dict[K, V] {
+	kept := Foldl(
+		func(k K, v V, xs []tuple.Tuple2[K, V]) []tuple.Tuple2[K, V] {
 			if isGood(k, v) {
-				return Insert(k, v, d)
+				return append(xs, tuple.Pair(k, v))
 			} else {
-				return d
+				return xs
 			}
 		},
-		Empty[K, V](),
+		[]tuple.Tuple2[K, V]{},
 		d,
 	)
+	return fromSorted(kept)
 }
 
 // Partition a dictionary according to some test. The first dictionary
 // contains all key-value pairs which passed the test, and the second contains
 // the pairs that did not.
-func Partition[K Comparable[K], V any](isGood func(K, V) bool, d Dict[K, V]) tuple.Tuple2[Dict[K, V], Dict[K, V]] {
-	add := func(
-		key K,
-		value V,
-		td tuple.Tuple2[Dict[K, V], Dict[K, V]],
-	) tuple.Tuple2[Dict[K, V], Dict[K, V]] {
-		t1, t2 := tuple.First(td), tuple.Second(td)
-		if isGood(key, value) {
-			return tuple.Pair(Insert(key, value, t1), t2)
-		} else {
-			return tuple.Pair(t1, Insert(key, value, t2))
-		}
-	}
-	return Foldl(add, tuple.Pair(Empty[K, V](), Empty[K, V]()), d)
+func Partition[K basics.Comparable[K], V any](isGood func(K, V) bool, d Dict[K, V]) tuple.Tuple2[Dict[K, V], Dict[K, V]] {
+	var good, bad []tuple.Tuple2[K, V]
+	Foldl(
+		func(key K, value V, _ struct{}) struct{} {
+			if isGood(key, value) {
+				good = append(good, tuple.Pair(key, value))
+			} else {
+				bad = append(bad, tuple.Pair(key, value))
+			}
+			return struct{}{}
+		},
+		struct{}{},
+		d,
+	)
+	return tuple.Pair(fromSorted(good), fromSorted(bad))
 }
 
 // COMBINE
 
 // Combine two dictionaries. If there is a collision, preference is given
 // to the first dictionary.
-func Union[K Comparable[K], V any](t1 Dict[K, V], t2 Dict[K, V]) Dict[K, V] {
-	return Foldl[K, V, Dict[K, V]](Insert, t2, t1)
+// This takes O(m log(n/m + 1)) time, where m is the size of the smaller dictionary.
+func Union[K basics.Comparable[K], V any](t1 Dict[K, V], t2 Dict[K, V]) Dict[K, V] {
+	return fromRoot(unionHelp(t1.rbt().root, t2.rbt().root))
 }
 
 // Keep a key-value pair when its key appears in the second dictionary.
 // Preference is given to values in the first dictionary.
-func Intersect[K Comparable[K], V any](t1 Dict[K, V], t2 Dict[K, V]) Dict[K, V] {
-	return Filter(func(k K, _ V) bool { return Member(k, t2) }, t1)
+// This takes O(m log(n/m + 1)) time, where m is the size of the smaller dictionary.
+func Intersect[K basics.Comparable[K], V any](t1 Dict[K, V], t2 Dict[K, V]) Dict[K, V] {
+	return fromRoot(intersectHelp(t1.rbt().root, t2.rbt().root))
 }
 
 // Keep a key-value pair when its key does not appear in the second dictionary.
-func Diff[K Comparable[K], V any](t1 Dict[K, V], t2 Dict[K, V]) Dict[K, V] {
-	return Foldl(
-		func(k K, _ V, t Dict[K, V]) Dict[K, V] { return Remove(k, t) },
-		t1,
-		t2,
-	)
+// This takes O(m log(n/m + 1)) time, where m is the size of the smaller dictionary.
+func Diff[K basics.Comparable[K], V any](t1 Dict[K, V], t2 Dict[K, V]) Dict[K, V] {
+	return fromRoot(diffHelp(t1.rbt().root, t2.rbt().root))
+}
+
+func unionHelp[K basics.Comparable[K], V any](t1 *node[K, V], t2 *node[K, V]) *node[K, V] {
+	if t1 == nil {
+		return t2
+	} else if t2 == nil {
+		return t1
+	}
+	l2, _, r2 := split(t1.key, t2)
+	return join(unionHelp(t1.left, l2), t1.key, t1.value, unionHelp(t1.right, r2))
+}
+
+func intersectHelp[K basics.Comparable[K], V any](t1 *node[K, V], t2 *node[K, V]) *node[K, V] {
+	if t1 == nil || t2 == nil {
+		return nil
+	}
+	l2, m, r2 := split(t1.key, t2)
+	l, r := intersectHelp(t1.left, l2), intersectHelp(t1.right, r2)
+	if m != nil {
+		return join(l, t1.key, t1.value, r)
+	}
+	return join2(l, r)
+}
+
+func diffHelp[K basics.Comparable[K], V any](t1 *node[K, V], t2 *node[K, V]) *node[K, V] {
+	if t1 == nil || t2 == nil {
+		return t1
+	}
+	l1, _, r1 := split(t2.key, t1)
+	return join2(diffHelp(l1, t2.left), diffHelp(r1, t2.right))
 }
 
 // The most general way of combining two dictionaries. You provide three
@@ -1022,7 +1754,7 @@ func Diff[K Comparable[K], V any](t1 Dict[K, V], t2 Dict[K, V]) Dict[K, V] {
 // 3. Only in the right dictionary.
 // You then traverse all the keys from lowest to highest, building up whatever
 // you want.
-func Merge[K Comparable[K], A, B, R any](
+func Merge[K basics.Comparable[K], A, B, R any](
 	leftStep func(K, A, R) R,
 	bothStep func(K, A, B, R) R,
 	rightStep func(K, B, R) R,
@@ -1030,44 +1762,55 @@ func Merge[K Comparable[K], A, B, R any](
 	rightDict Dict[K, B],
 	initialResult R,
 ) R {
-	stepState := func(rKey K, rValue B, _v0 tuple.Tuple2[list.List[tuple.Tuple2[K, A]], R]) tuple.Tuple2[list.List[tuple.Tuple2[K, A]], R] {
-	stepStateL:
-		for {
-			list_ := tuple.First(_v0)
-			result := tuple.Second(_v0)
-			if list_.Cons() == nil /* empty */ {
-				return tuple.Pair(list_, rightStep(rKey, rValue, result))
-			} else {
-				_v2 := list_.Cons().A
-				lKey := tuple.First(_v2)
-				lValue := tuple.Second(_v2)
-				rest := list_.Cons().B
-				if lKey.Cmp(rKey) < 0 {
-					tempRKey, tempRValue, temp_v0 := rKey, rValue, tuple.Pair(rest, leftStep(lKey, lValue, result))
-					rKey = tempRKey
-					rValue = tempRValue
-					_v0 = temp_v0
-					continue stepStateL
-				} else {
-					if lKey.Cmp(rKey) > 0 {
-						return tuple.Pair(list_, rightStep(rKey, rValue, result))
-					} else {
-						return tuple.Pair(rest, bothStep(lKey, lValue, rValue, result))
-					}
-				}
-			}
+	result := initialResult
+	left, right := newIterator(leftDict.rbt().root), newIterator(rightDict.rbt().root)
+	l, r := left.next(), right.next()
+	for l != nil && r != nil {
+		switch l.key.Cmp(r.key) {
+		case -1:
+			result = leftStep(l.key, l.value, result)
+			l = left.next()
+		case 0:
+			result = bothStep(l.key, l.value, r.value, result)
+			l, r = left.next(), right.next()
+		case 1:
+			result = rightStep(r.key, r.value, result)
+			r = right.next()
 		}
 	}
-	_v3 := Foldl(stepState, tuple.Pair(ToList(leftDict), initialResult), rightDict)
-	leftovers := tuple.First(_v3)
-	intermediateResult := tuple.Second(_v3)
-	return list.Foldl(
-		func(_v4 tuple.Tuple2[K, A], result R) R {
-			k := tuple.First(_v4)
-			v := tuple.Second(_v4)
-			return leftStep(k, v, result)
-		},
-		intermediateResult,
-		leftovers,
-	)
+	for ; l != nil; l = left.next() {
+		result = leftStep(l.key, l.value, result)
+	}
+	for ; r != nil; r = right.next() {
+		result = rightStep(r.key, r.value, result)
+	}
+	return result
+}
+
+// An in-order walk over the nodes of a tree, holding the path to the next node.
+type iterator[K basics.Comparable[K], V any] struct {
+	path []*node[K, V]
+}
+
+func newIterator[K basics.Comparable[K], V any](n *node[K, V]) *iterator[K, V] {
+	it := &iterator[K, V]{}
+	it.pushLeft(n)
+	return it
+}
+
+func (it *iterator[K, V]) pushLeft(n *node[K, V]) {
+	for ; n != nil; n = n.left {
+		it.path = append(it.path, n)
+	}
+}
+
+// Get the next node, or nil when the walk is done.
+func (it *iterator[K, V]) next() *node[K, V] {
+	if len(it.path) == 0 {
+		return nil
+	}
+	n := it.path[len(it.path)-1]
+	it.path = it.path[:len(it.path)-1]
+	it.pushLeft(n.right)
+	return n
 }
@@ -1,12 +1,14 @@
 package dict
 
 import (
-	. "github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/char"
 	"github.com/Confidenceman02/scion-tools/pkg/list"
 	"github.com/Confidenceman02/scion-tools/pkg/maybe"
 	s "github.com/Confidenceman02/scion-tools/pkg/string"
 	"github.com/Confidenceman02/scion-tools/pkg/tuple"
 	"github.com/stretchr/testify/assert"
+	"reflect"
 	"testing"
 )
 
@@ -14,7 +16,7 @@ func TestBuild(t *testing.T) {
 	asserts := assert.New(t)
 
 	t.Run("Empty", func(t *testing.T) {
-		asserts.Equal(FromList(list.Empty[tuple.Tuple2[Int, any]]()), Empty[Int, any]())
+		asserts.Equal(FromList(list.Empty[tuple.Tuple2[basics.Int, any]]()), Empty[basics.Int, any]())
 	})
 	t.Run("Singleton", func(t *testing.T) {
 		expected := FromList(list.FromSlice(
@@ -44,25 +46,25 @@ func TestBuild(t *testing.T) {
 		asserts.Equal(expected, SUT)
 	})
 	t.Run("Insert on Singleton", func(t *testing.T) {
-		d := Singleton(Int(1), 2)
-		d1 := Insert(Int(2), 2, d)
+		d := Singleton(basics.Int(1), 2)
+		d1 := Insert(basics.Int(2), 2, d)
 
-		asserts.Equal(Int(1), d.rbt().root.key)
-		asserts.Equal(Int(1), d1.rbt().root.key)
+		asserts.Equal(basics.Int(1), d.rbt().root.key)
+		asserts.Equal(basics.Int(1), d1.rbt().root.key)
 		asserts.Nil(d.rbt().root.right)
 		asserts.NotNil(d1.rbt().root.right)
-		asserts.Equal(Int(2), d1.rbt().root.right.key)
+		asserts.Equal(basics.Int(2), d1.rbt().root.right.key)
 	})
 	t.Run("Insert on Empty", func(t *testing.T) {
-		d := Empty[Int, Int]()
-		d1 := Insert(Int(2), 2, d)
+		d := Empty[basics.Int, basics.Int]()
+		d1 := Insert(basics.Int(2), 2, d)
 
-		asserts.Equal(&dict[Int, Int]{root: nil}, d.rbt())
-		asserts.Equal(Int(2), d1.rbt().root.key)
+		asserts.Equal(&dict[basics.Int, basics.Int]{root: nil}, d.rbt())
+		asserts.Equal(basics.Int(2), d1.rbt().root.key)
 	})
 	t.Run("Insert into existing entry", func(t *testing.T) {
-		d := Singleton(Int(10), 233)
-		d1 := Insert(Int(10), 233, d)
+		d := Singleton(basics.Int(10), 233)
+		d1 := Insert(basics.Int(10), 233, d)
 
 		SUT := d1.rbt()
 
@@ -71,11 +73,11 @@ func TestBuild(t *testing.T) {
 		asserts.NotSame(d.rbt().root, SUT.root)
 	})
 	t.Run("Insert with color pushdown", func(t *testing.T) {
-		d := Singleton(Int(40), 1)
-		d1 := Insert(Int(50), 2, d)
-		d2 := Insert(Int(30), 3, d1)
+		d := Singleton(basics.Int(40), 1)
+		d1 := Insert(basics.Int(50), 2, d)
+		d2 := Insert(basics.Int(30), 3, d1)
 		// Will cause color pushdown of parent node (40)
-		d3 := Insert(Int(35), 3, d2)
+		d3 := Insert(basics.Int(35), 3, d2)
 
 		asserts.Equal(d1.rbt().root.right, d2.rbt().root.right)
 		asserts.NotEqual(d2.rbt().root.right, d3.rbt().root.right)
@@ -108,17 +110,35 @@ func TestBuild(t *testing.T) {
 func TestMember(t *testing.T) {
 	asserts := assert.New(t)
 	t.Run("Member on Singleton", func(t *testing.T) {
-		d := Singleton(Int(10), 23)
+		d := Singleton(basics.Int(10), 23)
 
-		asserts.Equal(true, Member(Int(10), d))
-		asserts.Equal(false, Member(Int(2), d))
+		asserts.Equal(true, Member(basics.Int(10), d))
+		asserts.Equal(false, Member(basics.Int(2), d))
 	})
 
 	t.Run("Member on Empty", func(t *testing.T) {
-		d := Empty[Int, Int]()
+		d := Empty[basics.Int, basics.Int]()
 
-		asserts.Equal(false, Member(Int(22), d))
-		asserts.Equal(false, Member(Int(2), d))
+		asserts.Equal(false, Member(basics.Int(22), d))
+		asserts.Equal(false, Member(basics.Int(2), d))
+	})
+
+	t.Run("Member with tuple.Tuple2 keys", func(t *testing.T) {
+		key := func(x, y basics.Int) tuple.Tuple2[basics.Int, basics.Int] { return tuple.Pair(x, y) }
+		d := Insert(key(0, 1), "b", Insert(key(1, 0), "c", Singleton(key(0, 0), "a")))
+
+		asserts.Equal(true, Member(key(0, 1), d))
+		asserts.Equal(false, Member(key(1, 1), d))
+		asserts.Equal(maybe.Just[string]{Value: "c"}, Get(key(1, 0), d))
+		asserts.Equal([]string{"a", "b", "c"}, list.ToSlice(Values(d)))
+	})
+
+	t.Run("Member with char.Char keys", func(t *testing.T) {
+		d := FromList(list.FromSlice([]tuple.Tuple2[char.Char, basics.Int]{tuple.Pair[char.Char, basics.Int]('b', 2), tuple.Pair[char.Char, basics.Int]('a', 1)}))
+
+		asserts.Equal(true, Member[char.Char, basics.Int]('a', d))
+		asserts.Equal(false, Member[char.Char, basics.Int]('c', d))
+		asserts.Equal([]char.Char{'a', 'b'}, list.ToSlice(Keys(d)))
 	})
 }
 
@@ -126,14 +146,14 @@ func TestIsEmpty(t *testing.T) {
 	asserts := assert.New(t)
 
 	t.Run("Empty dict", func(t *testing.T) {
-		d := Empty[Int, int]()
+		d := Empty[basics.Int, int]()
 		SUT := IsEmpty(d)
 
 		asserts.True(SUT)
 	})
 
 	t.Run("Singleton dict", func(t *testing.T) {
-		d := Singleton(Int(100), 1)
+		d := Singleton(basics.Int(100), 1)
 		SUT := IsEmpty(d)
 
 		asserts.False(SUT)
@@ -143,15 +163,15 @@ func TestIsEmpty(t *testing.T) {
 func TestGet(t *testing.T) {
 	asserts := assert.New(t)
 	t.Run("Get existing node", func(t *testing.T) {
-		d := Singleton(Int(10), 23)
-		SUT := Get(Int(10), d)
+		d := Singleton(basics.Int(10), 23)
+		SUT := Get(basics.Int(10), d)
 
 		asserts.Equal(maybe.Just[int]{Value: 23}, SUT)
 	})
 
 	t.Run("Get non-existing entry", func(t *testing.T) {
-		d := Empty[Int, Int]()
-		SUT := Get(Int(10), d)
+		d := Empty[basics.Int, basics.Int]()
+		SUT := Get(basics.Int(10), d)
 
 		asserts.Equal(maybe.Nothing{}, SUT)
 	})
@@ -161,14 +181,16 @@ func TestSize(t *testing.T) {
 	asserts := assert.New(t)
 
 	t.Run("Size", func(t *testing.T) {
-		xs := list.FromSlice([]Int{1, 2, 3, 4, 5})
+		xs := list.FromSlice([]basics.Int{1, 2, 3, 4, 5})
 		SUT := list.Foldl(
-			func(i Int, d Dict[Int, Int]) Dict[Int, Int] { return Insert(i, i, d) },
-			Empty[Int, Int](),
+			func(i basics.Int, d Dict[basics.Int, basics.Int]) Dict[basics.Int, basics.Int] {
+				return Insert(i, i, d)
+			},
+			Empty[basics.Int, basics.Int](),
 			xs,
 		)
 
-		asserts.Equal(Int(5), Size(SUT))
+		asserts.Equal(basics.Int(5), Size(SUT))
 	})
 }
 
@@ -176,19 +198,20 @@ func TestInsert(t *testing.T) {
 	asserts := assert.New(t)
 
 	t.Run("Empty", func(t *testing.T) {
-		asserts.Equal(&dict[Int, struct{}]{root: nil}, Empty[Int, struct{}]())
+		asserts.Equal(&dict[basics.Int, struct{}]{root: nil}, Empty[basics.Int, struct{}]())
 	})
 
 	t.Run("Insert on Empty has properties", func(t *testing.T) {
-		d := Empty[Int, Int]()
-		d1 := Insert(Int(1), 233, d)
+		d := Empty[basics.Int, basics.Int]()
+		d1 := Insert(basics.Int(1), 233, d)
 
 		SUT := d1.rbt()
 
-		asserts.Equal(&dict[Int, Int]{root: &node[Int, Int]{
-			key:   Int(1),
+		asserts.Equal(&dict[basics.Int, basics.Int]{root: &node[basics.Int, basics.Int]{
+			key:   basics.Int(1),
 			value: 233,
 			color: black,
+			size:  1,
 			left:  nil,
 			right: nil}},
 			SUT,
@@ -196,8 +219,8 @@ func TestInsert(t *testing.T) {
 	})
 
 	t.Run("Insert on Singleton right side", func(t *testing.T) {
-		d := Singleton[Int, Int](Int(1), 1)
-		d1 := Insert(Int(2), 2, d)
+		d := Singleton[basics.Int, basics.Int](basics.Int(1), 1)
+		d1 := Insert(basics.Int(2), 2, d)
 
 		SUT := d1.rbt()
 
@@ -206,132 +229,133 @@ func TestInsert(t *testing.T) {
 	})
 
 	t.Run("Insert into existing entry", func(t *testing.T) {
-		d := Singleton(Int(10), 233)
-		d1 := Insert(Int(10), 100, d)
+		d := Singleton(basics.Int(10), 233)
+		d1 := Insert(basics.Int(10), 100, d)
 
 		SUT := d1
 
-		asserts.Equal(&dict[Int, int]{root: &node[Int, int]{
-			key:   Int(10),
+		asserts.Equal(&dict[basics.Int, int]{root: &node[basics.Int, int]{
+			key:   basics.Int(10),
 			value: 100,
 			color: black,
+			size:  1,
 			left:  nil,
 			right: nil},
 		}, SUT)
 	})
 
 	t.Run("LL Single right rotation", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Insert(Int(40), 2, d)
-		Insert(Int(30), 3, d1)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Insert(basics.Int(40), 2, d)
+		Insert(basics.Int(30), 3, d1)
 
 		asserts.Nil(d1.rbt().root.right)
-		asserts.Equal(Int(40), d1.rbt().root.left.key)
+		asserts.Equal(basics.Int(40), d1.rbt().root.left.key)
 	})
 
 	t.Run("LR -> RR rotation", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Insert(Int(40), 2, d)
-		d2 := Insert(Int(45), 3, d1)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Insert(basics.Int(40), 2, d)
+		d2 := Insert(basics.Int(45), 3, d1)
 
 		// d1
-		asserts.Equal(Int(50), d1.rbt().root.key)
+		asserts.Equal(basics.Int(50), d1.rbt().root.key)
 		asserts.Nil(d1.rbt().root.right)
-		asserts.Equal(Int(40), d1.rbt().root.left.key)
+		asserts.Equal(basics.Int(40), d1.rbt().root.left.key)
 		asserts.Nil(d1.rbt().root.left.left)
 		asserts.Nil(d1.rbt().root.left.right)
 
 		// d2
-		asserts.Equal(Int(45), d2.rbt().root.key)
-		asserts.Equal(Int(50), d2.rbt().root.right.key)
+		asserts.Equal(basics.Int(45), d2.rbt().root.key)
+		asserts.Equal(basics.Int(50), d2.rbt().root.right.key)
 		asserts.Nil(d2.rbt().root.right.right)
-		asserts.Equal(Int(40), d2.rbt().root.left.key)
+		asserts.Equal(basics.Int(40), d2.rbt().root.left.key)
 		asserts.Nil(d2.rbt().root.left.left)
 	})
 
 	t.Run("RR Single left rotation", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Insert(Int(60), 2, d)
-		Insert(Int(70), 3, d1)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Insert(basics.Int(60), 2, d)
+		Insert(basics.Int(70), 3, d1)
 
 		asserts.Nil(d1.rbt().root.left)
-		asserts.Equal(Int(60), d1.rbt().root.right.key)
+		asserts.Equal(basics.Int(60), d1.rbt().root.right.key)
 	})
 
 	t.Run("LR -> LL rotation", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Insert(Int(60), 2, d)
-		d2 := Insert(Int(55), 3, d1)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Insert(basics.Int(60), 2, d)
+		d2 := Insert(basics.Int(55), 3, d1)
 
 		// d1
-		asserts.Equal(Int(50), d1.rbt().root.key)
+		asserts.Equal(basics.Int(50), d1.rbt().root.key)
 		asserts.Nil(d1.rbt().root.left)
-		asserts.Equal(Int(60), d1.rbt().root.right.key)
+		asserts.Equal(basics.Int(60), d1.rbt().root.right.key)
 		asserts.Nil(d1.rbt().root.right.right)
 		asserts.Nil(d1.rbt().root.right.left)
 
 		// d2
-		asserts.Equal(Int(55), d2.rbt().root.key)
-		asserts.Equal(Int(60), d2.rbt().root.right.key)
+		asserts.Equal(basics.Int(55), d2.rbt().root.key)
+		asserts.Equal(basics.Int(60), d2.rbt().root.right.key)
 		asserts.Nil(d2.rbt().root.left.left)
-		asserts.Equal(Int(50), d2.rbt().root.left.key)
+		asserts.Equal(basics.Int(50), d2.rbt().root.left.key)
 		asserts.Nil(d2.rbt().root.right.right)
 	})
 
 	t.Run("granparent color pushdown", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Insert(Int(40), 2, d)
-		d2 := Insert(Int(45), 3, d1)
-		d3 := Insert(Int(30), 3, d2)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Insert(basics.Int(40), 2, d)
+		d2 := Insert(basics.Int(45), 3, d1)
+		d3 := Insert(basics.Int(30), 3, d2)
 
 		// d2
-		asserts.Equal(Int(50), d2.rbt().root.right.key)
+		asserts.Equal(basics.Int(50), d2.rbt().root.right.key)
 		asserts.Equal(red, d2.rbt().root.right.color)
 		asserts.Nil(d2.rbt().root.left.left)
 
 		// d3
-		asserts.Equal(Int(50), d3.rbt().root.right.key)
+		asserts.Equal(basics.Int(50), d3.rbt().root.right.key)
 		asserts.Equal(black, d3.rbt().root.right.color)
 		asserts.Equal(black, d3.rbt().root.left.color)
 		asserts.Equal(red, d3.rbt().root.left.left.color)
 	})
 
 	t.Run("LL Single right rotation", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Insert(Int(40), 2, d)
-		d2 := Insert(Int(30), 3, d1)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Insert(basics.Int(40), 2, d)
+		d2 := Insert(basics.Int(30), 3, d1)
 
 		SUT := d2.rbt()
 
-		asserts.Equal(Int(40), SUT.root.key)
+		asserts.Equal(basics.Int(40), SUT.root.key)
 		asserts.Equal(black, SUT.root.color)
-		asserts.Equal(Int(50), SUT.root.right.key)
+		asserts.Equal(basics.Int(50), SUT.root.right.key)
 		asserts.Equal(red, SUT.root.right.color)
-		asserts.Equal(Int(30), SUT.root.left.key)
+		asserts.Equal(basics.Int(30), SUT.root.left.key)
 		asserts.Equal(red, SUT.root.left.color)
 	})
 
 	t.Run("RR Single right rotation", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Insert(Int(60), 2, d)
-		d2 := Insert(Int(70), 3, d1)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Insert(basics.Int(60), 2, d)
+		d2 := Insert(basics.Int(70), 3, d1)
 
 		SUT := d2.rbt()
 
-		asserts.Equal(Int(60), SUT.root.key)
+		asserts.Equal(basics.Int(60), SUT.root.key)
 		asserts.Equal(black, SUT.root.color)
-		asserts.Equal(Int(50), SUT.root.left.key)
+		asserts.Equal(basics.Int(50), SUT.root.left.key)
 		asserts.Equal(red, SUT.root.left.color)
-		asserts.Equal(Int(70), SUT.root.right.key)
+		asserts.Equal(basics.Int(70), SUT.root.right.key)
 		asserts.Equal(red, SUT.root.right.color)
 	})
 
 	t.Run("LR double red, red uncle", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
+		d := Singleton(basics.Int(50), 1)
 		// Left
-		d1 := Insert(Int(40), 2, d)
-		d2 := Insert(Int(60), 3, d1)
-		d3 := Insert(Int(45), 4, d2)
+		d1 := Insert(basics.Int(40), 2, d)
+		d2 := Insert(basics.Int(60), 3, d1)
+		d3 := Insert(basics.Int(45), 4, d2)
 
 		SUT := d3.rbt()
 
@@ -342,26 +366,26 @@ func TestInsert(t *testing.T) {
 	})
 
 	t.Run("LR double red, black uncle", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
+		d := Singleton(basics.Int(50), 1)
 		// Left
-		d1 := Insert(Int(40), 2, d)
-		d2 := Insert(Int(45), 3, d1)
+		d1 := Insert(basics.Int(40), 2, d)
+		d2 := Insert(basics.Int(45), 3, d1)
 
 		SUT := d2.rbt()
 
 		asserts.Equal(black, SUT.root.color)
-		asserts.Equal(Int(45), SUT.root.key)
+		asserts.Equal(basics.Int(45), SUT.root.key)
 		asserts.Equal(red, SUT.root.left.color)
-		asserts.Equal(Int(40), SUT.root.left.key)
+		asserts.Equal(basics.Int(40), SUT.root.left.key)
 		asserts.Equal(red, SUT.root.right.color)
-		asserts.Equal(Int(50), SUT.root.right.key)
+		asserts.Equal(basics.Int(50), SUT.root.right.key)
 	})
 
 	t.Run("RL double red, red uncle", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Insert(Int(60), 2, d)
-		d2 := Insert(Int(40), 3, d1)
-		d3 := Insert(Int(55), 4, d2)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Insert(basics.Int(60), 2, d)
+		d2 := Insert(basics.Int(40), 3, d1)
+		d3 := Insert(basics.Int(55), 4, d2)
 
 		SUT := d3.rbt()
 
@@ -372,101 +396,134 @@ func TestInsert(t *testing.T) {
 	})
 
 	t.Run("RL double red, black uncle", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Insert(Int(60), 2, d)
-		d2 := Insert(Int(55), 4, d1)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Insert(basics.Int(60), 2, d)
+		d2 := Insert(basics.Int(55), 4, d1)
 
 		SUT := d2.rbt()
 
 		asserts.Equal(black, SUT.root.color)
-		asserts.Equal(Int(55), SUT.root.key)
+		asserts.Equal(basics.Int(55), SUT.root.key)
 		asserts.Equal(red, SUT.root.left.color)
-		asserts.Equal(Int(50), SUT.root.left.key)
+		asserts.Equal(basics.Int(50), SUT.root.left.key)
 		asserts.Equal(red, SUT.root.right.color)
-		asserts.Equal(Int(60), SUT.root.right.key)
+		asserts.Equal(basics.Int(60), SUT.root.right.key)
 	})
 
 	t.Run("test the following inserts 7,5,10,20,15", func(t *testing.T) {
-		d := Singleton(Int(7), 1)
-		d1 := Insert(Int(5), 2, d)
-		d2 := Insert(Int(10), 3, d1)
-		d3 := Insert(Int(20), 3, d2)
-		d4 := Insert(Int(15), 3, d3)
+		d := Singleton(basics.Int(7), 1)
+		d1 := Insert(basics.Int(5), 2, d)
+		d2 := Insert(basics.Int(10), 3, d1)
+		d3 := Insert(basics.Int(20), 3, d2)
+		d4 := Insert(basics.Int(15), 3, d3)
 
 		SUT := d4.rbt()
 
 		asserts.Equal(black, SUT.root.color)
-		asserts.Equal(Int(7), SUT.root.key)
+		asserts.Equal(basics.Int(7), SUT.root.key)
 		asserts.Equal(black, SUT.root.right.color)
-		asserts.Equal(Int(15), SUT.root.right.key)
+		asserts.Equal(basics.Int(15), SUT.root.right.key)
 		asserts.Equal(red, SUT.root.right.right.color)
-		asserts.Equal(Int(20), SUT.root.right.right.key)
+		asserts.Equal(basics.Int(20), SUT.root.right.right.key)
 		asserts.Equal(red, SUT.root.right.left.color)
-		asserts.Equal(Int(10), SUT.root.right.left.key)
+		asserts.Equal(basics.Int(10), SUT.root.right.left.key)
 	})
 
 	t.Run("test the following inserts 10,15,5,0,2", func(t *testing.T) {
-		d := Singleton(Int(10), 1)
-		d1 := Insert(Int(15), 2, d)
-		d2 := Insert(Int(5), 3, d1)
-		d3 := Insert(Int(0), 3, d2)
-		d4 := Insert(Int(2), 3, d3)
+		d := Singleton(basics.Int(10), 1)
+		d1 := Insert(basics.Int(15), 2, d)
+		d2 := Insert(basics.Int(5), 3, d1)
+		d3 := Insert(basics.Int(0), 3, d2)
+		d4 := Insert(basics.Int(2), 3, d3)
 
 		SUT := d4.rbt()
 
 		asserts.Equal(black, SUT.root.color)
-		asserts.Equal(Int(10), SUT.root.key)
+		asserts.Equal(basics.Int(10), SUT.root.key)
 		asserts.Equal(black, SUT.root.left.color)
-		asserts.Equal(Int(2), SUT.root.left.key)
+		asserts.Equal(basics.Int(2), SUT.root.left.key)
 		asserts.Equal(red, SUT.root.left.left.color)
-		asserts.Equal(Int(0), SUT.root.left.left.key)
+		asserts.Equal(basics.Int(0), SUT.root.left.left.key)
 		asserts.Equal(red, SUT.root.left.right.color)
-		asserts.Equal(Int(5), SUT.root.left.right.key)
+		asserts.Equal(basics.Int(5), SUT.root.left.right.key)
 	})
 
 	t.Run("Structure sharing right subtree", func(t *testing.T) {
-		d := Singleton(Int(40), 1)
-		d1 := Insert(Int(50), 2, d)
-		d2 := Insert(Int(30), 3, d1)
-		d3 := Insert(Int(35), 3, d1)
+		d := Singleton(basics.Int(40), 1)
+		d1 := Insert(basics.Int(50), 2, d)
+		d2 := Insert(basics.Int(30), 3, d1)
+		d3 := Insert(basics.Int(35), 3, d1)
 
 		asserts.Equal(d1.rbt().root.right, d2.rbt().root.right)
 		asserts.Equal(d2.rbt().root.right, d3.rbt().root.right)
 	})
+
+	t.Run("Replacing a black node under a red parent keeps the tree balanced", func(t *testing.T) {
+		d := fromKeys(1, 2, 3, 4, 5, 6)
+		SUT := Insert(basics.Int(3), 0, d)
+
+		asserts.True(isValidTree(d))
+		asserts.True(isValidTree(SUT))
+		asserts.Equal(maybe.Just[basics.Int]{Value: 0}, Get(3, SUT))
+	})
+
+	t.Run("Rotations under a red great grandparent keep the tree balanced", func(t *testing.T) {
+		SUT := fromKeys(list.ToSlice(list.Map(func(i basics.Int) basics.Int { return basics.ModBy(997, i*7919) }, list.Range(1, 600)))...)
+
+		asserts.True(isValidTree(SUT))
+		asserts.Equal(basics.Int(600), list.Length(Keys(SUT)))
+	})
+
+	t.Run("Inserts and removes keep the tree balanced", func(t *testing.T) {
+		SUT := Empty[basics.Int, basics.Int]()
+		x := basics.Int(7)
+		for i := 0; i < 2000; i++ {
+			x = basics.ModBy(10007, x*7919+13)
+			if basics.ModBy(3, x) == 0 {
+				SUT = Remove(basics.ModBy(97, x), SUT)
+			} else {
+				SUT = Insert(basics.ModBy(97, x), x, SUT)
+			}
+			if !isValidTree(SUT) {
+				asserts.Fail("unbalanced tree", "after %d operations", i+1)
+				break
+			}
+		}
+	})
 }
 
 func TestRemove(t *testing.T) {
 	asserts := assert.New(t)
 
 	t.Run("remove Empty Dict", func(t *testing.T) {
-		d := Empty[Int, Int]()
-		d1 := Remove(Int(50), d)
+		d := Empty[basics.Int, basics.Int]()
+		d1 := Remove(basics.Int(50), d)
 
 		asserts.Equal(&d, &d1)
 	})
 
 	t.Run("remove Singleton key that doesn't exist", func(t *testing.T) {
-		d := Singleton[Int, Int](Int(1), 1)
-		d1 := Remove(Int(50), d)
+		d := Singleton[basics.Int, basics.Int](basics.Int(1), 1)
+		d1 := Remove(basics.Int(50), d)
 
 		// Pointers match
 		asserts.Equal(&d, &d1)
 	})
 
 	t.Run("remove Singleton", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Remove(Int(50), d)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Remove(basics.Int(50), d)
 
 		asserts.NotNil(d.rbt().root)
-		asserts.Equal(Int(50), d.rbt().root.key)
+		asserts.Equal(basics.Int(50), d.rbt().root.key)
 		asserts.Nil(d1.rbt().root)
 	})
 
 	t.Run("remove childless red leaf", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Insert(Int(40), 2, d)
-		d2 := Insert(Int(60), 3, d1)
-		d3 := Remove(Int(40), d2)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Insert(basics.Int(40), 2, d)
+		d2 := Insert(basics.Int(60), 3, d1)
+		d3 := Remove(basics.Int(40), d2)
 
 		asserts.NotNil(d1.rbt().root.left)
 		asserts.NotNil(d2.rbt().root.left)
@@ -482,49 +539,49 @@ func TestRemove(t *testing.T) {
 	})
 
 	t.Run("black leaf, LEFT | BLACK sibling, red near nephew, BLACK distant nephew", func(t *testing.T) {
-		var tree Dict[Int, Comparable[Int]]
-		tree = &dict[Int, Comparable[Int]]{
-			root: &node[Int, Comparable[Int]]{
-				key:   Int(40),
-				value: Int(1),
+		var tree Dict[basics.Int, basics.Comparable[basics.Int]]
+		tree = &dict[basics.Int, basics.Comparable[basics.Int]]{
+			root: &node[basics.Int, basics.Comparable[basics.Int]]{
+				key:   basics.Int(40),
+				value: basics.Int(1),
 				color: black,
-				left:  &node[Int, Comparable[Int]]{key: Int(30), value: Int(2), color: black, left: nil, right: nil},
-				right: &node[Int, Comparable[Int]]{
-					key:   Int(50),
-					value: Int(3),
+				left:  &node[basics.Int, basics.Comparable[basics.Int]]{key: basics.Int(30), value: basics.Int(2), color: black, left: nil, right: nil},
+				right: &node[basics.Int, basics.Comparable[basics.Int]]{
+					key:   basics.Int(50),
+					value: basics.Int(3),
 					color: black,
-					left:  &node[Int, Comparable[Int]]{key: Int(45), value: Int(4), color: red, left: nil, right: nil},
+					left:  &node[basics.Int, basics.Comparable[basics.Int]]{key: basics.Int(45), value: basics.Int(4), color: red, left: nil, right: nil},
 					right: nil,
 				},
 			},
 		}
 
-		SUT := Remove(Int(30), tree).rbt()
+		SUT := Remove(basics.Int(30), tree).rbt()
 
-		asserts.Equal(Int(40), tree.rbt().root.key)
-		asserts.Equal(Int(45), SUT.root.key)
-		asserts.Equal(Int(50), SUT.root.right.key)
-		asserts.Equal(Int(40), SUT.root.left.key)
+		asserts.Equal(basics.Int(40), tree.rbt().root.key)
+		asserts.Equal(basics.Int(45), SUT.root.key)
+		asserts.Equal(basics.Int(50), SUT.root.right.key)
+		asserts.Equal(basics.Int(40), SUT.root.left.key)
 		asserts.NotEqual(tree.rbt().root.right, SUT.root.right)
 	})
 
 	t.Run("black leaf, RIGHT | BLACK sibling, red near nephew, BLACK distant nephew", func(t *testing.T) {
-		var tree Dict[Int, Comparable[Int]]
-		tree = &dict[Int, Comparable[Int]]{
-			root: &node[Int, Comparable[Int]]{
-				key:   Int(40),
-				value: Int(1),
+		var tree Dict[basics.Int, basics.Comparable[basics.Int]]
+		tree = &dict[basics.Int, basics.Comparable[basics.Int]]{
+			root: &node[basics.Int, basics.Comparable[basics.Int]]{
+				key:   basics.Int(40),
+				value: basics.Int(1),
 				color: black,
-				left: &node[Int, Comparable[Int]]{
-					key:   Int(30),
-					value: Int(2),
+				left: &node[basics.Int, basics.Comparable[basics.Int]]{
+					key:   basics.Int(30),
+					value: basics.Int(2),
 					color: black,
 					left:  nil,
-					right: &node[Int, Comparable[Int]]{key: Int(35), value: Int(4), color: red, left: nil, right: nil},
+					right: &node[basics.Int, basics.Comparable[basics.Int]]{key: basics.Int(35), value: basics.Int(4), color: red, left: nil, right: nil},
 				},
-				right: &node[Int, Comparable[Int]]{
-					key:   Int(50),
-					value: Int(3),
+				right: &node[basics.Int, basics.Comparable[basics.Int]]{
+					key:   basics.Int(50),
+					value: basics.Int(3),
 					color: black,
 					left:  nil,
 					right: nil,
@@ -532,37 +589,37 @@ func TestRemove(t *testing.T) {
 			},
 		}
 
-		SUT := Remove(Int(50), tree).rbt()
+		SUT := Remove(basics.Int(50), tree).rbt()
 
-		asserts.Equal(Int(40), tree.rbt().root.key)
-		asserts.Equal(Int(35), SUT.root.key)
-		asserts.Equal(Int(40), SUT.root.right.key)
-		asserts.Equal(Int(30), SUT.root.left.key)
+		asserts.Equal(basics.Int(40), tree.rbt().root.key)
+		asserts.Equal(basics.Int(35), SUT.root.key)
+		asserts.Equal(basics.Int(40), SUT.root.right.key)
+		asserts.Equal(basics.Int(30), SUT.root.left.key)
 		asserts.NotEqual(tree.rbt().root.left, SUT.root.left)
 	})
 
 	t.Run("black leaf, RIGHT | red sibling | BLACK near nephew | BLACK distant nephew", func(t *testing.T) {
-		var tree Dict[Int, Int]
-		tree = &dict[Int, Int]{
-			root: &node[Int, Int]{
-				key:   Int(50),
+		var tree Dict[basics.Int, basics.Int]
+		tree = &dict[basics.Int, basics.Int]{
+			root: &node[basics.Int, basics.Int]{
+				key:   basics.Int(50),
 				value: 1,
 				color: black,
-				left: &node[Int, Int]{
-					key:   Int(40),
+				left: &node[basics.Int, basics.Int]{
+					key:   basics.Int(40),
 					value: 2,
 					color: red,
-					left:  &node[Int, Int]{key: Int(35), value: 5, color: black, left: nil, right: nil},
-					right: &node[Int, Int]{
-						key:   Int(45),
+					left:  &node[basics.Int, basics.Int]{key: basics.Int(35), value: 5, color: black, left: nil, right: nil},
+					right: &node[basics.Int, basics.Int]{
+						key:   basics.Int(45),
 						value: 6,
 						color: black,
 						left:  nil,
 						right: nil,
 					},
 				},
-				right: &node[Int, Int]{
-					key:   Int(60),
+				right: &node[basics.Int, basics.Int]{
+					key:   basics.Int(60),
 					value: 3,
 					color: black,
 					left:  nil,
@@ -571,16 +628,16 @@ func TestRemove(t *testing.T) {
 			},
 		}
 
-		SUT := Remove(Int(60), tree).rbt()
+		SUT := Remove(basics.Int(60), tree).rbt()
 
 		// Removes node
 		asserts.Nil(SUT.root.right.right)
 
-		asserts.Equal(Int(50), tree.rbt().root.key)
-		asserts.Equal(Int(40), SUT.root.key)
+		asserts.Equal(basics.Int(50), tree.rbt().root.key)
+		asserts.Equal(basics.Int(40), SUT.root.key)
 		asserts.Equal(black, SUT.root.color)
-		asserts.Equal(Int(50), SUT.root.right.key)
-		asserts.Equal(Int(45), SUT.root.right.left.key)
+		asserts.Equal(basics.Int(50), SUT.root.right.key)
+		asserts.Equal(basics.Int(45), SUT.root.right.left.key)
 		asserts.Equal(red, SUT.root.right.left.color)
 
 		// Structure sharing
@@ -588,75 +645,75 @@ func TestRemove(t *testing.T) {
 	})
 
 	t.Run("black node, LEFT | red child, LEFT | NIL child, RIGHT", func(t *testing.T) {
-		var tree Dict[Int, Int]
-		tree = &dict[Int, Int]{
-			root: &node[Int, Int]{
-				key:   Int(50),
+		var tree Dict[basics.Int, basics.Int]
+		tree = &dict[basics.Int, basics.Int]{
+			root: &node[basics.Int, basics.Int]{
+				key:   basics.Int(50),
 				value: 1,
 				color: black,
-				left: &node[Int, Int]{
-					key:   Int(40),
+				left: &node[basics.Int, basics.Int]{
+					key:   basics.Int(40),
 					color: black,
 					value: 3,
 					left:  nil,
-					right: &node[Int, Int]{key: Int(45), color: red, value: 6, left: nil, right: nil}},
-				right: &node[Int, Int]{key: Int(60), color: black, value: 2, left: nil, right: nil},
+					right: &node[basics.Int, basics.Int]{key: basics.Int(45), color: red, value: 6, left: nil, right: nil}},
+				right: &node[basics.Int, basics.Int]{key: basics.Int(60), color: black, value: 2, left: nil, right: nil},
 			},
 		}
 
-		SUT := Remove(Int(40), tree).rbt()
+		SUT := Remove(basics.Int(40), tree).rbt()
 
 		asserts.Nil(SUT.root.left.right)
-		asserts.Equal(Int(45), SUT.root.left.key)
+		asserts.Equal(basics.Int(45), SUT.root.left.key)
 
 		// Structure Sharing
 		asserts.True(tree.rbt().root.right == SUT.root.right)
 	})
 
 	t.Run("black node, RIGHT | red child, LEFT | NIL child, RIGHT", func(t *testing.T) {
-		var tree Dict[Int, Int]
-		tree = &dict[Int, Int]{
-			root: &node[Int, Int]{
-				key:   Int(50),
+		var tree Dict[basics.Int, basics.Int]
+		tree = &dict[basics.Int, basics.Int]{
+			root: &node[basics.Int, basics.Int]{
+				key:   basics.Int(50),
 				value: 1,
 				color: black,
-				right: &node[Int, Int]{
-					key:   Int(60),
+				right: &node[basics.Int, basics.Int]{
+					key:   basics.Int(60),
 					color: black,
 					value: 3,
-					left:  &node[Int, Int]{key: Int(55), color: red, value: 6, left: nil, right: nil},
+					left:  &node[basics.Int, basics.Int]{key: basics.Int(55), color: red, value: 6, left: nil, right: nil},
 					right: nil,
 				},
-				left: &node[Int, Int]{key: Int(40), color: black, value: 2, left: nil, right: nil},
+				left: &node[basics.Int, basics.Int]{key: basics.Int(40), color: black, value: 2, left: nil, right: nil},
 			},
 		}
 
-		SUT := Remove(Int(60), tree).rbt()
+		SUT := Remove(basics.Int(60), tree).rbt()
 
 		asserts.Nil(SUT.root.right.left)
-		asserts.Equal(Int(55), SUT.root.right.key)
+		asserts.Equal(basics.Int(55), SUT.root.right.key)
 
 		// Structure Sharing
 		asserts.True(tree.rbt().root.left == SUT.root.left)
 	})
 
 	t.Run("Removes root node with 2 red children", func(t *testing.T) {
-		var tree Dict[Int, Int]
-		tree = &dict[Int, Int]{
-			root: &node[Int, Int]{
-				key:   Int(50),
+		var tree Dict[basics.Int, basics.Int]
+		tree = &dict[basics.Int, basics.Int]{
+			root: &node[basics.Int, basics.Int]{
+				key:   basics.Int(50),
 				color: black,
 				value: 1,
-				left:  &node[Int, Int]{key: Int(40), color: red, value: 2, left: nil, right: nil},
-				right: &node[Int, Int]{key: Int(60), color: red, value: 3, left: nil, right: nil},
+				left:  &node[basics.Int, basics.Int]{key: basics.Int(40), color: red, value: 2, left: nil, right: nil},
+				right: &node[basics.Int, basics.Int]{key: basics.Int(60), color: red, value: 3, left: nil, right: nil},
 			},
 		}
 
-		SUT := Remove(Int(50), tree).rbt()
+		SUT := Remove(basics.Int(50), tree).rbt()
 
-		asserts.Equal(Int(60), SUT.root.key)
+		asserts.Equal(basics.Int(60), SUT.root.key)
 		asserts.Nil(SUT.root.right)
-		asserts.Equal(Int(40), SUT.root.left.key)
+		asserts.Equal(basics.Int(40), SUT.root.left.key)
 
 		// Structure Sharing
 		asserts.Equal(tree.rbt().root.left, SUT.root.left)
@@ -664,10 +721,10 @@ func TestRemove(t *testing.T) {
 	})
 
 	t.Run("Removes red right leaf node with no children", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Insert(Int(40), 2, d)
-		d2 := Insert(Int(60), 3, d1)
-		d3 := Remove(Int(60), d2)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Insert(basics.Int(40), 2, d)
+		d2 := Insert(basics.Int(60), 3, d1)
+		d3 := Remove(basics.Int(60), d2)
 
 		SUT := d3.rbt()
 
@@ -679,10 +736,10 @@ func TestRemove(t *testing.T) {
 	})
 
 	t.Run("Removes a red left node with no children", func(t *testing.T) {
-		d := Singleton(Int(50), 1)
-		d1 := Insert(Int(40), 2, d)
-		d2 := Insert(Int(60), 3, d1)
-		d3 := Remove(Int(40), d2)
+		d := Singleton(basics.Int(50), 1)
+		d1 := Insert(basics.Int(40), 2, d)
+		d2 := Insert(basics.Int(60), 3, d1)
+		d3 := Remove(basics.Int(40), d2)
 
 		SUT := d3.rbt()
 
@@ -691,35 +748,35 @@ func TestRemove(t *testing.T) {
 
 	t.Run("Solve rbt | remove 50,20,100,90,40,60,70,10,30,80", func(t *testing.T) {
 		// https://www.youtube.com/watch?v=PgO_Xj7DC1A&t=16s
-		var tree Dict[Int, Int]
-		tree = &dict[Int, Int]{
-			root: &node[Int, Int]{
-				key:   Int(40),
+		var tree Dict[basics.Int, basics.Int]
+		tree = &dict[basics.Int, basics.Int]{
+			root: &node[basics.Int, basics.Int]{
+				key:   basics.Int(40),
 				value: 1,
 				color: black,
-				left: &node[Int, Int]{
-					key:   Int(20),
+				left: &node[basics.Int, basics.Int]{
+					key:   basics.Int(20),
 					value: 2,
 					color: black,
-					left:  &node[Int, Int]{key: Int(10), value: 3, color: black, left: nil, right: nil},
-					right: &node[Int, Int]{key: Int(30), value: 4, color: black, left: nil, right: nil},
+					left:  &node[basics.Int, basics.Int]{key: basics.Int(10), value: 3, color: black, left: nil, right: nil},
+					right: &node[basics.Int, basics.Int]{key: basics.Int(30), value: 4, color: black, left: nil, right: nil},
 				},
-				right: &node[Int, Int]{
-					key:   Int(60),
+				right: &node[basics.Int, basics.Int]{
+					key:   basics.Int(60),
 					value: 5,
 					color: black,
-					left:  &node[Int, Int]{key: Int(50), value: 6, color: black, left: nil, right: nil},
-					right: &node[Int, Int]{
-						key:   Int(80),
+					left:  &node[basics.Int, basics.Int]{key: basics.Int(50), value: 6, color: black, left: nil, right: nil},
+					right: &node[basics.Int, basics.Int]{
+						key:   basics.Int(80),
 						value: 7,
 						color: red,
-						left:  &node[Int, Int]{key: Int(70), value: 8, color: black, left: nil, right: nil},
-						right: &node[Int, Int]{
-							key:   Int(90),
+						left:  &node[basics.Int, basics.Int]{key: basics.Int(70), value: 8, color: black, left: nil, right: nil},
+						right: &node[basics.Int, basics.Int]{
+							key:   basics.Int(90),
 							value: 9,
 							color: black,
 							left:  nil,
-							right: &node[Int, Int]{key: Int(100), value: 10, color: red, left: nil, right: nil},
+							right: &node[basics.Int, basics.Int]{key: basics.Int(100), value: 10, color: red, left: nil, right: nil},
 						},
 					},
 				},
@@ -727,10 +784,10 @@ func TestRemove(t *testing.T) {
 		}
 
 		// REMOVE 50
-		tree1 := Remove(Int(50), tree)
+		tree1 := Remove(basics.Int(50), tree)
 		SUT1 := tree1.rbt()
 
-		asserts.Equal(Int(40), SUT1.root.key)
+		asserts.Equal(basics.Int(40), SUT1.root.key)
 		asserts.Equal(black, SUT1.root.color)
 
 		// Structure sharing
@@ -746,10 +803,10 @@ func TestRemove(t *testing.T) {
 		asserts.NotEqual(tree.rbt().root.right.right.left, SUT1.root.right.left.right)
 
 		// REMOVE 20
-		tree2 := Remove(Int(20), tree1)
+		tree2 := Remove(basics.Int(20), tree1)
 		SUT2 := tree2.rbt()
 
-		asserts.Equal(Int(40), SUT2.root.key)
+		asserts.Equal(basics.Int(40), SUT2.root.key)
 		asserts.Equal(black, SUT2.root.color)
 
 		// Structure sharing
@@ -763,7 +820,7 @@ func TestRemove(t *testing.T) {
 		asserts.True(SUT1.root.right.right.right == SUT2.root.right.right.right)
 
 		// REMOVE 100
-		tree3 := Remove(Int(100), tree2)
+		tree3 := Remove(basics.Int(100), tree2)
 		SUT3 := tree3.rbt()
 
 		// Different root
@@ -776,28 +833,28 @@ func TestRemove(t *testing.T) {
 		asserts.True(SUT2.root.left.left == SUT3.root.left.left)
 
 		// REMOVE 90
-		tree4 := Remove(Int(90), tree3)
+		tree4 := Remove(basics.Int(90), tree3)
 		SUT4 := tree4.rbt()
 
-		asserts.Equal(Int(40), SUT4.root.key)
-		asserts.Equal(Int(70), SUT4.root.right.key)
+		asserts.Equal(basics.Int(40), SUT4.root.key)
+		asserts.Equal(basics.Int(70), SUT4.root.right.key)
 		asserts.Equal(red, SUT4.root.right.color)
-		asserts.Equal(Int(80), SUT4.root.right.right.key)
+		asserts.Equal(basics.Int(80), SUT4.root.right.right.key)
 		asserts.Equal(black, SUT4.root.right.right.color)
-		asserts.Equal(Int(60), SUT4.root.right.left.key)
+		asserts.Equal(basics.Int(60), SUT4.root.right.left.key)
 		asserts.Equal(black, SUT4.root.right.left.color)
 		asserts.Nil(SUT4.root.right.left.left)
 		asserts.Nil(SUT4.root.right.left.right)
 
 		// REMOVE 40
-		tree5 := Remove(Int(40), tree4)
+		tree5 := Remove(basics.Int(40), tree4)
 		SUT5 := tree5.rbt()
 
-		asserts.Equal(Int(60), SUT5.root.key)
+		asserts.Equal(basics.Int(60), SUT5.root.key)
 		asserts.Equal(black, SUT5.root.color)
-		asserts.Equal(Int(70), SUT5.root.right.key)
+		asserts.Equal(basics.Int(70), SUT5.root.right.key)
 		asserts.Equal(black, SUT5.root.right.color)
-		asserts.Equal(Int(80), SUT5.root.right.right.key)
+		asserts.Equal(basics.Int(80), SUT5.root.right.right.key)
 		asserts.Equal(red, SUT5.root.right.right.color)
 
 		// Structure sharing
@@ -805,46 +862,46 @@ func TestRemove(t *testing.T) {
 		asserts.True(tree5.rbt().root.left.left == SUT5.root.left.left)
 
 		// REMOVE 60
-		tree6 := Remove(Int(60), tree5)
+		tree6 := Remove(basics.Int(60), tree5)
 		SUT6 := tree6.rbt()
 
-		asserts.Equal(Int(70), SUT6.root.key)
+		asserts.Equal(basics.Int(70), SUT6.root.key)
 		asserts.Equal(black, SUT6.root.color)
-		asserts.Equal(Int(80), SUT6.root.right.key)
+		asserts.Equal(basics.Int(80), SUT6.root.right.key)
 		asserts.Equal(black, SUT6.root.right.color)
 
 		// REMOVE 70
-		tree7 := Remove(Int(70), tree6)
+		tree7 := Remove(basics.Int(70), tree6)
 		SUT7 := tree7.rbt()
 
-		asserts.Equal(Int(30), SUT7.root.key)
+		asserts.Equal(basics.Int(30), SUT7.root.key)
 		asserts.Equal(black, SUT7.root.color)
-		asserts.Equal(Int(80), SUT7.root.right.key)
+		asserts.Equal(basics.Int(80), SUT7.root.right.key)
 		asserts.Equal(black, SUT7.root.right.color)
 
 		// Structure sharing
 		asserts.True(tree7.rbt().root.left == SUT7.root.left)
 
 		// REMOVE 10
-		tree8 := Remove(Int(10), tree7)
+		tree8 := Remove(basics.Int(10), tree7)
 		SUT8 := tree8.rbt()
 
-		asserts.Equal(Int(30), SUT8.root.key)
+		asserts.Equal(basics.Int(30), SUT8.root.key)
 		asserts.Equal(black, SUT8.root.color)
-		asserts.Equal(Int(80), SUT8.root.right.key)
+		asserts.Equal(basics.Int(80), SUT8.root.right.key)
 		asserts.Equal(red, SUT8.root.right.color)
 		asserts.Nil(SUT8.root.left)
 
 		// REMOVE 30
-		tree9 := Remove(Int(30), tree8)
+		tree9 := Remove(basics.Int(30), tree8)
 		SUT9 := tree9.rbt()
 
-		asserts.Equal(Int(80), SUT9.root.key)
+		asserts.Equal(basics.Int(80), SUT9.root.key)
 		asserts.Equal(black, SUT9.root.color)
 		asserts.Nil(SUT9.root.right)
 
 		// REMOVE 80
-		tree10 := Remove(Int(80), tree9)
+		tree10 := Remove(basics.Int(80), tree9)
 		SUT10 := tree10.rbt()
 
 		asserts.Nil(SUT10.root)
@@ -860,20 +917,20 @@ func TestListFunctions(t *testing.T) {
 	asserts := assert.New(t)
 
 	t.Run("Keys", func(t *testing.T) {
-		xs := list.FromSlice([]tuple.Tuple2[Int, int]{
-			tuple.Pair(Int(Int(1)), 1),
-			tuple.Pair(Int(Int(2)), 2),
-			tuple.Pair(Int(Int(3)), 3),
+		xs := list.FromSlice([]tuple.Tuple2[basics.Int, int]{
+			tuple.Pair(basics.Int(basics.Int(1)), 1),
+			tuple.Pair(basics.Int(basics.Int(2)), 2),
+			tuple.Pair(basics.Int(basics.Int(3)), 3),
 		})
 		dxs := FromList(xs)
 		SUT := Keys(dxs)
 
-		asserts.Equal([]Int{Int(1), Int(2), Int(3)}, list.ToSlice(SUT))
+		asserts.Equal([]basics.Int{basics.Int(1), basics.Int(2), basics.Int(3)}, list.ToSlice(SUT))
 	})
 	t.Run("Values", func(t *testing.T) {
-		xs := list.FromSlice([]tuple.Tuple2[Int, string]{
-			tuple.Pair(Int(Int(1)), "Alice"),
-			tuple.Pair(Int(Int(2)), "Bob"),
+		xs := list.FromSlice([]tuple.Tuple2[basics.Int, string]{
+			tuple.Pair(basics.Int(basics.Int(1)), "Alice"),
+			tuple.Pair(basics.Int(basics.Int(2)), "Bob"),
 		})
 		dxs := FromList(xs)
 		SUT := Values(dxs)
@@ -882,56 +939,56 @@ func TestListFunctions(t *testing.T) {
 	})
 
 	t.Run("ToList", func(t *testing.T) {
-		xs := list.FromSlice([]tuple.Tuple2[Int, int]{
-			tuple.Pair(Int(1), 1),
-			tuple.Pair(Int(2), 2),
+		xs := list.FromSlice([]tuple.Tuple2[basics.Int, int]{
+			tuple.Pair(basics.Int(1), 1),
+			tuple.Pair(basics.Int(2), 2),
 		})
 		SUT := ToList(FromList(xs))
 
-		asserts.Equal([]tuple.Tuple2[Int, int]{
-			tuple.Pair(Int(1), 1),
-			tuple.Pair(Int(2), 2),
+		asserts.Equal([]tuple.Tuple2[basics.Int, int]{
+			tuple.Pair(basics.Int(1), 1),
+			tuple.Pair(basics.Int(2), 2),
 		},
 			list.ToSlice(SUT),
 		)
 	})
 
 	t.Run("FromList", func(t *testing.T) {
-		xs := list.FromSlice([]tuple.Tuple2[Int, int]{
-			tuple.Pair(Int(1), 1),
-			tuple.Pair(Int(2), 2),
-			tuple.Pair(Int(3), 3),
+		xs := list.FromSlice([]tuple.Tuple2[basics.Int, int]{
+			tuple.Pair(basics.Int(1), 1),
+			tuple.Pair(basics.Int(2), 2),
+			tuple.Pair(basics.Int(3), 3),
 		})
 		SUT := FromList(xs)
 
-		asserts.Equal([]Int{Int(1), Int(2), Int(3)}, list.ToSlice(Keys(SUT)))
+		asserts.Equal([]basics.Int{basics.Int(1), basics.Int(2), basics.Int(3)}, list.ToSlice(Keys(SUT)))
 	})
 }
 
-func insertBoth[K Comparable[K], B any](key K, leftVal list.List[B], rightVal list.List[B], dict Dict[K, list.List[B]]) Dict[K, list.List[B]] {
+func insertBoth[K basics.Comparable[K], B any](key K, leftVal list.List[B], rightVal list.List[B], dict Dict[K, list.List[B]]) Dict[K, list.List[B]] {
 	return Insert(key, list.Append(leftVal, rightVal), dict)
 }
 func TestTransformFunctions(t *testing.T) {
 	asserts := assert.New(t)
 
 	t.Run("Map", func(t *testing.T) {
-		xs := list.FromSlice([]tuple.Tuple2[Int, int]{tuple.Pair(Int(1), 1), tuple.Pair(Int(2), 2), tuple.Pair(Int(3), 3)})
+		xs := list.FromSlice([]tuple.Tuple2[basics.Int, int]{tuple.Pair(basics.Int(1), 1), tuple.Pair(basics.Int(2), 2), tuple.Pair(basics.Int(3), 3)})
 		d := FromList(xs)
-		mapper := func(k Int, v int) int { return (v + 1) }
+		mapper := func(k basics.Int, v int) int { return (v + 1) }
 		SUT := Map(mapper, d)
 
 		asserts.Equal([]int{2, 3, 4}, list.ToSlice(Values(SUT)))
 	})
 
 	t.Run("Foldl", func(t *testing.T) {
-		xs := list.FromSlice([]tuple.Tuple2[Int, string]{
-			tuple.Pair(Int(1), "hello"),
-			tuple.Pair(Int(2), "my"),
-			tuple.Pair(Int(3), "name"),
-			tuple.Pair(Int(4), "is"),
+		xs := list.FromSlice([]tuple.Tuple2[basics.Int, string]{
+			tuple.Pair(basics.Int(1), "hello"),
+			tuple.Pair(basics.Int(2), "my"),
+			tuple.Pair(basics.Int(3), "name"),
+			tuple.Pair(basics.Int(4), "is"),
 		})
 		d := FromList(xs)
-		SUT := Foldl(func(k Int, v string, acc string) string {
+		SUT := Foldl(func(k basics.Int, v string, acc string) string {
 			return acc + v + "pause"
 		},
 			"",
@@ -942,8 +999,8 @@ func TestTransformFunctions(t *testing.T) {
 	})
 
 	t.Run("Foldr", func(t *testing.T) {
-		getAges := func(_ Int, user User, ages list.List[Int]) list.List[Int] {
-			return list.Cons(Int(user.Age), ages)
+		getAges := func(_ basics.Int, user User, ages list.List[basics.Int]) list.List[basics.Int] {
+			return list.Cons(basics.Int(user.Age), ages)
 		}
 		user1 := User{
 			Name: "Jdawg",
@@ -954,38 +1011,38 @@ func TestTransformFunctions(t *testing.T) {
 			Age:  30,
 		}
 		users := list.FromSlice([]User{user1, user2})
-		d := list.Foldl(func(usr User, acc Dict[Int, User]) Dict[Int, User] {
-			return Insert(Int(usr.Age), usr, acc)
+		d := list.Foldl(func(usr User, acc Dict[basics.Int, User]) Dict[basics.Int, User] {
+			return Insert(basics.Int(usr.Age), usr, acc)
 		},
-			Empty[Int, User](),
+			Empty[basics.Int, User](),
 			users,
 		)
-		SUT := Foldr(getAges, list.Empty[Int](), d)
+		SUT := Foldr(getAges, list.Empty[basics.Int](), d)
 
-		asserts.Equal([]Int{30, 42}, list.ToSlice(SUT))
+		asserts.Equal([]basics.Int{30, 42}, list.ToSlice(SUT))
 	})
 
 	t.Run("Filter", func(t *testing.T) {
-		xxs := list.FromSlice([]tuple.Tuple2[Int, int]{
-			tuple.Pair(Int(1), 1),
-			tuple.Pair(Int(2), 2),
-			tuple.Pair(Int(3), 3),
+		xxs := list.FromSlice([]tuple.Tuple2[basics.Int, int]{
+			tuple.Pair(basics.Int(1), 1),
+			tuple.Pair(basics.Int(2), 2),
+			tuple.Pair(basics.Int(3), 3),
 		})
 		d := FromList(xxs)
-		filterer := func(k Int, v int) bool { return v > 2 }
+		filterer := func(k basics.Int, v int) bool { return v > 2 }
 		SUT := Filter(filterer, d)
 
 		asserts.Equal([]int{3}, list.ToSlice(Values(SUT)))
 	})
 
 	t.Run("Partition", func(t *testing.T) {
-		xxs := list.FromSlice([]tuple.Tuple2[Int, int]{
-			tuple.Pair(Int(1), 1),
-			tuple.Pair(Int(2), 2),
-			tuple.Pair(Int(3), 3),
+		xxs := list.FromSlice([]tuple.Tuple2[basics.Int, int]{
+			tuple.Pair(basics.Int(1), 1),
+			tuple.Pair(basics.Int(2), 2),
+			tuple.Pair(basics.Int(3), 3),
 		})
 		d := FromList(xxs)
-		isGood := func(k Int, v int) bool { return v > 2 }
+		isGood := func(k basics.Int, v int) bool { return v > 2 }
 		SUT := Partition(isGood, d)
 
 		asserts.Equal([]int{3}, list.ToSlice(Values(tuple.First(SUT))))
@@ -993,15 +1050,15 @@ func TestTransformFunctions(t *testing.T) {
 	})
 
 	t.Run("Union", func(t *testing.T) {
-		xs1 := list.FromSlice([]tuple.Tuple2[Int, int]{
-			tuple.Pair(Int(1), 2),
-			tuple.Pair(Int(2), 2),
-			tuple.Pair(Int(3), 3),
+		xs1 := list.FromSlice([]tuple.Tuple2[basics.Int, int]{
+			tuple.Pair(basics.Int(1), 2),
+			tuple.Pair(basics.Int(2), 2),
+			tuple.Pair(basics.Int(3), 3),
 		})
-		xs2 := list.FromSlice([]tuple.Tuple2[Int, int]{
-			tuple.Pair(Int(1), 1),
-			tuple.Pair(Int(2), 2),
-			tuple.Pair(Int(3), 3),
+		xs2 := list.FromSlice([]tuple.Tuple2[basics.Int, int]{
+			tuple.Pair(basics.Int(1), 1),
+			tuple.Pair(basics.Int(2), 2),
+			tuple.Pair(basics.Int(3), 3),
 		})
 		d1 := FromList(xs1)
 		d2 := FromList(xs2)
@@ -1012,15 +1069,15 @@ func TestTransformFunctions(t *testing.T) {
 	})
 
 	t.Run("Intersect", func(t *testing.T) {
-		xs1 := list.FromSlice([]tuple.Tuple2[Int, int]{
-			tuple.Pair(Int(1), 23),
-			tuple.Pair(Int(2), 2),
-			tuple.Pair(Int(3), 3),
+		xs1 := list.FromSlice([]tuple.Tuple2[basics.Int, int]{
+			tuple.Pair(basics.Int(1), 23),
+			tuple.Pair(basics.Int(2), 2),
+			tuple.Pair(basics.Int(3), 3),
 		})
-		xs2 := list.FromSlice([]tuple.Tuple2[Int, int]{
-			tuple.Pair(Int(1), 1),
-			tuple.Pair(Int(5), 2),
-			tuple.Pair(Int(6), 3),
+		xs2 := list.FromSlice([]tuple.Tuple2[basics.Int, int]{
+			tuple.Pair(basics.Int(1), 1),
+			tuple.Pair(basics.Int(5), 2),
+			tuple.Pair(basics.Int(6), 3),
 		})
 
 		d1 := FromList(xs1)
@@ -1028,20 +1085,20 @@ func TestTransformFunctions(t *testing.T) {
 
 		SUT := Intersect(d1, d2)
 
-		asserts.Equal([]Int{Int(1)}, list.ToSlice(Keys(SUT)))
+		asserts.Equal([]basics.Int{basics.Int(1)}, list.ToSlice(Keys(SUT)))
 		asserts.Equal([]int{23}, list.ToSlice(Values(SUT)))
 	})
 
 	t.Run("Diff", func(t *testing.T) {
-		xs1 := list.FromSlice([]tuple.Tuple2[Int, int]{
-			tuple.Pair(Int(23), 23),
-			tuple.Pair(Int(5), 2),
-			tuple.Pair(Int(6), 3),
+		xs1 := list.FromSlice([]tuple.Tuple2[basics.Int, int]{
+			tuple.Pair(basics.Int(23), 23),
+			tuple.Pair(basics.Int(5), 2),
+			tuple.Pair(basics.Int(6), 3),
 		})
-		xs2 := list.FromSlice([]tuple.Tuple2[Int, int]{
-			tuple.Pair(Int(1), 1),
-			tuple.Pair(Int(5), 2),
-			tuple.Pair(Int(6), 3),
+		xs2 := list.FromSlice([]tuple.Tuple2[basics.Int, int]{
+			tuple.Pair(basics.Int(1), 1),
+			tuple.Pair(basics.Int(5), 2),
+			tuple.Pair(basics.Int(6), 3),
 		})
 
 		d1 := FromList(xs1)
@@ -1049,20 +1106,20 @@ func TestTransformFunctions(t *testing.T) {
 
 		SUT := Diff(d1, d2)
 
-		asserts.Equal([]Int{Int(23)}, list.ToSlice(Keys(SUT)))
+		asserts.Equal([]basics.Int{basics.Int(23)}, list.ToSlice(Keys(SUT)))
 		asserts.Equal([]int{23}, list.ToSlice(Values(SUT)))
 	})
 
 	t.Run("Merge", func(t *testing.T) {
-		s1 := Insert(s.String("u1"), list.Singleton(Int(1)), Empty[s.String, list.List[Int]]())
-		s2 := Insert(s.String("u2"), list.Singleton(Int(2)), Empty[s.String, list.List[Int]]())
-		s23 := Insert(s.String("u2"), list.Singleton(Int(3)), Empty[s.String, list.List[Int]]())
-		b1 := FromList(list.Map(func(i Int) tuple.Tuple2[Int, list.List[Int]] {
+		s1 := Insert(s.String("u1"), list.Singleton(basics.Int(1)), Empty[s.String, list.List[basics.Int]]())
+		s2 := Insert(s.String("u2"), list.Singleton(basics.Int(2)), Empty[s.String, list.List[basics.Int]]())
+		s23 := Insert(s.String("u2"), list.Singleton(basics.Int(3)), Empty[s.String, list.List[basics.Int]]())
+		b1 := FromList(list.Map(func(i basics.Int) tuple.Tuple2[basics.Int, list.List[basics.Int]] {
 			return tuple.Pair(i, list.Singleton(i))
 		},
 			list.Range(1, 10),
 		))
-		b2 := FromList(list.Map(func(i Int) tuple.Tuple2[Int, list.List[Int]] {
+		b2 := FromList(list.Map(func(i basics.Int) tuple.Tuple2[basics.Int, list.List[basics.Int]] {
 			return tuple.Pair(i, list.Singleton(i))
 		},
 			list.Range(5, 15),
@@ -1082,83 +1139,860 @@ func TestTransformFunctions(t *testing.T) {
 		})
 		t.Run("merge singletons in order", func(t *testing.T) {
 			SUT := Merge(
-				Insert[s.String, list.List[Int]],
-				insertBoth[s.String, Int],
-				Insert[s.String, list.List[Int]],
+				Insert[s.String, list.List[basics.Int]],
+				insertBoth[s.String, basics.Int],
+				Insert[s.String, list.List[basics.Int]],
 				s1,
 				s2,
-				Empty[s.String, list.List[Int]](),
+				Empty[s.String, list.List[basics.Int]](),
 			)
 
-			expected := list.FromSlice([]tuple.Tuple2[s.String, list.List[Int]]{
-				tuple.Pair(s.String("u1"), list.Singleton(Int(1))),
-				tuple.Pair(s.String("u2"), list.Singleton(Int(2))),
+			expected := list.FromSlice([]tuple.Tuple2[s.String, list.List[basics.Int]]{
+				tuple.Pair(s.String("u1"), list.Singleton(basics.Int(1))),
+				tuple.Pair(s.String("u2"), list.Singleton(basics.Int(2))),
 			})
 
 			asserts.Equal(expected, ToList(SUT))
 		})
 		t.Run("merge singletons out of order", func(t *testing.T) {
 			SUT := Merge(
-				Insert[s.String, list.List[Int]],
-				insertBoth[s.String, Int],
-				Insert[s.String, list.List[Int]],
+				Insert[s.String, list.List[basics.Int]],
+				insertBoth[s.String, basics.Int],
+				Insert[s.String, list.List[basics.Int]],
 				s2,
 				s1,
-				Empty[s.String, list.List[Int]](),
+				Empty[s.String, list.List[basics.Int]](),
 			)
 
-			expected := list.FromSlice([]tuple.Tuple2[s.String, list.List[Int]]{
-				tuple.Pair(s.String("u1"), list.Singleton(Int(1))),
-				tuple.Pair(s.String("u2"), list.Singleton(Int(2))),
+			expected := list.FromSlice([]tuple.Tuple2[s.String, list.List[basics.Int]]{
+				tuple.Pair(s.String("u1"), list.Singleton(basics.Int(1))),
+				tuple.Pair(s.String("u2"), list.Singleton(basics.Int(2))),
 			})
 
 			asserts.Equal(expected, ToList(SUT))
 		})
 		t.Run("merge with duplicate key", func(t *testing.T) {
 			SUT := Merge(
-				Insert[s.String, list.List[Int]],
-				insertBoth[s.String, Int],
-				Insert[s.String, list.List[Int]],
+				Insert[s.String, list.List[basics.Int]],
+				insertBoth[s.String, basics.Int],
+				Insert[s.String, list.List[basics.Int]],
 				s2,
 				s23,
-				Empty[s.String, list.List[Int]](),
+				Empty[s.String, list.List[basics.Int]](),
 			)
 
-			expected := list.FromSlice([]tuple.Tuple2[s.String, list.List[Int]]{
-				tuple.Pair(s.String("u2"), list.FromSlice([]Int{2, 3})),
+			expected := list.FromSlice([]tuple.Tuple2[s.String, list.List[basics.Int]]{
+				tuple.Pair(s.String("u2"), list.FromSlice([]basics.Int{2, 3})),
 			})
 
 			asserts.Equal(expected, ToList(SUT))
 		})
 		t.Run("partially overlapping", func(t *testing.T) {
 			SUT := Merge(
-				Insert[Int, list.List[Int]],
-				insertBoth[Int, Int],
-				Insert[Int, list.List[Int]],
+				Insert[basics.Int, list.List[basics.Int]],
+				insertBoth[basics.Int, basics.Int],
+				Insert[basics.Int, list.List[basics.Int]],
 				b1,
 				b2,
-				Empty[Int, list.List[Int]](),
+				Empty[basics.Int, list.List[basics.Int]](),
 			)
 
-			expected := list.FromSlice([]tuple.Tuple2[Int, list.List[Int]]{
-				tuple.Pair(Int(1), list.FromSlice([]Int{Int(1)})),
-				tuple.Pair(Int(2), list.FromSlice([]Int{Int(2)})),
-				tuple.Pair(Int(3), list.FromSlice([]Int{Int(3)})),
-				tuple.Pair(Int(4), list.FromSlice([]Int{Int(4)})),
-				tuple.Pair(Int(5), list.FromSlice([]Int{Int(5), Int(5)})),
-				tuple.Pair(Int(6), list.FromSlice([]Int{Int(6), Int(6)})),
-				tuple.Pair(Int(7), list.FromSlice([]Int{Int(7), Int(7)})),
-				tuple.Pair(Int(8), list.FromSlice([]Int{Int(8), Int(8)})),
-				tuple.Pair(Int(9), list.FromSlice([]Int{Int(9), Int(9)})),
-				tuple.Pair(Int(10), list.FromSlice([]Int{Int(10), Int(10)})),
-				tuple.Pair(Int(11), list.FromSlice([]Int{Int(11)})),
-				tuple.Pair(Int(12), list.FromSlice([]Int{Int(12)})),
-				tuple.Pair(Int(13), list.FromSlice([]Int{Int(13)})),
-				tuple.Pair(Int(14), list.FromSlice([]Int{Int(14)})),
-				tuple.Pair(Int(15), list.FromSlice([]Int{Int(15)})),
+			expected := list.FromSlice([]tuple.Tuple2[basics.Int, list.List[basics.Int]]{
+				tuple.Pair(basics.Int(1), list.FromSlice([]basics.Int{basics.Int(1)})),
+				tuple.Pair(basics.Int(2), list.FromSlice([]basics.Int{basics.Int(2)})),
+				tuple.Pair(basics.Int(3), list.FromSlice([]basics.Int{basics.Int(3)})),
+				tuple.Pair(basics.Int(4), list.FromSlice([]basics.Int{basics.Int(4)})),
+				tuple.Pair(basics.Int(5), list.FromSlice([]basics.Int{basics.Int(5), basics.Int(5)})),
+				tuple.Pair(basics.Int(6), list.FromSlice([]basics.Int{basics.Int(6), basics.Int(6)})),
+				tuple.Pair(basics.Int(7), list.FromSlice([]basics.Int{basics.Int(7), basics.Int(7)})),
+				tuple.Pair(basics.Int(8), list.FromSlice([]basics.Int{basics.Int(8), basics.Int(8)})),
+				tuple.Pair(basics.Int(9), list.FromSlice([]basics.Int{basics.Int(9), basics.Int(9)})),
+				tuple.Pair(basics.Int(10), list.FromSlice([]basics.Int{basics.Int(10), basics.Int(10)})),
+				tuple.Pair(basics.Int(11), list.FromSlice([]basics.Int{basics.Int(11)})),
+				tuple.Pair(basics.Int(12), list.FromSlice([]basics.Int{basics.Int(12)})),
+				tuple.Pair(basics.Int(13), list.FromSlice([]basics.Int{basics.Int(13)})),
+				tuple.Pair(basics.Int(14), list.FromSlice([]basics.Int{basics.Int(14)})),
+				tuple.Pair(basics.Int(15), list.FromSlice([]basics.Int{basics.Int(15)})),
 			})
 
 			asserts.Equal(expected, ToList(SUT))
 		})
 	})
 }
+
+// Check the red-black rules, key order, subtree sizes and that the root is black.
+func isValidTree[K basics.Comparable[K], V any](d Dict[K, V]) bool {
+	root := d.rbt().root
+	if root.isRed() {
+		return false
+	}
+	_, ok := validHelp(root, maybe.Nothing{}, maybe.Nothing{})
+	return ok && validSize(root)
+}
+
+func validSize[K basics.Comparable[K], V any](n *node[K, V]) bool {
+	if n == nil {
+		return true
+	}
+	return n.size == 1+sizeOf(n.left)+sizeOf(n.right) && validSize(n.left) && validSize(n.right)
+}
+
+func validHelp[K basics.Comparable[K], V any](n *node[K, V], lo maybe.Maybe[K], hi maybe.Maybe[K]) (int, bool) {
+	if n == nil {
+		return 0, true
+	}
+	if j, ok := lo.(maybe.Just[K]); ok && n.key.Cmp(j.Value) <= 0 {
+		return 0, false
+	}
+	if j, ok := hi.(maybe.Just[K]); ok && n.key.Cmp(j.Value) >= 0 {
+		return 0, false
+	}
+	if n.isRed() && (n.left.isRed() || n.right.isRed()) {
+		return 0, false
+	}
+	lh, lok := validHelp(n.left, lo, maybe.Just[K]{Value: n.key})
+	rh, rok := validHelp(n.right, maybe.Just[K]{Value: n.key}, hi)
+	if !lok || !rok || lh != rh {
+		return 0, false
+	}
+	if n.color == black {
+		lh++
+	}
+	return lh, true
+}
+
+func fromKeys(keys ...basics.Int) Dict[basics.Int, basics.Int] {
+	return list.Foldl(
+		func(k basics.Int, d Dict[basics.Int, basics.Int]) Dict[basics.Int, basics.Int] {
+			return Insert(k, k*10, d)
+		},
+		Empty[basics.Int, basics.Int](),
+		list.FromSlice(keys),
+	)
+}
+
+func pair(k basics.Int) maybe.Maybe[tuple.Tuple2[basics.Int, basics.Int]] {
+	return maybe.Just[tuple.Tuple2[basics.Int, basics.Int]]{Value: tuple.Pair(k, k*10)}
+}
+
+func TestNavigation(t *testing.T) {
+	asserts := assert.New(t)
+	d := fromKeys(10, 20, 30, 40, 50)
+	empty := Empty[basics.Int, basics.Int]()
+
+	t.Run("Min", func(t *testing.T) {
+		asserts.Equal(pair(10), Min(d))
+		asserts.Equal(maybe.Nothing{}, Min(empty))
+	})
+	t.Run("Max", func(t *testing.T) {
+		asserts.Equal(pair(50), Max(d))
+		asserts.Equal(maybe.Nothing{}, Max(empty))
+	})
+	t.Run("Floor", func(t *testing.T) {
+		asserts.Equal(pair(30), Floor(30, d))
+		asserts.Equal(pair(30), Floor(35, d))
+		asserts.Equal(pair(50), Floor(99, d))
+		asserts.Equal(maybe.Nothing{}, Floor(9, d))
+		asserts.Equal(maybe.Nothing{}, Floor(1, empty))
+	})
+	t.Run("Ceiling", func(t *testing.T) {
+		asserts.Equal(pair(30), Ceiling(30, d))
+		asserts.Equal(pair(40), Ceiling(35, d))
+		asserts.Equal(pair(10), Ceiling(-5, d))
+		asserts.Equal(maybe.Nothing{}, Ceiling(51, d))
+	})
+	t.Run("Lower", func(t *testing.T) {
+		asserts.Equal(pair(20), Lower(30, d))
+		asserts.Equal(pair(30), Lower(35, d))
+		asserts.Equal(maybe.Nothing{}, Lower(10, d))
+	})
+	t.Run("Higher", func(t *testing.T) {
+		asserts.Equal(pair(40), Higher(30, d))
+		asserts.Equal(pair(40), Higher(35, d))
+		asserts.Equal(maybe.Nothing{}, Higher(50, d))
+	})
+	t.Run("Split on a member", func(t *testing.T) {
+		SUT := Split(30, d)
+
+		asserts.Equal([]basics.Int{10, 20}, list.ToSlice(Keys(tuple.First3(SUT))))
+		asserts.Equal(maybe.Just[basics.Int]{Value: 300}, tuple.Second3(SUT))
+		asserts.Equal([]basics.Int{40, 50}, list.ToSlice(Keys(tuple.Third(SUT))))
+	})
+	t.Run("Split on a missing key", func(t *testing.T) {
+		SUT := Split(35, d)
+
+		asserts.Equal([]basics.Int{10, 20, 30}, list.ToSlice(Keys(tuple.First3(SUT))))
+		asserts.Equal(maybe.Nothing{}, tuple.Second3(SUT))
+		asserts.Equal([]basics.Int{40, 50}, list.ToSlice(Keys(tuple.Third(SUT))))
+	})
+	t.Run("Split leaves the original untouched", func(t *testing.T) {
+		Split(30, d)
+
+		asserts.Equal([]basics.Int{10, 20, 30, 40, 50}, list.ToSlice(Keys(d)))
+		asserts.True(isValidTree(d))
+	})
+	t.Run("Range", func(t *testing.T) {
+		keys := func(lo, hi Bound[basics.Int]) []basics.Int { return list.ToSlice(Keys(Range(lo, hi, d))) }
+
+		asserts.Equal([]basics.Int{20, 30, 40}, keys(Inclusive[basics.Int]{Key: 20}, Inclusive[basics.Int]{Key: 40}))
+		asserts.Equal([]basics.Int{30}, keys(Exclusive[basics.Int]{Key: 20}, Exclusive[basics.Int]{Key: 40}))
+		asserts.Equal([]basics.Int{20, 30}, keys(Inclusive[basics.Int]{Key: 15}, Exclusive[basics.Int]{Key: 40}))
+		asserts.Equal([]basics.Int{10, 20}, keys(Unbounded{}, Inclusive[basics.Int]{Key: 20}))
+		asserts.Equal([]basics.Int{40, 50}, keys(Exclusive[basics.Int]{Key: 30}, Unbounded{}))
+		asserts.Equal([]basics.Int{10, 20, 30, 40, 50}, keys(Unbounded{}, Unbounded{}))
+		asserts.Equal([]basics.Int{}, keys(Inclusive[basics.Int]{Key: 41}, Inclusive[basics.Int]{Key: 49}))
+		asserts.Equal([]basics.Int{}, keys(Inclusive[basics.Int]{Key: 40}, Inclusive[basics.Int]{Key: 20}))
+	})
+	t.Run("FoldlRange", func(t *testing.T) {
+		collect := func(k basics.Int, v basics.Int, acc list.List[basics.Int]) list.List[basics.Int] {
+			return list.Cons(v, acc)
+		}
+
+		asserts.Equal(
+			[]basics.Int{400, 300, 200},
+			list.ToSlice(FoldlRange(collect, list.Empty[basics.Int](), Inclusive[basics.Int]{Key: 20}, Inclusive[basics.Int]{Key: 40}, d)),
+		)
+		asserts.Equal(
+			[]basics.Int{500, 400},
+			list.ToSlice(FoldlRange(collect, list.Empty[basics.Int](), Exclusive[basics.Int]{Key: 30}, Unbounded{}, d)),
+		)
+		asserts.Equal(
+			[]basics.Int{},
+			list.ToSlice(FoldlRange(collect, list.Empty[basics.Int](), Unbounded{}, Unbounded{}, empty)),
+		)
+	})
+	t.Run("FoldlRange only visits keys in range", func(t *testing.T) {
+		visited := 0
+		SUT := FoldlRange(
+			func(k basics.Int, _ basics.Int, acc basics.Int) basics.Int { visited++; return acc + k },
+			0,
+			Inclusive[basics.Int]{Key: 500},
+			Exclusive[basics.Int]{Key: 510},
+			fromKeys(list.ToSlice(list.Range(1, 1000))...),
+		)
+
+		asserts.Equal(basics.Int(5045), SUT)
+		asserts.Equal(10, visited)
+	})
+	t.Run("Split and Range agree with Filter on larger dictionaries", func(t *testing.T) {
+		big := fromKeys(list.ToSlice(list.Map(func(i basics.Int) basics.Int { return basics.ModBy(997, i*7919) }, list.Range(1, 600)))...)
+
+		for _, k := range []basics.Int{-1, 0, 1, 13, 250, 498, 499, 500, 996, 997} {
+			SUT := Split(k, big)
+			lower, upper := tuple.First3(SUT), tuple.Third(SUT)
+
+			asserts.Equal(ToList(Filter(func(key basics.Int, _ basics.Int) bool { return key < k }, big)), ToList(lower))
+			asserts.Equal(ToList(Filter(func(key basics.Int, _ basics.Int) bool { return key > k }, big)), ToList(upper))
+			asserts.Equal(Get(k, big), tuple.Second3(SUT))
+			asserts.True(isValidTree(lower))
+			asserts.True(isValidTree(upper))
+
+			inRange := Range(Exclusive[basics.Int]{Key: k}, Inclusive[basics.Int]{Key: k + 300}, big)
+			asserts.Equal(ToList(Filter(func(key basics.Int, _ basics.Int) bool { return key > k && key <= k+300 }, big)), ToList(inRange))
+			asserts.True(isValidTree(inRange))
+		}
+		asserts.True(isValidTree(big))
+	})
+}
+
+func TestOrderStatistics(t *testing.T) {
+	asserts := assert.New(t)
+	d := fromKeys(10, 20, 30, 40, 50)
+	empty := Empty[basics.Int, basics.Int]()
+
+	t.Run("Size does not change when replacing a value", func(t *testing.T) {
+		asserts.Equal(basics.Int(5), Size(Insert(30, 0, d)))
+	})
+	t.Run("Size after Remove", func(t *testing.T) {
+		asserts.Equal(basics.Int(4), Size(Remove(30, d)))
+		asserts.Equal(basics.Int(5), Size(Remove(35, d)))
+		asserts.Equal(basics.Int(0), Size(Remove(1, Singleton(basics.Int(1), 1))))
+	})
+	t.Run("Size after Map, Filter and Split", func(t *testing.T) {
+		asserts.Equal(basics.Int(5), Size(Map(func(k basics.Int, v basics.Int) string { return "" }, d)))
+		asserts.Equal(basics.Int(2), Size(Filter(func(k basics.Int, _ basics.Int) bool { return k > 30 }, d)))
+		asserts.Equal(basics.Int(2), Size(tuple.First3(Split(30, d))))
+	})
+	t.Run("Rank", func(t *testing.T) {
+		asserts.Equal(basics.Int(0), Rank(10, d))
+		asserts.Equal(basics.Int(2), Rank(30, d))
+		asserts.Equal(basics.Int(3), Rank(35, d))
+		asserts.Equal(basics.Int(5), Rank(99, d))
+		asserts.Equal(basics.Int(0), Rank(1, empty))
+	})
+	t.Run("At", func(t *testing.T) {
+		asserts.Equal(pair(10), At(0, d))
+		asserts.Equal(pair(30), At(2, d))
+		asserts.Equal(pair(50), At(4, d))
+		asserts.Equal(maybe.Nothing{}, At(5, d))
+		asserts.Equal(maybe.Nothing{}, At(-1, d))
+		asserts.Equal(maybe.Nothing{}, At(0, empty))
+	})
+	t.Run("TakeSmallest", func(t *testing.T) {
+		asserts.Equal([]basics.Int{10, 20}, list.ToSlice(Keys(TakeSmallest(2, d))))
+		asserts.Equal([]basics.Int{}, list.ToSlice(Keys(TakeSmallest(0, d))))
+		asserts.Equal([]basics.Int{10, 20, 30, 40, 50}, list.ToSlice(Keys(TakeSmallest(9, d))))
+	})
+	t.Run("DropSmallest", func(t *testing.T) {
+		asserts.Equal([]basics.Int{30, 40, 50}, list.ToSlice(Keys(DropSmallest(2, d))))
+		asserts.Equal([]basics.Int{10, 20, 30, 40, 50}, list.ToSlice(Keys(DropSmallest(-1, d))))
+		asserts.Equal([]basics.Int{}, list.ToSlice(Keys(DropSmallest(5, d))))
+	})
+	t.Run("Percentile", func(t *testing.T) {
+		asserts.Equal(pair(10), Percentile(0, d))
+		asserts.Equal(pair(10), Percentile(20, d))
+		asserts.Equal(pair(20), Percentile(21, d))
+		asserts.Equal(pair(50), Percentile(100, d))
+		asserts.Equal(pair(50), Percentile(150, d))
+		asserts.Equal(pair(10), Percentile(-5, d))
+		asserts.Equal(maybe.Nothing{}, Percentile(50, empty))
+	})
+	t.Run("Median", func(t *testing.T) {
+		asserts.Equal(pair(30), Median(d))
+		asserts.Equal(pair(20), Median(fromKeys(10, 20, 30, 40)))
+		asserts.Equal(maybe.Nothing{}, Median(empty))
+	})
+	t.Run("Rank, At, TakeSmallest and DropSmallest agree with ToList", func(t *testing.T) {
+		big := fromKeys(list.ToSlice(list.Map(func(i basics.Int) basics.Int { return basics.ModBy(997, i*7919) }, list.Range(1, 300)))...)
+		pairs := list.ToSlice(ToList(big))
+
+		for i, p := range pairs {
+			asserts.Equal(basics.Int(i), Rank(tuple.First(p), big))
+			asserts.Equal(maybe.Just[tuple.Tuple2[basics.Int, basics.Int]]{Value: p}, At(basics.Int(i), big))
+		}
+		for _, n := range []basics.Int{0, 1, 2, 77, 150, 299, 300} {
+			smallest, rest := TakeSmallest(n, big), DropSmallest(n, big)
+
+			asserts.Equal(pairs[:n], list.ToSlice(ToList(smallest)))
+			asserts.Equal(pairs[n:], list.ToSlice(ToList(rest)))
+			asserts.True(isValidTree(smallest))
+			asserts.True(isValidTree(rest))
+		}
+	})
+}
+
+// The fold based combine functions these are checked and benchmarked against.
+
+func unionByInsert[K basics.Comparable[K], V any](t1 Dict[K, V], t2 Dict[K, V]) Dict[K, V] {
+	return Foldl[K, V, Dict[K, V]](Insert, t2, t1)
+}
+
+func intersectByMember[K basics.Comparable[K], V any](t1 Dict[K, V], t2 Dict[K, V]) Dict[K, V] {
+	return Filter(func(k K, _ V) bool { return Member(k, t2) }, t1)
+}
+
+func diffByRemove[K basics.Comparable[K], V any](t1 Dict[K, V], t2 Dict[K, V]) Dict[K, V] {
+	return Foldl(func(k K, _ V, t Dict[K, V]) Dict[K, V] { return Remove(k, t) }, t1, t2)
+}
+
+// Keys from 0 up to n in steps, with values telling the dictionaries apart.
+func stepped(n basics.Int, step basics.Int, value basics.Int) Dict[basics.Int, basics.Int] {
+	d := Empty[basics.Int, basics.Int]()
+	for k := basics.Int(0); k < n; k += step {
+		d = Insert(k, value, d)
+	}
+	return d
+}
+
+func TestCombineLargeDictionaries(t *testing.T) {
+	asserts := assert.New(t)
+	cases := []tuple.Tuple2[Dict[basics.Int, basics.Int], Dict[basics.Int, basics.Int]]{
+		tuple.Pair(stepped(1000, 2, 1), stepped(1000, 3, 2)),
+		tuple.Pair(stepped(1000, 1, 1), stepped(1000, 7, 2)),
+		tuple.Pair(stepped(50, 1, 1), stepped(3000, 5, 2)),
+		tuple.Pair(stepped(500, 1, 1), stepped(1000, 1, 2)),
+		tuple.Pair(stepped(500, 1, 1), Empty[basics.Int, basics.Int]()),
+		tuple.Pair(Empty[basics.Int, basics.Int](), stepped(500, 1, 2)),
+	}
+
+	t.Run("Union", func(t *testing.T) {
+		for _, c := range cases {
+			d1, d2 := tuple.First(c), tuple.Second(c)
+			SUT := Union(d1, d2)
+
+			asserts.Equal(ToList(unionByInsert(d1, d2)), ToList(SUT))
+			asserts.True(isValidTree(SUT))
+		}
+	})
+	t.Run("Intersect", func(t *testing.T) {
+		for _, c := range cases {
+			d1, d2 := tuple.First(c), tuple.Second(c)
+			SUT := Intersect(d1, d2)
+
+			asserts.Equal(ToList(intersectByMember(d1, d2)), ToList(SUT))
+			asserts.True(isValidTree(SUT))
+		}
+	})
+	t.Run("Diff", func(t *testing.T) {
+		for _, c := range cases {
+			d1, d2 := tuple.First(c), tuple.Second(c)
+			SUT := Diff(d1, d2)
+
+			asserts.Equal(ToList(diffByRemove(d1, d2)), ToList(SUT))
+			asserts.True(isValidTree(SUT))
+		}
+	})
+	t.Run("Merge visits keys from lowest to highest", func(t *testing.T) {
+		for _, c := range cases {
+			d1, d2 := tuple.First(c), tuple.Second(c)
+			SUT := Merge(
+				func(k basics.Int, _ basics.Int, acc []basics.Int) []basics.Int { return append(acc, k) },
+				func(k basics.Int, _ basics.Int, _ basics.Int, acc []basics.Int) []basics.Int { return append(acc, k) },
+				func(k basics.Int, _ basics.Int, acc []basics.Int) []basics.Int { return append(acc, k) },
+				d1,
+				d2,
+				[]basics.Int{},
+			)
+
+			asserts.Equal(list.ToSlice(Keys(unionByInsert(d1, d2))), SUT)
+		}
+	})
+	t.Run("Inputs are left untouched", func(t *testing.T) {
+		d1, d2 := stepped(300, 2, 1), stepped(300, 3, 2)
+		Union(d1, d2)
+		Intersect(d1, d2)
+		Diff(d1, d2)
+
+		asserts.Equal(list.ToSlice(Keys(stepped(300, 2, 1))), list.ToSlice(Keys(d1)))
+		asserts.Equal(list.ToSlice(Keys(stepped(300, 3, 2))), list.ToSlice(Keys(d2)))
+		asserts.True(isValidTree(d1))
+		asserts.True(isValidTree(d2))
+	})
+}
+
+// Two dictionaries of 100k keys, sharing half of their keys.
+func benchmarkDicts(b *testing.B) (Dict[basics.Int, basics.Int], Dict[basics.Int, basics.Int]) {
+	d1, d2 := Empty[basics.Int, basics.Int](), Empty[basics.Int, basics.Int]()
+	for k := basics.Int(0); k < 100_000; k++ {
+		d1 = Insert(k, 1, d1)
+		d2 = Insert(k+50_000, 2, d2)
+	}
+	b.ResetTimer()
+	return d1, d2
+}
+
+func BenchmarkUnion(b *testing.B) {
+	b.Run("join", func(b *testing.B) {
+		d1, d2 := benchmarkDicts(b)
+		for i := 0; i < b.N; i++ {
+			Union(d1, d2)
+		}
+	})
+	b.Run("fold insert", func(b *testing.B) {
+		d1, d2 := benchmarkDicts(b)
+		for i := 0; i < b.N; i++ {
+			unionByInsert(d1, d2)
+		}
+	})
+}
+
+func BenchmarkIntersect(b *testing.B) {
+	b.Run("join", func(b *testing.B) {
+		d1, d2 := benchmarkDicts(b)
+		for i := 0; i < b.N; i++ {
+			Intersect(d1, d2)
+		}
+	})
+	b.Run("filter member", func(b *testing.B) {
+		d1, d2 := benchmarkDicts(b)
+		for i := 0; i < b.N; i++ {
+			intersectByMember(d1, d2)
+		}
+	})
+}
+
+func BenchmarkDiff(b *testing.B) {
+	b.Run("join", func(b *testing.B) {
+		d1, d2 := benchmarkDicts(b)
+		for i := 0; i < b.N; i++ {
+			Diff(d1, d2)
+		}
+	})
+	b.Run("fold remove", func(b *testing.B) {
+		d1, d2 := benchmarkDicts(b)
+		for i := 0; i < b.N; i++ {
+			diffByRemove(d1, d2)
+		}
+	})
+}
+
+func BenchmarkMerge(b *testing.B) {
+	d1, d2 := benchmarkDicts(b)
+	count := func(_ basics.Int, _ basics.Int, acc int) int { return acc + 1 }
+	for i := 0; i < b.N; i++ {
+		Merge(count, func(_ basics.Int, _ basics.Int, _ basics.Int, acc int) int { return acc + 1 }, count, d1, d2, 0)
+	}
+}
+
+func TestFromSorted(t *testing.T) {
+	asserts := assert.New(t)
+	pairs := func(keys ...basics.Int) []tuple.Tuple2[basics.Int, basics.Int] {
+		xs := []tuple.Tuple2[basics.Int, basics.Int]{}
+		for _, k := range keys {
+			xs = append(xs, tuple.Pair(k, k*10))
+		}
+		return xs
+	}
+
+	t.Run("FromSortedSlice", func(t *testing.T) {
+		SUT := FromSortedSlice(pairs(1, 2, 3))
+
+		asserts.Equal(ToList(fromKeys(1, 2, 3)), ToList(SUT))
+		asserts.Equal(basics.Int(3), Size(SUT))
+	})
+	t.Run("FromSortedList", func(t *testing.T) {
+		SUT := FromSortedList(list.FromSlice(pairs(1, 5, 9)))
+
+		asserts.Equal(ToList(fromKeys(1, 5, 9)), ToList(SUT))
+	})
+	t.Run("FromSortedSlice with no pairs", func(t *testing.T) {
+		asserts.Equal(Empty[basics.Int, basics.Int](), FromSortedSlice(pairs()))
+	})
+	t.Run("FromSortedSlice builds balanced trees of any size", func(t *testing.T) {
+		for n := basics.Int(0); n <= 130; n++ {
+			keys := list.ToSlice(list.Range(1, n))
+			SUT := FromSortedSlice(pairs(keys...))
+
+			asserts.True(isValidTree(SUT), "size %d", n)
+			asserts.Equal(ToList(fromKeys(keys...)), ToList(SUT))
+		}
+	})
+	t.Run("FromSortedSlice panics on unsorted keys", func(t *testing.T) {
+		asserts.Panics(func() { FromSortedSlice(pairs(1, 3, 2)) })
+	})
+	t.Run("FromSortedSlice panics on duplicate keys", func(t *testing.T) {
+		asserts.Panics(func() { FromSortedSlice(pairs(1, 2, 2)) })
+	})
+	t.Run("FromList with sorted input builds a balanced tree", func(t *testing.T) {
+		SUT := FromList(list.FromSlice(pairs(list.ToSlice(list.Range(1, 100))...)))
+
+		asserts.True(isValidTree(SUT))
+		asserts.Equal(FromSortedSlice(pairs(list.ToSlice(list.Range(1, 100))...)), SUT)
+	})
+	t.Run("FromList with sorted input keeps the last value of a duplicate key", func(t *testing.T) {
+		xs := list.FromSlice([]tuple.Tuple2[basics.Int, basics.Int]{
+			tuple.Pair(basics.Int(1), basics.Int(1)),
+			tuple.Pair(basics.Int(2), basics.Int(2)),
+			tuple.Pair(basics.Int(2), basics.Int(3)),
+			tuple.Pair(basics.Int(2), basics.Int(4)),
+			tuple.Pair(basics.Int(3), basics.Int(5)),
+		})
+		SUT := FromList(xs)
+
+		asserts.Equal([]basics.Int{1, 4, 5}, list.ToSlice(Values(SUT)))
+		asserts.True(isValidTree(SUT))
+	})
+	t.Run("FromList with unsorted input", func(t *testing.T) {
+		SUT := FromList(list.FromSlice(pairs(3, 1, 2, 1)))
+
+		asserts.Equal([]basics.Int{1, 2, 3}, list.ToSlice(Keys(SUT)))
+		asserts.True(isValidTree(SUT))
+	})
+	t.Run("FromList leaves its list untouched", func(t *testing.T) {
+		xs := list.FromSlice(pairs(1, 2, 2, 3))
+		FromList(xs)
+
+		asserts.Equal(pairs(1, 2, 2, 3), list.ToSlice(xs))
+	})
+	t.Run("Filter and Partition build balanced trees", func(t *testing.T) {
+		d := FromSortedSlice(pairs(list.ToSlice(list.Range(1, 100))...))
+		isEven := func(k basics.Int, _ basics.Int) bool { return basics.ModBy(2, k) == 0 }
+		SUT := Partition(isEven, d)
+
+		asserts.True(isValidTree(Filter(isEven, d)))
+		asserts.True(isValidTree(tuple.First(SUT)))
+		asserts.True(isValidTree(tuple.Second(SUT)))
+		asserts.Equal(basics.Int(50), Size(tuple.First(SUT)))
+	})
+}
+
+// A million sorted key-value pairs, like a reference table loaded at startup.
+func benchmarkPairs(b *testing.B) []tuple.Tuple2[basics.Int, basics.Int] {
+	xs := make([]tuple.Tuple2[basics.Int, basics.Int], 1_000_000)
+	for i := range xs {
+		xs[i] = tuple.Pair(basics.Int(i), basics.Int(i))
+	}
+	b.ResetTimer()
+	return xs
+}
+
+func BenchmarkFromSortedSlice(b *testing.B) {
+	xs := benchmarkPairs(b)
+	for i := 0; i < b.N; i++ {
+		FromSortedSlice(xs)
+	}
+}
+
+func BenchmarkFromList(b *testing.B) {
+	b.Run("sorted", func(b *testing.B) {
+		xs := list.FromSlice(benchmarkPairs(b))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			FromList(xs)
+		}
+	})
+	b.Run("fold insert", func(b *testing.B) {
+		xs := list.FromSlice(benchmarkPairs(b))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			list.Foldl(
+				func(t tuple.Tuple2[basics.Int, basics.Int], d Dict[basics.Int, basics.Int]) Dict[basics.Int, basics.Int] {
+					return Insert(tuple.First(t), tuple.Second(t), d)
+				},
+				Empty[basics.Int, basics.Int](),
+				xs,
+			)
+		}
+	})
+}
+
+func TestBuilder(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Insert and Freeze", func(t *testing.T) {
+		b := NewBuilder[basics.Int, basics.Int]()
+		b.Insert(2, 20)
+		b.Insert(1, 10)
+		b.Insert(3, 30)
+		SUT := b.Freeze()
+
+		asserts.Equal(ToList(fromKeys(1, 2, 3)), ToList(SUT))
+		asserts.Equal(basics.Int(3), Size(SUT))
+	})
+	t.Run("Remove", func(t *testing.T) {
+		b := NewBuilder[basics.Int, basics.Int]()
+		b.Insert(1, 10)
+		b.Insert(2, 20)
+		b.Remove(1)
+		b.Remove(5)
+
+		asserts.Equal([]basics.Int{2}, list.ToSlice(Keys(b.Freeze())))
+	})
+	t.Run("Get and Size", func(t *testing.T) {
+		b := NewBuilder[basics.Int, basics.Int]()
+		b.Insert(1, 10)
+
+		asserts.Equal(maybe.Just[basics.Int]{Value: 10}, b.Get(1))
+		asserts.Equal(maybe.Nothing{}, b.Get(2))
+		asserts.Equal(basics.Int(1), b.Size())
+	})
+	t.Run("Freeze with no pairs", func(t *testing.T) {
+		asserts.Equal(Empty[basics.Int, basics.Int](), NewBuilder[basics.Int, basics.Int]().Freeze())
+	})
+	t.Run("Changes nodes it owns in place", func(t *testing.T) {
+		b := NewBuilder[basics.Int, basics.Int]()
+		b.Insert(1, 10)
+		root := b.root
+		b.Insert(1, 11)
+
+		asserts.True(root == b.root)
+		asserts.Equal(basics.Int(11), b.root.value)
+	})
+	t.Run("Frozen dictionaries never change", func(t *testing.T) {
+		b := NewBuilder[basics.Int, basics.Int]()
+		for i := basics.Int(0); i < 200; i++ {
+			b.Insert(i, i)
+		}
+		frozen := b.Freeze()
+		before := ToList(frozen)
+		for i := basics.Int(0); i < 200; i += 3 {
+			b.Remove(i)
+		}
+		for i := basics.Int(0); i < 300; i += 2 {
+			b.Insert(i, -i)
+		}
+		after := b.Freeze()
+
+		asserts.Equal(before, ToList(frozen))
+		asserts.True(isValidTree(frozen))
+		asserts.True(isValidTree(after))
+		asserts.Equal(basics.Int(200), Size(frozen))
+		asserts.Equal(maybe.Just[basics.Int]{Value: -4}, Get(4, after))
+		asserts.Equal(maybe.Nothing{}, Get(3, after))
+	})
+	t.Run("NewBuilderFrom leaves the dictionary untouched", func(t *testing.T) {
+		d := fromKeys(list.ToSlice(list.Range(1, 100))...)
+		before := ToList(d)
+		b := NewBuilderFrom(d)
+		for i := basics.Int(1); i <= 100; i += 2 {
+			b.Remove(i)
+		}
+		b.Insert(500, 5)
+
+		asserts.Equal(before, ToList(d))
+		asserts.True(isValidTree(d))
+		asserts.Equal(basics.Int(51), Size(b.Freeze()))
+	})
+	t.Run("Matches Insert and Remove", func(t *testing.T) {
+		b := NewBuilder[basics.Int, basics.Int]()
+		d := Empty[basics.Int, basics.Int]()
+		x := basics.Int(7)
+		for i := 0; i < 2000; i++ {
+			x = basics.ModBy(10007, x*7919+13)
+			k := basics.ModBy(97, x)
+			if basics.ModBy(3, x) == 0 {
+				b.Remove(k)
+				d = Remove(k, d)
+			} else {
+				b.Insert(k, x)
+				d = Insert(k, x, d)
+			}
+		}
+		SUT := b.Freeze()
+
+		asserts.Equal(ToList(d), ToList(SUT))
+		asserts.True(isValidTree(SUT))
+	})
+}
+
+func BenchmarkBuilder(b *testing.B) {
+	b.Run("builder", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			builder := NewBuilder[basics.Int, basics.Int]()
+			for k := basics.Int(0); k < 100_000; k++ {
+				builder.Insert(k, k)
+			}
+			builder.Freeze()
+		}
+	})
+	b.Run("insert", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			d := Empty[basics.Int, basics.Int]()
+			for k := basics.Int(0); k < 100_000; k++ {
+				d = Insert(k, k, d)
+			}
+		}
+	})
+}
+
+func TestIterators(t *testing.T) {
+	asserts := assert.New(t)
+	d := fromKeys(list.ToSlice(list.Range(1, 10))...)
+
+	t.Run("All", func(t *testing.T) {
+		var SUT []tuple.Tuple2[basics.Int, basics.Int]
+		for k, v := range All(d) {
+			SUT = append(SUT, tuple.Pair(k, v))
+		}
+
+		asserts.Equal(list.ToSlice(ToList(d)), SUT)
+	})
+	t.Run("All stops early", func(t *testing.T) {
+		var SUT []basics.Int
+		for k := range All(d) {
+			if k > 3 {
+				break
+			}
+			SUT = append(SUT, k)
+		}
+
+		asserts.Equal([]basics.Int{1, 2, 3}, SUT)
+	})
+	t.Run("All on an empty dictionary", func(t *testing.T) {
+		for range All(Empty[basics.Int, basics.Int]()) {
+			t.Fatal("expected no pairs")
+		}
+	})
+	t.Run("KeysSeq and ValuesSeq", func(t *testing.T) {
+		asserts.Equal(Keys(d), list.Collect(KeysSeq(d)))
+		asserts.Equal(Values(d), list.Collect(ValuesSeq(d)))
+	})
+	t.Run("RangeSeq", func(t *testing.T) {
+		keys := func(lo Bound[basics.Int], hi Bound[basics.Int]) []basics.Int {
+			var ks []basics.Int
+			for k := range RangeSeq(lo, hi, d) {
+				ks = append(ks, k)
+			}
+			return ks
+		}
+
+		asserts.Equal([]basics.Int{3, 4, 5}, keys(Inclusive[basics.Int]{Key: 3}, Exclusive[basics.Int]{Key: 6}))
+		asserts.Equal([]basics.Int{4, 5, 6}, keys(Exclusive[basics.Int]{Key: 3}, Inclusive[basics.Int]{Key: 6}))
+		asserts.Equal([]basics.Int{9, 10}, keys(Inclusive[basics.Int]{Key: 9}, Unbounded{}))
+		asserts.Equal([]basics.Int{1, 2}, keys(Unbounded{}, Exclusive[basics.Int]{Key: 3}))
+		asserts.Nil(keys(Inclusive[basics.Int]{Key: 20}, Unbounded{}))
+	})
+	t.Run("RangeSeq matches Range", func(t *testing.T) {
+		large := fromKeys(list.ToSlice(list.Range(0, 500))...)
+		for lo := basics.Int(-1); lo < 502; lo += 37 {
+			for hi := lo; hi < 502; hi += 53 {
+				lower, upper := Inclusive[basics.Int]{Key: lo}, Exclusive[basics.Int]{Key: hi}
+
+				asserts.Equal(ToList(Range(lower, upper, large)), list.Collect(func(yield func(tuple.Tuple2[basics.Int, basics.Int]) bool) {
+					for k, v := range RangeSeq(lower, upper, large) {
+						if !yield(tuple.Pair(k, v)) {
+							return
+						}
+					}
+				}))
+			}
+		}
+	})
+	t.Run("Collect", func(t *testing.T) {
+		SUT := Collect(All(d))
+
+		asserts.Equal(ToList(d), ToList(SUT))
+		asserts.True(isValidTree(SUT))
+	})
+	t.Run("Collect keeps the last value of a key", func(t *testing.T) {
+		SUT := Collect(func(yield func(basics.Int, string) bool) {
+			_ = yield(1, "a") && yield(2, "b") && yield(1, "c")
+		})
+
+		asserts.Equal(maybe.Just[string]{Value: "c"}, Get(1, SUT))
+		asserts.Equal(basics.Int(2), Size(SUT))
+	})
+}
+
+func BenchmarkIterate(b *testing.B) {
+	d := fromKeys(list.ToSlice(list.Range(1, 10_000))...)
+
+	b.Run("all", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sum := basics.Int(0)
+			for _, v := range All(d) {
+				sum += v
+			}
+		}
+	})
+	b.Run("values list", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sum := basics.Int(0)
+			for _, v := range list.ToSlice(Values(d)) {
+				sum += v
+			}
+		}
+	})
+}
+
+func TestEquality(t *testing.T) {
+	asserts := assert.New(t)
+	inserted := fromKeys(list.ToSlice(list.Range(1, 20))...)
+	sorted := FromSortedSlice(list.ToSlice(list.Map(func(k basics.Int) tuple.Tuple2[basics.Int, basics.Int] {
+		return tuple.Pair(k, k*10)
+	}, list.Range(1, 20))))
+
+	t.Run("Same pairs with different tree shapes", func(t *testing.T) {
+		asserts.False(reflect.DeepEqual(inserted, sorted))
+		asserts.True(basics.Eq(inserted, sorted))
+		asserts.Equal(basics.Hash(inserted), basics.Hash(sorted))
+	})
+	t.Run("Different values", func(t *testing.T) {
+		asserts.False(basics.Eq(inserted, Insert(5, 0, sorted)))
+	})
+	t.Run("Different keys", func(t *testing.T) {
+		asserts.False(basics.Eq(inserted, Remove(5, sorted)))
+		asserts.False(basics.Eq(Insert(0, 0, Remove(20, inserted)), sorted))
+	})
+	t.Run("Empty dictionaries", func(t *testing.T) {
+		asserts.True(basics.Eq(Empty[basics.Int, basics.Int](), Remove(1, Singleton[basics.Int, basics.Int](1, 1))))
+		asserts.Equal(basics.Hash(Empty[basics.Int, basics.Int]()), basics.Hash(Remove(1, Singleton[basics.Int, basics.Int](1, 1))))
+	})
+	t.Run("Values are compared with Eq", func(t *testing.T) {
+		d1 := Singleton[basics.Int, Dict[basics.Int, basics.Int]](1, inserted)
+		d2 := Singleton[basics.Int, Dict[basics.Int, basics.Int]](1, sorted)
+
+		asserts.True(basics.Eq(d1, d2))
+		asserts.Equal(basics.Hash(d1), basics.Hash(d2))
+	})
+}
@@ -0,0 +1,200 @@
+package dict
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/internal"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"reflect"
+	"strconv"
+)
+
+// A dictionary is encoded as a JSON object when its keys can be object keys, following the
+// rules encoding/json uses for map keys: string types, integer types and encoding.TextMarshaler.
+// Other dictionaries are encoded as an array of [key, value] pairs, sorted by key.
+//
+//	{"a": 1, "b": 2}
+//	[[1.5, "a"], [2.5, "b"]]
+func (d *dict[K, V]) MarshalJSON() ([]byte, error) {
+	if !hasTextKeys[K]() {
+		return json.Marshal(list.ToSlice(ToList[K, V](d)))
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for k, v := range All[K, V](d) {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, err := marshalKey(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Decode a dictionary from a JSON object or an array of [key, value] pairs, whichever MarshalJSON makes for K.
+// When a key appears more than once, the last value is kept.
+func FromJSON[K basics.Comparable[K], V any](data []byte) (Dict[K, V], error) {
+	if !hasTextKeys[K]() {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		xs := make([]tuple.Tuple2[K, V], len(raw))
+		for i, r := range raw {
+			x, err := tuple.PairFromJSON[K, V](r)
+			if err != nil {
+				return nil, err
+			}
+			xs[i] = x
+		}
+		return FromList(list.FromSlice(xs)), nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	b := NewBuilder[K, V]()
+	for key, value := range raw {
+		k, err := unmarshalKey[K](key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := internal.UnmarshalJSON[V](value)
+		if err != nil {
+			return nil, err
+		}
+		b.Insert(k, v)
+	}
+	return b.Freeze(), nil
+}
+
+// Register sets up Dict[K, V] to be decoded inside other values and sent through encoding/gob.
+func Register[K basics.Comparable[K], V any]() {
+	gob.Register(&dict[K, V]{})
+	internal.RegisterJSON(FromJSON[K, V])
+}
+
+// A Field holds a Dict as a struct field that encoding/json can decode.
+// A Field with no Dict is encoded as an empty dictionary.
+type Field[K basics.Comparable[K], V any] struct {
+	Dict Dict[K, V]
+}
+
+func (f Field[K, V]) MarshalJSON() ([]byte, error) {
+	if f.Dict == nil {
+		return json.Marshal(Empty[K, V]())
+	}
+	return json.Marshal(f.Dict)
+}
+
+func (f *Field[K, V]) UnmarshalJSON(data []byte) error {
+	d, err := FromJSON[K, V](data)
+	if err != nil {
+		return err
+	}
+	f.Dict = d
+	return nil
+}
+
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+func hasTextKeys[K any]() bool {
+	t := reflect.TypeFor[K]()
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return t.Implements(textMarshalerType)
+	}
+}
+
+func marshalKey[K any](k K) ([]byte, error) {
+	v := reflect.ValueOf(k)
+	// Like encoding/json, a TextMarshaler is preferred over an integer kind, so a Char key is its character
+	if v.Kind() == reflect.String {
+		return json.Marshal(v.String())
+	}
+	if m, ok := any(k).(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(text))
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return json.Marshal(strconv.FormatInt(v.Int(), 10))
+	default:
+		return json.Marshal(strconv.FormatUint(v.Uint(), 10))
+	}
+}
+
+func unmarshalKey[K any](s string) (K, error) {
+	var k K
+	if u, ok := any(&k).(encoding.TextUnmarshaler); ok {
+		return k, u.UnmarshalText([]byte(s))
+	}
+	v := reflect.ValueOf(&k).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return k, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		v.SetInt(n)
+		return k, err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		v.SetUint(n)
+		return k, err
+	}
+	return k, fmt.Errorf("dict: cannot decode the object key %q into %v", s, v.Type())
+}
+
+// The keys and values are sent as slices, sorted by key.
+type gobDict[K, V any] struct {
+	Keys   []K
+	Values []V
+}
+
+func (d *dict[K, V]) GobEncode() ([]byte, error) {
+	g := gobDict[K, V]{}
+	for k, v := range All[K, V](d) {
+		g.Keys = append(g.Keys, k)
+		g.Values = append(g.Values, v)
+	}
+	return internal.GobEncode(g)
+}
+
+func (d *dict[K, V]) GobDecode(data []byte) error {
+	var g gobDict[K, V]
+	if err := internal.GobDecode(data, &g); err != nil {
+		return err
+	}
+	if len(g.Keys) != len(g.Values) {
+		return fmt.Errorf("dict: decoded %d keys but %d values", len(g.Keys), len(g.Values))
+	}
+	xs := make([]tuple.Tuple2[K, V], len(g.Keys))
+	for i := range xs {
+		xs[i] = tuple.Pair(g.Keys[i], g.Values[i])
+	}
+	d.root = FromList(list.FromSlice(xs)).rbt().root
+	return nil
+}
@@ -0,0 +1,200 @@
+package dict
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/char"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	s "github.com/Confidenceman02/scion-tools/pkg/string"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// A key type that is written as text, like "1.2".
+type version struct {
+	major, minor int
+}
+
+func (v version) Cmp(y basics.Comparable[version]) int {
+	if c := cmp.Compare(v.major, y.T().major); c != 0 {
+		return c
+	}
+	return cmp.Compare(v.minor, y.T().minor)
+}
+
+func (v version) T() version {
+	return v
+}
+
+func (v version) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%d", v.major, v.minor)), nil
+}
+
+func (v *version) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d.%d", &v.major, &v.minor)
+	return err
+}
+
+type payload struct {
+	Scores Dict[s.String, basics.Int]
+}
+
+func TestJSON(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Marshal String keys as an object", func(t *testing.T) {
+		d := Insert("b", 2, Insert[s.String, basics.Int]("a", 1, Empty[s.String, basics.Int]()))
+
+		SUT, err := json.Marshal(payload{Scores: d})
+
+		asserts.NoError(err)
+		asserts.Equal(`{"Scores":{"a":1,"b":2}}`, string(SUT))
+	})
+	t.Run("Marshal Int keys as an object sorted by key", func(t *testing.T) {
+		SUT, err := json.Marshal(fromKeys(10, 2, -1))
+
+		asserts.NoError(err)
+		asserts.Equal(`{"-1":-10,"2":20,"10":100}`, string(SUT))
+	})
+	t.Run("Marshal TextMarshaler keys as an object", func(t *testing.T) {
+		SUT, err := json.Marshal(Singleton(version{1, 2}, "stable"))
+
+		asserts.NoError(err)
+		asserts.Equal(`{"1.2":"stable"}`, string(SUT))
+	})
+	t.Run("Round trip Char keys as characters", func(t *testing.T) {
+		d := Insert('b', 2, Singleton[char.Char, basics.Int]('a', 1))
+
+		data, err := json.Marshal(d)
+		asserts.NoError(err)
+		asserts.Equal(`{"a":1,"b":2}`, string(data))
+
+		SUT, err := FromJSON[char.Char, basics.Int](data)
+		asserts.NoError(err)
+		asserts.Equal(ToList(d), ToList(SUT))
+	})
+	t.Run("Marshal other keys as pairs", func(t *testing.T) {
+		d := Insert(2.5, "b", Singleton[basics.Float, string](1.5, "a"))
+
+		SUT, err := json.Marshal(d)
+
+		asserts.NoError(err)
+		asserts.Equal(`[[1.5,"a"],[2.5,"b"]]`, string(SUT))
+	})
+	t.Run("Marshal an empty dictionary", func(t *testing.T) {
+		SUT1, _ := json.Marshal(Empty[basics.Int, basics.Int]())
+		SUT2, _ := json.Marshal(Empty[basics.Float, basics.Int]())
+
+		asserts.Equal(`{}`, string(SUT1))
+		asserts.Equal(`[]`, string(SUT2))
+	})
+	t.Run("FromJSON", func(t *testing.T) {
+		SUT1, err1 := FromJSON[basics.Int, basics.Int]([]byte(`{"2": 20, "1": 10}`))
+		SUT2, err2 := FromJSON[basics.Float, string]([]byte(`[[2.5, "b"], [1.5, "a"], [2.5, "c"]]`))
+		SUT3, err3 := FromJSON[version, string]([]byte(`{"1.2": "stable"}`))
+
+		asserts.NoError(err1)
+		asserts.NoError(err2)
+		asserts.NoError(err3)
+		asserts.Equal(ToList(fromKeys(1, 2)), ToList(SUT1))
+		asserts.Equal(ToList(Insert(2.5, "c", Singleton[basics.Float, string](1.5, "a"))), ToList(SUT2))
+		asserts.Equal(maybe.Just[string]{Value: "stable"}, Get(version{1, 2}, SUT3))
+	})
+	t.Run("FromJSON with invalid keys", func(t *testing.T) {
+		_, err1 := FromJSON[basics.Int, basics.Int]([]byte(`{"one": 1}`))
+		_, err2 := FromJSON[char.Char, basics.Int]([]byte(`{"99999999999": 1}`))
+		_, err3 := FromJSON[basics.Float, basics.Int]([]byte(`[[1.5]]`))
+
+		asserts.Error(err1)
+		asserts.Error(err2)
+		asserts.Error(err3)
+	})
+	t.Run("Round trip nested collections", func(t *testing.T) {
+		list.Register[basics.Int]()
+		maybe.Register[basics.Int]()
+		tuple.RegisterPair[basics.Int, basics.Int]()
+		d1 := Insert("b", list.Empty[basics.Int](), Singleton[s.String, list.List[basics.Int]]("a", list.Range(1, 3)))
+		d2 := FromList(list.FromSlice([]tuple.Tuple2[tuple.Tuple2[basics.Int, basics.Int], maybe.Maybe[basics.Int]]{
+			tuple.Pair[tuple.Tuple2[basics.Int, basics.Int], maybe.Maybe[basics.Int]](tuple.Pair[basics.Int, basics.Int](1, 2), maybe.Just[basics.Int]{Value: 3}),
+			tuple.Pair[tuple.Tuple2[basics.Int, basics.Int], maybe.Maybe[basics.Int]](tuple.Pair[basics.Int, basics.Int](2, 1), maybe.Nothing{}),
+		}))
+
+		data1, err := json.Marshal(d1)
+		asserts.NoError(err)
+		data2, err := json.Marshal(d2)
+		asserts.NoError(err)
+		SUT1, err := FromJSON[s.String, list.List[basics.Int]](data1)
+		asserts.NoError(err)
+		SUT2, err := FromJSON[tuple.Tuple2[basics.Int, basics.Int], maybe.Maybe[basics.Int]](data2)
+		asserts.NoError(err)
+
+		asserts.Equal(`{"a":[1,2,3],"b":[]}`, string(data1))
+		asserts.Equal(`[[[1,2],3],[[2,1],null]]`, string(data2))
+		asserts.Equal(ToList(d1), ToList(SUT1))
+		asserts.Equal(ToList(d2), ToList(SUT2))
+	})
+	t.Run("Round trip a large dictionary", func(t *testing.T) {
+		d := fromKeys(list.ToSlice(list.Range(1, 1000))...)
+		data, err := json.Marshal(d)
+		asserts.NoError(err)
+
+		SUT, err := FromJSON[basics.Int, basics.Int](data)
+
+		asserts.NoError(err)
+		asserts.Equal(ToList(d), ToList(SUT))
+		asserts.True(isValidTree(SUT))
+	})
+	t.Run("Round trip a struct with a Field", func(t *testing.T) {
+		type game struct {
+			Scores Field[s.String, basics.Int]
+		}
+		data, err := json.Marshal(game{Scores: Field[s.String, basics.Int]{Dict: Insert("b", 2, Singleton[s.String, basics.Int]("a", 1))}})
+		asserts.NoError(err)
+		asserts.Equal(`{"Scores":{"a":1,"b":2}}`, string(data))
+
+		var SUT game
+		asserts.NoError(json.Unmarshal(data, &SUT))
+
+		asserts.Equal(maybe.Just[basics.Int]{Value: 2}, Get("b", SUT.Scores.Dict))
+		asserts.Equal(basics.Int(2), Size(SUT.Scores.Dict))
+	})
+	t.Run("Marshal a Field with no Dict", func(t *testing.T) {
+		SUT, err := json.Marshal(Field[s.String, basics.Int]{})
+
+		asserts.NoError(err)
+		asserts.Equal(`{}`, string(SUT))
+	})
+}
+
+func TestGob(t *testing.T) {
+	asserts := assert.New(t)
+	Register[s.String, basics.Int]()
+
+	roundTrip := func(p payload) payload {
+		var buf bytes.Buffer
+		asserts.NoError(gob.NewEncoder(&buf).Encode(p))
+		var decoded payload
+		asserts.NoError(gob.NewDecoder(&buf).Decode(&decoded))
+		return decoded
+	}
+
+	t.Run("Round trip", func(t *testing.T) {
+		d := Insert("b", 2, Insert[s.String, basics.Int]("a", 1, Empty[s.String, basics.Int]()))
+
+		SUT := roundTrip(payload{Scores: d})
+
+		asserts.Equal(ToList(d), ToList(SUT.Scores))
+		asserts.True(isValidTree(SUT.Scores))
+	})
+	t.Run("Round trip an empty dictionary", func(t *testing.T) {
+		SUT := roundTrip(payload{Scores: Empty[s.String, basics.Int]()})
+
+		asserts.True(IsEmpty(SUT.Scores))
+	})
+}
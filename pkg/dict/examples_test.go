@@ -2,33 +2,35 @@ package dict
 
 import (
 	"fmt"
-	. "github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
 )
 
 func ExampleEmpty() {
-	Empty[Int, int]()
+	Empty[basics.Int, int]()
 }
 
 func ExampleSingleton() {
-	var key Int = 1
+	var key basics.Int = 1
 	Singleton(key, 24)
 }
 
 func ExampleInsert() {
-	var key Int = 1
-	Insert(key, 24, Empty[Int, Int]())
+	var key basics.Int = 1
+	Insert(key, 24, Empty[basics.Int, basics.Int]())
 }
 
 func ExampleRemove() {
-	some_dict := Singleton(Int(1), 24)
+	some_dict := Singleton(basics.Int(1), 24)
 
-	var key Int = 1
+	var key basics.Int = 1
 	Remove(key, some_dict)
 }
 
 func ExampleIsEmpty() {
-	var key Int = 1
-	fmt.Println(IsEmpty(Empty[Int, int]()))
+	var key basics.Int = 1
+	fmt.Println(IsEmpty(Empty[basics.Int, int]()))
 	fmt.Println(IsEmpty(Singleton(key, 24)))
 
 	// Output:
@@ -37,7 +39,7 @@ func ExampleIsEmpty() {
 }
 
 func ExampleMember() {
-	var key Int = 1
+	var key basics.Int = 1
 	fmt.Println(Member(key, Singleton(key, 24)))
 
 	// Output:
@@ -45,6 +47,27 @@ func ExampleMember() {
 }
 
 func ExampleGet() {
-	var key Int = 1
+	var key basics.Int = 1
 	Get(key, Singleton(key, 24)) // -> Just 24
 }
+
+func ExampleFloor() {
+	d := FromList(list.FromSlice([]tuple.Tuple2[basics.Int, string]{tuple.Pair(basics.Int(10), "a"), tuple.Pair(basics.Int(20), "b")}))
+
+	Floor(15, d) // Just (10, "a")
+}
+
+func ExampleSplit() {
+	d := FromList(list.FromSlice([]tuple.Tuple2[basics.Int, string]{tuple.Pair(basics.Int(10), "a"), tuple.Pair(basics.Int(20), "b"), tuple.Pair(basics.Int(30), "c")}))
+
+	Split(20, d) // ({10: "a"}, Just "b", {30: "c"})
+}
+
+func ExampleRange() {
+	d := FromList(list.FromSlice([]tuple.Tuple2[basics.Int, string]{tuple.Pair(basics.Int(10), "a"), tuple.Pair(basics.Int(20), "b"), tuple.Pair(basics.Int(30), "c")}))
+
+	fmt.Println(list.ToSlice(Keys(Range[basics.Int, string](Exclusive[basics.Int]{Key: 10}, Unbounded{}, d))))
+
+	// Output:
+	// [20 30]
+}
@@ -0,0 +1,185 @@
+package dict
+
+import (
+	"cmp"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"iter"
+	"slices"
+)
+
+// Ordered is a dictionary with keys of a native Go ordered type, like int, string or time.Duration,
+// so keys don't have to be wrapped in basics.Int or string.String.
+//
+// It holds a Dict keyed by [basics.Native], and converting between the two with [OrderedFrom]
+// and Dict copies nothing, so every function of this package can still be used through Dict.
+// The zero value is an empty dictionary.
+type Ordered[K cmp.Ordered, V any] struct {
+	d Dict[basics.Native[K], V]
+}
+
+// Create an empty ordered dictionary.
+func EmptyOrdered[K cmp.Ordered, V any]() Ordered[K, V] {
+	return Ordered[K, V]{d: Empty[basics.Native[K], V]()}
+}
+
+// Use a dictionary keyed by basics.Native as an ordered dictionary. This takes O(1) time.
+func OrderedFrom[K cmp.Ordered, V any](d Dict[basics.Native[K], V]) Ordered[K, V] {
+	return Ordered[K, V]{d: d}
+}
+
+// Convert a Go map into an ordered dictionary. This takes O(n log n) time to sort the keys.
+func OrderedFromMap[K cmp.Ordered, V any](m map[K]V) Ordered[K, V] {
+	// Values are taken while ranging, because looking up a NaN key in a map never finds it
+	pairs := make([]tuple.Tuple2[basics.Native[K], V], 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, tuple.Pair(basics.Wrap(k), v))
+	}
+	slices.SortFunc(pairs, func(a, b tuple.Tuple2[basics.Native[K], V]) int {
+		return cmp.Compare(tuple.First(a).Value, tuple.First(b).Value)
+	})
+	// A map can hold many NaN keys, which are all one key here
+	pairs = slices.CompactFunc(pairs, func(a, b tuple.Tuple2[basics.Native[K], V]) bool {
+		return cmp.Compare(tuple.First(a).Value, tuple.First(b).Value) == 0
+	})
+	return OrderedFrom(FromSortedSlice(pairs))
+}
+
+// Get the dictionary keyed by basics.Native that the ordered dictionary holds. This takes O(1) time.
+func (o Ordered[K, V]) Dict() Dict[basics.Native[K], V] {
+	if o.d == nil {
+		return Empty[basics.Native[K], V]()
+	}
+	return o.d
+}
+
+// Insert a key-value pair. Replaces the value when there is a collision.
+func (o Ordered[K, V]) Insert(key K, v V) Ordered[K, V] {
+	return OrderedFrom(Insert(basics.Wrap(key), v, o.Dict()))
+}
+
+// Update the value for a specific key with a given function.
+func (o Ordered[K, V]) Update(key K, f func(maybe.Maybe[V]) maybe.Maybe[V]) Ordered[K, V] {
+	return OrderedFrom(Update(basics.Wrap(key), f, o.Dict()))
+}
+
+// Remove a key-value pair. If the key is not found, no changes are made.
+func (o Ordered[K, V]) Remove(key K) Ordered[K, V] {
+	return OrderedFrom(Remove(basics.Wrap(key), o.Dict()))
+}
+
+// Determine if the dictionary is empty.
+func (o Ordered[K, V]) IsEmpty() bool {
+	return IsEmpty(o.Dict())
+}
+
+// Determine if a key is in the dictionary.
+func (o Ordered[K, V]) Member(key K) bool {
+	return Member(basics.Wrap(key), o.Dict())
+}
+
+// Get the value associated with a key. If the key is not found, return Nothing.
+func (o Ordered[K, V]) Get(key K) maybe.Maybe[V] {
+	return Get(basics.Wrap(key), o.Dict())
+}
+
+// Determine the number of key-value pairs in the dictionary. This takes O(1) time.
+func (o Ordered[K, V]) Size() basics.Int {
+	return Size(o.Dict())
+}
+
+// Get all of the keys, sorted from lowest to highest.
+func (o Ordered[K, V]) Keys() list.List[K] {
+	return list.Map(unwrap[K], Keys(o.Dict()))
+}
+
+// Get all of the values, sorted by their keys.
+func (o Ordered[K, V]) Values() list.List[V] {
+	return Values(o.Dict())
+}
+
+// Convert the dictionary into an association list of key-value pairs, sorted by keys.
+func (o Ordered[K, V]) ToList() list.List[tuple.Tuple2[K, V]] {
+	return Foldr(
+		func(k basics.Native[K], v V, xs list.List[tuple.Tuple2[K, V]]) list.List[tuple.Tuple2[K, V]] {
+			return list.Cons(tuple.Pair(k.Value, v), xs)
+		},
+		list.Empty[tuple.Tuple2[K, V]](),
+		o.Dict(),
+	)
+}
+
+// Get a sequence of the key-value pairs, sorted by keys.
+func (o Ordered[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range All(o.Dict()) {
+			if !yield(k.Value, v) {
+				return
+			}
+		}
+	}
+}
+
+// Keep only the key-value pairs that pass the given test.
+func (o Ordered[K, V]) Filter(isGood func(K, V) bool) Ordered[K, V] {
+	return OrderedFrom(Filter(func(k basics.Native[K], v V) bool { return isGood(k.Value, v) }, o.Dict()))
+}
+
+// Combine two dictionaries. If there is a collision, preference is given to this dictionary.
+func (o Ordered[K, V]) Union(other Ordered[K, V]) Ordered[K, V] {
+	return OrderedFrom(Union(o.Dict(), other.Dict()))
+}
+
+// Keep a key-value pair when its key appears in the other dictionary.
+// Preference is given to values in this dictionary.
+func (o Ordered[K, V]) Intersect(other Ordered[K, V]) Ordered[K, V] {
+	return OrderedFrom(Intersect(o.Dict(), other.Dict()))
+}
+
+// Keep a key-value pair when its key does not appear in the other dictionary.
+func (o Ordered[K, V]) Diff(other Ordered[K, V]) Ordered[K, V] {
+	return OrderedFrom(Diff(o.Dict(), other.Dict()))
+}
+
+// Ordered dictionaries are equal when the dictionaries they hold are.
+func (o Ordered[K, V]) Equal(y Ordered[K, V]) bool {
+	return o.Dict().rbt().Equal(y.Dict())
+}
+
+func (o Ordered[K, V]) Hash() uint64 {
+	return o.Dict().rbt().Hash()
+}
+
+// Ordered dictionaries are written to JSON as an object, like a Go map.
+func (o Ordered[K, V]) MarshalJSON() ([]byte, error) {
+	return o.Dict().rbt().MarshalJSON()
+}
+
+// Ordered is a struct, so unlike Dict it can be decoded with json.Unmarshal.
+func (o *Ordered[K, V]) UnmarshalJSON(data []byte) error {
+	d, err := FromJSON[basics.Native[K], V](data)
+	if err != nil {
+		return err
+	}
+	o.d = d
+	return nil
+}
+
+func (o Ordered[K, V]) GobEncode() ([]byte, error) {
+	return o.Dict().rbt().GobEncode()
+}
+
+func (o *Ordered[K, V]) GobDecode(data []byte) error {
+	d := &dict[basics.Native[K], V]{}
+	if err := d.GobDecode(data); err != nil {
+		return err
+	}
+	o.d = d
+	return nil
+}
+
+func unwrap[K cmp.Ordered](k basics.Native[K]) K {
+	return k.Value
+}
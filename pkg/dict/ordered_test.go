@@ -0,0 +1,133 @@
+package dict
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestOrdered(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Insert and Get with native keys", func(t *testing.T) {
+		SUT := EmptyOrdered[int, string]().Insert(2, "b").Insert(1, "a")
+
+		asserts.Equal(maybe.Just[string]{Value: "a"}, SUT.Get(1))
+		asserts.Equal(maybe.Nothing{}, SUT.Get(3))
+		asserts.Equal(basics.Int(2), SUT.Size())
+	})
+
+	t.Run("The zero value is empty", func(t *testing.T) {
+		var SUT Ordered[string, int]
+
+		asserts.True(SUT.IsEmpty())
+		asserts.Equal(basics.Int(1), SUT.Insert("a", 1).Size())
+	})
+
+	t.Run("Update and Remove", func(t *testing.T) {
+		d := EmptyOrdered[time.Duration, int]().Insert(time.Second, 1).Insert(time.Minute, 2)
+		SUT := d.Update(time.Second, func(m maybe.Maybe[int]) maybe.Maybe[int] {
+			return maybe.Map(func(v int) int { return v * 10 }, m)
+		}).Remove(time.Minute)
+
+		asserts.Equal(maybe.Just[int]{Value: 10}, SUT.Get(time.Second))
+		asserts.False(SUT.Member(time.Minute))
+		asserts.True(d.Member(time.Minute))
+	})
+
+	t.Run("Keys, Values and ToList are sorted by key", func(t *testing.T) {
+		SUT := OrderedFromMap(map[string]int{"c": 3, "a": 1, "b": 2})
+
+		asserts.Equal([]string{"a", "b", "c"}, list.ToSlice(SUT.Keys()))
+		asserts.Equal([]int{1, 2, 3}, list.ToSlice(SUT.Values()))
+		asserts.Equal(
+			[]tuple.Tuple2[string, int]{tuple.Pair("a", 1), tuple.Pair("b", 2), tuple.Pair("c", 3)},
+			list.ToSlice(SUT.ToList()),
+		)
+	})
+
+	t.Run("OrderedFromMap keeps the value of a NaN key", func(t *testing.T) {
+		SUT := OrderedFromMap(map[float64]string{math.NaN(): "nan", math.NaN(): "nan", 1.5: "a"})
+
+		asserts.Equal(basics.Int(2), SUT.Size())
+		asserts.Equal(maybe.Just[string]{Value: "nan"}, SUT.Get(math.NaN()))
+		asserts.Equal(maybe.Just[string]{Value: "a"}, SUT.Get(1.5))
+	})
+
+	t.Run("All", func(t *testing.T) {
+		var keys []string
+		for k := range OrderedFromMap(map[string]int{"b": 2, "a": 1}).All() {
+			keys = append(keys, k)
+		}
+
+		asserts.Equal([]string{"a", "b"}, keys)
+	})
+
+	t.Run("Filter, Union, Intersect and Diff", func(t *testing.T) {
+		x := OrderedFromMap(map[int]string{1: "x", 2: "x"})
+		y := OrderedFromMap(map[int]string{2: "y", 3: "y"})
+
+		asserts.Equal([]int{2}, list.ToSlice(x.Filter(func(k int, _ string) bool { return k > 1 }).Keys()))
+		asserts.Equal(maybe.Just[string]{Value: "x"}, x.Union(y).Get(2))
+		asserts.Equal([]int{1, 2, 3}, list.ToSlice(x.Union(y).Keys()))
+		asserts.Equal([]int{2}, list.ToSlice(x.Intersect(y).Keys()))
+		asserts.Equal([]int{1}, list.ToSlice(x.Diff(y).Keys()))
+	})
+
+	t.Run("Converting to and from Dict copies nothing", func(t *testing.T) {
+		d := Insert(basics.Wrap(1), "a", Empty[basics.Native[int], string]())
+		SUT := OrderedFrom(d)
+
+		asserts.Same(d, SUT.Dict())
+		asserts.Equal(maybe.Just[string]{Value: "a"}, SUT.Get(1))
+	})
+
+	t.Run("Equality", func(t *testing.T) {
+		x := EmptyOrdered[int, int]().Insert(1, 1).Insert(2, 2).Insert(3, 3)
+		y := EmptyOrdered[int, int]().Insert(3, 3).Insert(2, 2).Insert(1, 1)
+
+		asserts.True(basics.Eq(x, y))
+		asserts.Equal(basics.Hash(x), basics.Hash(y))
+		asserts.False(basics.Eq(x, y.Remove(1)))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		type payload struct {
+			Scores Ordered[string, int]
+		}
+		data, err := json.Marshal(payload{Scores: OrderedFromMap(map[string]int{"b": 2, "a": 1})})
+		asserts.NoError(err)
+		asserts.JSONEq(`{"Scores":{"a":1,"b":2}}`, string(data))
+
+		var SUT payload
+		asserts.NoError(json.Unmarshal(data, &SUT))
+		asserts.Equal(maybe.Just[int]{Value: 2}, SUT.Scores.Get("b"))
+	})
+
+	t.Run("JSON with float keys", func(t *testing.T) {
+		data, err := json.Marshal(OrderedFromMap(map[float64]string{1.5: "a"}))
+		asserts.NoError(err)
+		asserts.JSONEq(`{"1.5":"a"}`, string(data))
+
+		var SUT Ordered[float64, string]
+		asserts.NoError(json.Unmarshal(data, &SUT))
+		asserts.Equal(maybe.Just[string]{Value: "a"}, SUT.Get(1.5))
+	})
+
+	t.Run("Gob", func(t *testing.T) {
+		var buf bytes.Buffer
+		asserts.NoError(gob.NewEncoder(&buf).Encode(OrderedFromMap(map[int]string{1: "a", 2: "b"})))
+
+		var SUT Ordered[int, string]
+		asserts.NoError(gob.NewDecoder(&buf).Decode(&SUT))
+		asserts.Equal([]int{1, 2}, list.ToSlice(SUT.Keys()))
+	})
+}
@@ -0,0 +1,9 @@
+package hashdict
+
+import "reflect"
+
+// Choose the hash of keys of type K, so tests can make keys collide.
+// It has to be called before any key of type K is hashed.
+func setKeyHasher[K comparable](hash func(K) uint64) {
+	keyHashers.Store(reflect.TypeFor[K](), hash)
+}
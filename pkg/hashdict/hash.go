@@ -0,0 +1,164 @@
+package hashdict
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/internal/hashing"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// Keys are hashed the way == compares them: pointers and channels by address, interfaces by their
+// dynamic type and value, and arrays and structs by their elements and fields. basics.Hash follows
+// pointers instead, so a key would be lost when the value it points to changes.
+//
+// The hash function of a key type is worked out with reflection once and cached, so a lookup
+// only calls it. Key types that are plain memory, like ints, pointers or arrays of bytes, are
+// hashed as bytes in one go.
+
+// A hasher hashes the value of a type at a pointer.
+type hasher func(p unsafe.Pointer) uint64
+
+var (
+	keyHashers sync.Map // reflect.Type -> func(K) uint64
+	hashers    sync.Map // reflect.Type -> hasher
+)
+
+// Hash a key the way == compares it.
+func hashKey[K comparable](k K) uint64 {
+	return keyHasher[K]()(k)
+}
+
+// Get the hash function for keys of type K.
+func keyHasher[K comparable]() func(K) uint64 {
+	t := reflect.TypeFor[K]()
+	if h, ok := keyHashers.Load(t); ok {
+		return h.(func(K) uint64)
+	}
+	h, _ := keyHashers.LoadOrStore(t, newKeyHasher[K](t))
+	return h.(func(K) uint64)
+}
+
+func newKeyHasher[K comparable](t reflect.Type) func(K) uint64 {
+	// Reading the key through its address in place keeps it from escaping to the heap
+	if isMemory(t) {
+		size := t.Size()
+		return func(k K) uint64 {
+			return hashing.Bytes(unsafe.Slice((*byte)(unsafe.Pointer(&k)), size))
+		}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return func(k K) uint64 { return hashing.String(*(*string)(unsafe.Pointer(&k))) }
+	case reflect.Float64:
+		return func(k K) uint64 { return hashing.Float64(*(*float64)(unsafe.Pointer(&k))) }
+	case reflect.Float32:
+		return func(k K) uint64 { return hashing.Float64(float64(*(*float32)(unsafe.Pointer(&k)))) }
+	}
+	h := hasherOf(t)
+	return func(k K) uint64 { return h(unsafe.Pointer(&k)) }
+}
+
+func hasherOf(t reflect.Type) hasher {
+	if h, ok := hashers.Load(t); ok {
+		return h.(hasher)
+	}
+	h, _ := hashers.LoadOrStore(t, newHasher(t))
+	return h.(hasher)
+}
+
+func newHasher(t reflect.Type) hasher {
+	if isMemory(t) {
+		size := t.Size()
+		return func(p unsafe.Pointer) uint64 { return hashing.Bytes(unsafe.Slice((*byte)(p), size)) }
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return func(p unsafe.Pointer) uint64 { return hashing.String(*(*string)(p)) }
+	case reflect.Float32:
+		return func(p unsafe.Pointer) uint64 { return hashing.Float64(float64(*(*float32)(p))) }
+	case reflect.Float64:
+		return func(p unsafe.Pointer) uint64 { return hashing.Float64(*(*float64)(p)) }
+	case reflect.Complex64:
+		return func(p unsafe.Pointer) uint64 {
+			c := *(*complex64)(p)
+			return hashing.Combine(hashing.Float64(float64(real(c))), hashing.Float64(float64(imag(c))))
+		}
+	case reflect.Complex128:
+		return func(p unsafe.Pointer) uint64 {
+			c := *(*complex128)(p)
+			return hashing.Combine(hashing.Float64(real(c)), hashing.Float64(imag(c)))
+		}
+	case reflect.Array:
+		elem, size, n := hasherOf(t.Elem()), t.Elem().Size(), t.Len()
+		return func(p unsafe.Pointer) uint64 {
+			h := hashing.Uint64(uint64(n))
+			for i := 0; i < n; i++ {
+				h = hashing.Combine(h, elem(unsafe.Add(p, uintptr(i)*size)))
+			}
+			return h
+		}
+	case reflect.Struct:
+		// == skips blank fields, so they are not hashed either
+		type field struct {
+			offset uintptr
+			hash   hasher
+		}
+		var fields []field
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.Name != "_" {
+				fields = append(fields, field{f.Offset, hasherOf(f.Type)})
+			}
+		}
+		return func(p unsafe.Pointer) uint64 {
+			h := hashing.Uint64(uint64(len(fields)))
+			for _, f := range fields {
+				h = hashing.Combine(h, f.hash(unsafe.Add(p, f.offset)))
+			}
+			return h
+		}
+	case reflect.Interface:
+		return func(p unsafe.Pointer) uint64 {
+			v := reflect.NewAt(t, p).Elem()
+			if v.IsNil() {
+				return 0
+			}
+			dynamic := v.Elem().Type()
+			if !dynamic.Comparable() {
+				panic("hashdict: hash of unhashable type " + dynamic.String())
+			}
+			// The dynamic value is copied so it has an address to hash it at
+			c := reflect.New(dynamic)
+			c.Elem().Set(v.Elem())
+			return hashing.Combine(hashing.String(dynamic.String()), hasherOf(dynamic)(c.UnsafePointer()))
+		}
+	default:
+		panic("hashdict: hash of unhashable type " + t.String())
+	}
+}
+
+// Determine if values of a type are equal exactly when their bytes are, so they can be hashed as bytes.
+// Floats are not, because 0 and -0 are equal, and neither are strings and interfaces, which point to their contents.
+// Structs with padding between their fields are not either, because the padding can hold anything.
+func isMemory(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return true
+	case reflect.Array:
+		return isMemory(t.Elem())
+	case reflect.Struct:
+		var end uintptr
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Name == "_" || f.Offset != end || !isMemory(f.Type) {
+				return false
+			}
+			end = f.Offset + f.Type.Size()
+		}
+		return end == t.Size()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,470 @@
+// Package hashdict implements an immutable dictionary for keys that can be compared with ==, like Go structs,
+// arrays and strings, without needing a Cmp method.
+//
+// It is a hash array mapped trie: each level of the trie uses 5 bits of the key's hash to pick one of 32 slots,
+// so insert, remove, and query operations take O(log32 n) time, which is close to O(1) in practice.
+// Changes copy only the path to the changed key and share the rest of the trie.
+//
+// Keys are hashed the way == compares them, so pointer keys are found by address even when what they point to changes.
+// The pairs are kept in the order of their hashes, which is not sorted and can differ between runs.
+package hashdict
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/internal/hashing"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"iter"
+	"math/bits"
+)
+
+// HashDict represents a dictionary of keys and values, where the keys can be any comparable Go type.
+type HashDict[K comparable, V any] interface {
+	hamt() *hashDict[K, V]
+}
+
+/*
+Retrieve the internal trie
+*/
+func (d *hashDict[K, V]) hamt() *hashDict[K, V] {
+	return d
+}
+
+type hashDict[K comparable, V any] struct {
+	root *node[K, V]
+	size int
+}
+
+const (
+	bitsPerLevel = 5
+	levelMask    = 1<<bitsPerLevel - 1
+)
+
+// A node has room for 32 slots, one for each value of 5 bits of the hash.
+// The bitmap says which of them are used, and only the used ones are stored.
+type node[K comparable, V any] struct {
+	bitmap uint32
+	slots  []slot[K, V]
+}
+
+// A slot holds either a child node, or the entries whose hashes lead to it.
+// Entries only share a slot when their whole hashes are the same.
+type slot[K comparable, V any] struct {
+	child   *node[K, V]
+	hash    uint64
+	entries []entry[K, V]
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// The bit of a node's bitmap that a hash uses at a level of the trie.
+func bitFor(hash uint64, shift uint) uint32 {
+	return 1 << ((hash >> shift) & levelMask)
+}
+
+// The position in a node's slots of the slot for a bit.
+func (n *node[K, V]) index(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// BUILD
+
+// Create an empty dictionary.
+func Empty[K comparable, V any]() HashDict[K, V] {
+	return &hashDict[K, V]{}
+}
+
+// Create a dictionary with one key-value pair.
+func Singleton[K comparable, V any](key K, value V) HashDict[K, V] {
+	return Insert(key, value, Empty[K, V]())
+}
+
+// Insert a key-value pair into a dictionary. Replaces value when there is a collision.
+func Insert[K comparable, V any](key K, value V, d HashDict[K, V]) HashDict[K, V] {
+	h := d.hamt()
+	root, added := insert(h.root, 0, hashKey(key), entry[K, V]{key: key, value: value})
+	if added {
+		return &hashDict[K, V]{root: root, size: h.size + 1}
+	}
+	return &hashDict[K, V]{root: root, size: h.size}
+}
+
+// Update the value of a dictionary for a specific key with a given function.
+func Update[K comparable, V any](targetKey K, f func(maybe.Maybe[V]) maybe.Maybe[V], d HashDict[K, V]) HashDict[K, V] {
+	return maybe.MaybeWith(
+		f(Get(targetKey, d)),
+		func(j maybe.Just[V]) HashDict[K, V] { return Insert(targetKey, j.Value, d) },
+		func(n maybe.Nothing) HashDict[K, V] { return Remove(targetKey, d) },
+	)
+}
+
+// Remove a key-value pair from a dictionary. If the key is not found, no changes are made.
+func Remove[K comparable, V any](key K, d HashDict[K, V]) HashDict[K, V] {
+	h := d.hamt()
+	root, removed := remove(h.root, 0, hashKey(key), key)
+	if !removed {
+		return d
+	}
+	return &hashDict[K, V]{root: root, size: h.size - 1}
+}
+
+func insert[K comparable, V any](n *node[K, V], shift uint, hash uint64, e entry[K, V]) (*node[K, V], bool) {
+	if n == nil {
+		n = &node[K, V]{}
+	}
+	bit := bitFor(hash, shift)
+	i := n.index(bit)
+	if n.bitmap&bit == 0 {
+		slots := make([]slot[K, V], len(n.slots)+1)
+		copy(slots, n.slots[:i])
+		slots[i] = slot[K, V]{hash: hash, entries: []entry[K, V]{e}}
+		copy(slots[i+1:], n.slots[i:])
+		return &node[K, V]{bitmap: n.bitmap | bit, slots: slots}, true
+	}
+	s := n.slots[i]
+	switch {
+	case s.child != nil:
+		child, added := insert(s.child, shift+bitsPerLevel, hash, e)
+		return n.withSlot(i, slot[K, V]{child: child}), added
+	case s.hash == hash:
+		for j, old := range s.entries {
+			if old.key == e.key {
+				entries := append([]entry[K, V]{}, s.entries...)
+				entries[j] = e
+				return n.withSlot(i, slot[K, V]{hash: hash, entries: entries}), false
+			}
+		}
+		entries := append(append([]entry[K, V]{}, s.entries...), e)
+		return n.withSlot(i, slot[K, V]{hash: hash, entries: entries}), true
+	default:
+		// Two different hashes lead to this slot, so they move down to where their bits differ
+		child := split(shift+bitsPerLevel, s, slot[K, V]{hash: hash, entries: []entry[K, V]{e}})
+		return n.withSlot(i, slot[K, V]{child: child}), true
+	}
+}
+
+// Make a node holding two slots with different hashes.
+func split[K comparable, V any](shift uint, a slot[K, V], b slot[K, V]) *node[K, V] {
+	bitA, bitB := bitFor(a.hash, shift), bitFor(b.hash, shift)
+	switch {
+	case bitA == bitB:
+		return &node[K, V]{bitmap: bitA, slots: []slot[K, V]{{child: split(shift+bitsPerLevel, a, b)}}}
+	case bitA < bitB:
+		return &node[K, V]{bitmap: bitA | bitB, slots: []slot[K, V]{a, b}}
+	default:
+		return &node[K, V]{bitmap: bitA | bitB, slots: []slot[K, V]{b, a}}
+	}
+}
+
+func remove[K comparable, V any](n *node[K, V], shift uint, hash uint64, key K) (*node[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	bit := bitFor(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	i := n.index(bit)
+	s := n.slots[i]
+	if s.child != nil {
+		child, removed := remove(s.child, shift+bitsPerLevel, hash, key)
+		if !removed {
+			return n, false
+		}
+		if len(child.slots) == 1 && child.slots[0].child == nil {
+			// A child left with one slot of entries is pulled up, so the trie has
+			// the same shape however it was built
+			return n.withSlot(i, child.slots[0]), true
+		}
+		return n.withSlot(i, slot[K, V]{child: child}), true
+	}
+	if s.hash != hash {
+		return n, false
+	}
+	for j, e := range s.entries {
+		if e.key != key {
+			continue
+		}
+		if len(s.entries) > 1 {
+			entries := append(append([]entry[K, V]{}, s.entries[:j]...), s.entries[j+1:]...)
+			return n.withSlot(i, slot[K, V]{hash: hash, entries: entries}), true
+		}
+		if len(n.slots) == 1 {
+			return nil, true
+		}
+		slots := append(append([]slot[K, V]{}, n.slots[:i]...), n.slots[i+1:]...)
+		return &node[K, V]{bitmap: n.bitmap &^ bit, slots: slots}, true
+	}
+	return n, false
+}
+
+// Copy a node with one of its slots replaced.
+func (n *node[K, V]) withSlot(i int, s slot[K, V]) *node[K, V] {
+	slots := append([]slot[K, V]{}, n.slots...)
+	slots[i] = s
+	return &node[K, V]{bitmap: n.bitmap, slots: slots}
+}
+
+// QUERY
+
+// Determine if a dictionary is empty.
+func IsEmpty[K comparable, V any](d HashDict[K, V]) bool {
+	return d.hamt().size == 0
+}
+
+// Determine if a key is in a dictionary.
+func Member[K comparable, V any](k K, d HashDict[K, V]) bool {
+	_, ok := get(d.hamt().root, hashKey(k), k)
+	return ok
+}
+
+// Get the value associated with a key.
+// If the key is not found, return Nothing.
+func Get[K comparable, V any](targetKey K, d HashDict[K, V]) maybe.Maybe[V] {
+	if v, ok := get(d.hamt().root, hashKey(targetKey), targetKey); ok {
+		return maybe.Just[V]{Value: v}
+	}
+	return maybe.Nothing{}
+}
+
+// Determine the number of key-value pairs in the dictionary. This takes O(1) time.
+func Size[K comparable, V any](d HashDict[K, V]) basics.Int {
+	return basics.Int(d.hamt().size)
+}
+
+func get[K comparable, V any](n *node[K, V], hash uint64, key K) (V, bool) {
+	var zero V
+	for shift := uint(0); n != nil; shift += bitsPerLevel {
+		bit := bitFor(hash, shift)
+		if n.bitmap&bit == 0 {
+			return zero, false
+		}
+		s := &n.slots[n.index(bit)]
+		if s.child != nil {
+			n = s.child
+			continue
+		}
+		if s.hash == hash {
+			for _, e := range s.entries {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+		}
+		return zero, false
+	}
+	return zero, false
+}
+
+// EQUALITY
+
+// Dictionaries are equal when they have the same keys with equal values, compared with basics.Eq.
+func (d *hashDict[K, V]) Equal(y HashDict[K, V]) bool {
+	if d.size != y.hamt().size {
+		return false
+	}
+	for k, v := range All[K, V](d) {
+		other, ok := get(y.hamt().root, hashKey(k), k)
+		if !ok || !basics.Eq(v, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// The hashes of the pairs are added up, so the hash does not depend on the order of the pairs.
+func (d *hashDict[K, V]) Hash() uint64 {
+	h := hashing.Uint64(uint64(d.size))
+	for k, v := range All[K, V](d) {
+		h += hashing.Combine(hashKey(k), basics.Hash(v))
+	}
+	return h
+}
+
+// LISTS
+
+// Get all of the keys in a dictionary, in the order of their hashes.
+func Keys[K comparable, V any](d HashDict[K, V]) list.List[K] {
+	return Foldr(
+		func(key K, _ V, keyList list.List[K]) list.List[K] {
+			return list.Cons(key, keyList)
+		},
+		list.Empty[K](),
+		d,
+	)
+}
+
+// Get all of the values in a dictionary, in the order of their keys' hashes.
+func Values[K comparable, V any](d HashDict[K, V]) list.List[V] {
+	return Foldr(
+		func(_ K, value V, valueList list.List[V]) list.List[V] {
+			return list.Cons(value, valueList)
+		},
+		list.Empty[V](),
+		d,
+	)
+}
+
+// Convert a dictionary into an association list of key-value pairs, in the order of the keys' hashes.
+func ToList[K comparable, V any](d HashDict[K, V]) list.List[tuple.Tuple2[K, V]] {
+	return Foldr(
+		func(key K, value V, xs list.List[tuple.Tuple2[K, V]]) list.List[tuple.Tuple2[K, V]] {
+			return list.Cons(tuple.Pair(key, value), xs)
+		},
+		list.Empty[tuple.Tuple2[K, V]](),
+		d,
+	)
+}
+
+// Convert an association list into a dictionary.
+// If a key appears more than once, the last value is kept.
+func FromList[K comparable, V any](assocs list.List[tuple.Tuple2[K, V]]) HashDict[K, V] {
+	return list.Foldl(
+		func(t tuple.Tuple2[K, V], d HashDict[K, V]) HashDict[K, V] {
+			return Insert(tuple.First(t), tuple.Second(t), d)
+		},
+		Empty[K, V](),
+		assocs,
+	)
+}
+
+// Get a sequence of the key-value pairs in a dictionary, in the order of the keys' hashes.
+func All[K comparable, V any](d HashDict[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		walk(d.hamt().root, yield)
+	}
+}
+
+func walk[K comparable, V any](n *node[K, V], yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	for _, s := range n.slots {
+		if s.child != nil {
+			if !walk(s.child, yield) {
+				return false
+			}
+			continue
+		}
+		for _, e := range s.entries {
+			if !yield(e.key, e.value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// TRANSFORM
+
+// Apply a function to all values in a dictionary.
+// The keys do not change, so the trie keeps its shape and no key is hashed again.
+func Map[K comparable, A, B any](f func(key K, value A) B, d HashDict[K, A]) HashDict[K, B] {
+	return &hashDict[K, B]{root: mapHelp(f, d.hamt().root), size: d.hamt().size}
+}
+
+func mapHelp[K comparable, A, B any](f func(K, A) B, n *node[K, A]) *node[K, B] {
+	if n == nil {
+		return nil
+	}
+	slots := make([]slot[K, B], len(n.slots))
+	for i, s := range n.slots {
+		if s.child != nil {
+			slots[i] = slot[K, B]{child: mapHelp(f, s.child)}
+			continue
+		}
+		entries := make([]entry[K, B], len(s.entries))
+		for j, e := range s.entries {
+			entries[j] = entry[K, B]{key: e.key, value: f(e.key, e.value)}
+		}
+		slots[i] = slot[K, B]{hash: s.hash, entries: entries}
+	}
+	return &node[K, B]{bitmap: n.bitmap, slots: slots}
+}
+
+// Fold over the key-value pairs in a dictionary, in the order of the keys' hashes.
+func Foldl[K comparable, V, B any](f func(K, V, B) B, acc B, d HashDict[K, V]) B {
+	for k, v := range All(d) {
+		acc = f(k, v, acc)
+	}
+	return acc
+}
+
+// Fold over the key-value pairs in a dictionary, in the reverse order of Foldl.
+func Foldr[K comparable, V, B any](f func(K, V, B) B, acc B, d HashDict[K, V]) B {
+	return foldrHelp(f, acc, d.hamt().root)
+}
+
+func foldrHelp[K comparable, V, B any](f func(K, V, B) B, acc B, n *node[K, V]) B {
+	if n == nil {
+		return acc
+	}
+	for i := len(n.slots) - 1; i >= 0; i-- {
+		s := n.slots[i]
+		if s.child != nil {
+			acc = foldrHelp(f, acc, s.child)
+			continue
+		}
+		for j := len(s.entries) - 1; j >= 0; j-- {
+			acc = f(s.entries[j].key, s.entries[j].value, acc)
+		}
+	}
+	return acc
+}
+
+// Keep only the key-value pairs that pass the given test.
+func Filter[K comparable, V any](isGood func(K, V) bool, d HashDict[K, V]) HashDict[K, V] {
+	return Foldl(
+		func(k K, v V, acc HashDict[K, V]) HashDict[K, V] {
+			if isGood(k, v) {
+				return acc
+			}
+			return Remove(k, acc)
+		},
+		d,
+		d,
+	)
+}
+
+// Partition a dictionary according to some test. The first dictionary contains all key-value pairs
+// which passed the test, and the second contains the pairs that did not.
+func Partition[K comparable, V any](isGood func(K, V) bool, d HashDict[K, V]) tuple.Tuple2[HashDict[K, V], HashDict[K, V]] {
+	return tuple.Pair(
+		Filter(isGood, d),
+		Filter(func(k K, v V) bool { return !isGood(k, v) }, d),
+	)
+}
+
+// COMBINE
+
+// Combine two dictionaries. If there is a collision, preference is given to the first dictionary.
+// The pairs of the smaller dictionary are added to the larger one.
+func Union[K comparable, V any](t1 HashDict[K, V], t2 HashDict[K, V]) HashDict[K, V] {
+	if Size(t1) >= Size(t2) {
+		return Foldl(
+			func(k K, v V, acc HashDict[K, V]) HashDict[K, V] {
+				if Member(k, acc) {
+					return acc
+				}
+				return Insert(k, v, acc)
+			},
+			t1,
+			t2,
+		)
+	}
+	return Foldl(Insert[K, V], t2, t1)
+}
+
+// Keep a key-value pair when its key appears in the second dictionary. Preference is given to values in the first dictionary.
+func Intersect[K comparable, V any](t1 HashDict[K, V], t2 HashDict[K, V]) HashDict[K, V] {
+	return Filter(func(k K, _ V) bool { return Member(k, t2) }, t1)
+}
+
+// Keep a key-value pair when its key does not appear in the second dictionary.
+func Diff[K comparable, A, B any](t1 HashDict[K, A], t2 HashDict[K, B]) HashDict[K, A] {
+	return Filter(func(k K, _ A) bool { return !Member(k, t2) }, t1)
+}
@@ -0,0 +1,394 @@
+package hashdict
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+type point struct {
+	x, y int
+}
+
+// A key with a hash that can be chosen, to make keys collide.
+type collider struct {
+	name string
+	hash uint64
+}
+
+func init() {
+	setKeyHasher(func(c collider) uint64 { return c.hash })
+}
+
+func TestBuild(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Empty", func(t *testing.T) {
+		SUT := Empty[point, string]()
+
+		asserts.True(IsEmpty(SUT))
+		asserts.Equal(basics.Int(0), Size(SUT))
+	})
+
+	t.Run("Singleton", func(t *testing.T) {
+		SUT := Singleton(point{1, 2}, "a")
+
+		asserts.Equal(basics.Int(1), Size(SUT))
+		asserts.Equal(maybe.Just[string]{Value: "a"}, Get(point{1, 2}, SUT))
+	})
+
+	t.Run("Insert", func(t *testing.T) {
+		SUT := Insert(point{3, 4}, "b", Singleton(point{1, 2}, "a"))
+
+		asserts.Equal(basics.Int(2), Size(SUT))
+		asserts.Equal(maybe.Just[string]{Value: "b"}, Get(point{3, 4}, SUT))
+	})
+
+	t.Run("Insert replaces", func(t *testing.T) {
+		SUT := Insert(point{1, 2}, "b", Singleton(point{1, 2}, "a"))
+
+		asserts.Equal(basics.Int(1), Size(SUT))
+		asserts.Equal(maybe.Just[string]{Value: "b"}, Get(point{1, 2}, SUT))
+	})
+
+	t.Run("Insert array keys", func(t *testing.T) {
+		SUT := Insert([16]byte{1}, 1, Singleton([16]byte{2}, 2))
+
+		asserts.Equal(maybe.Just[int]{Value: 1}, Get([16]byte{1}, SUT))
+		asserts.Equal(maybe.Just[int]{Value: 2}, Get([16]byte{2}, SUT))
+		asserts.Equal(maybe.Nothing{}, Get([16]byte{3}, SUT))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		SUT := Update(point{1, 2}, func(m maybe.Maybe[int]) maybe.Maybe[int] {
+			return maybe.Map(func(v int) int { return v + 1 }, m)
+		}, Singleton(point{1, 2}, 1))
+
+		asserts.Equal(maybe.Just[int]{Value: 2}, Get(point{1, 2}, SUT))
+	})
+
+	t.Run("Update to Nothing removes", func(t *testing.T) {
+		SUT := Update(point{1, 2}, func(m maybe.Maybe[int]) maybe.Maybe[int] {
+			return maybe.Nothing{}
+		}, Singleton(point{1, 2}, 1))
+
+		asserts.True(IsEmpty(SUT))
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		SUT := Remove(point{1, 2}, Insert(point{3, 4}, "b", Singleton(point{1, 2}, "a")))
+
+		asserts.Equal(basics.Int(1), Size(SUT))
+		asserts.False(Member(point{1, 2}, SUT))
+		asserts.True(Member(point{3, 4}, SUT))
+	})
+
+	t.Run("Remove not found", func(t *testing.T) {
+		d := Singleton(point{1, 2}, "a")
+
+		asserts.Same(d, Remove(point{5, 6}, d))
+	})
+}
+
+func TestCollisions(t *testing.T) {
+	asserts := assert.New(t)
+	a, b, c := collider{"a", 7}, collider{"b", 7}, collider{"c", 7}
+
+	t.Run("Keys with the same hash are kept apart", func(t *testing.T) {
+		SUT := FromList(list.FromSlice([]tuple.Tuple2[collider, int]{
+			tuple.Pair(a, 1), tuple.Pair(b, 2), tuple.Pair(c, 3),
+		}))
+
+		asserts.Equal(basics.Int(3), Size(SUT))
+		asserts.Equal(maybe.Just[int]{Value: 1}, Get(a, SUT))
+		asserts.Equal(maybe.Just[int]{Value: 2}, Get(b, SUT))
+		asserts.Equal(maybe.Just[int]{Value: 3}, Get(c, SUT))
+		asserts.Equal(maybe.Nothing{}, Get(collider{"d", 7}, SUT))
+	})
+
+	t.Run("Remove a colliding key", func(t *testing.T) {
+		SUT := Remove(b, FromList(list.FromSlice([]tuple.Tuple2[collider, int]{
+			tuple.Pair(a, 1), tuple.Pair(b, 2), tuple.Pair(c, 3),
+		})))
+
+		asserts.Equal(basics.Int(2), Size(SUT))
+		asserts.False(Member(b, SUT))
+		asserts.True(Member(a, SUT))
+		asserts.True(Member(c, SUT))
+	})
+
+	t.Run("Hashes sharing their low bits", func(t *testing.T) {
+		// These hashes share their first 30 bits, so the trie goes six levels deep
+		low, high := collider{"low", 1}, collider{"high", 1 | 1<<30}
+		SUT := Insert(high, 2, Singleton(low, 1))
+
+		asserts.Equal(maybe.Just[int]{Value: 1}, Get(low, SUT))
+		asserts.Equal(maybe.Just[int]{Value: 2}, Get(high, SUT))
+		asserts.Equal(Singleton(high, 2), Remove(low, SUT))
+	})
+}
+
+func TestKeyHashes(t *testing.T) {
+	asserts := assert.New(t)
+	type box struct{ n int }
+
+	t.Run("Pointer keys are found by address when what they point to changes", func(t *testing.T) {
+		p := &box{1}
+		SUT := Insert(p, "a", Empty[*box, string]())
+		p.n = 2
+
+		asserts.True(Member(p, SUT))
+		asserts.False(Member(&box{2}, SUT))
+	})
+
+	t.Run("Interface keys holding pointers", func(t *testing.T) {
+		p := &box{1}
+		SUT := Insert[any](p, "a", Empty[any, string]())
+		p.n = 2
+
+		asserts.True(Member[any](p, SUT))
+	})
+
+	t.Run("Interface keys are told apart by their dynamic type", func(t *testing.T) {
+		SUT := Insert[any](int64(1), "b", Singleton[any](1, "a"))
+
+		asserts.Equal(basics.Int(2), Size(SUT))
+		asserts.Equal(maybe.Just[string]{Value: "a"}, Get[any](1, SUT))
+		asserts.Equal(maybe.Just[string]{Value: "b"}, Get[any](int64(1), SUT))
+	})
+
+	t.Run("Keys that are == hash the same", func(t *testing.T) {
+		type named struct {
+			name  string
+			score float64
+			_     int
+		}
+		zero := 0.0
+		SUT := Singleton(named{name: "a", score: zero}, 1)
+
+		asserts.True(Member(named{name: "a", score: -zero}, SUT))
+		asserts.True(Member(math.Copysign(0, -1), Singleton(0.0, 1)))
+		asserts.True(Member([2]string{"a", "b"}, Singleton([2]string{"a", "b"}, 1)))
+	})
+}
+
+func TestPersistence(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Random operations match a Go map and leave old versions alone", func(t *testing.T) {
+		r := rand.New(rand.NewSource(42))
+		type version struct {
+			d HashDict[point, int]
+			m map[point]int
+		}
+		var versions []version
+		d, m := Empty[point, int](), map[point]int{}
+		for i := 0; i < 5000; i++ {
+			k := point{r.Intn(40), r.Intn(40)}
+			if r.Intn(3) == 0 {
+				d = Remove(k, d)
+				delete(m, k)
+			} else {
+				d = Insert(k, i, d)
+				m[k] = i
+			}
+			if i%250 == 0 {
+				snapshot := make(map[point]int, len(m))
+				for k, v := range m {
+					snapshot[k] = v
+				}
+				versions = append(versions, version{d, snapshot})
+			}
+		}
+		versions = append(versions, version{d, m})
+
+		for _, v := range versions {
+			asserts.Equal(basics.Int(len(v.m)), Size(v.d))
+			got := map[point]int{}
+			for k, val := range All(v.d) {
+				got[k] = val
+			}
+			asserts.Equal(v.m, got)
+		}
+	})
+
+	t.Run("The trie has the same shape however it was built", func(t *testing.T) {
+		var pairs []tuple.Tuple2[point, int]
+		for i := 0; i < 500; i++ {
+			pairs = append(pairs, tuple.Pair(point{i, -i}, i))
+		}
+		forwards := FromList(list.FromSlice(pairs))
+		backwards := FromList(list.Reverse(list.FromSlice(pairs)))
+		withExtras := forwards
+		for i := 500; i < 1000; i++ {
+			withExtras = Insert(point{i, -i}, i, withExtras)
+		}
+		for i := 500; i < 1000; i++ {
+			withExtras = Remove(point{i, -i}, withExtras)
+		}
+
+		asserts.Equal(forwards, backwards)
+		asserts.Equal(forwards, withExtras)
+	})
+}
+
+func TestLists(t *testing.T) {
+	asserts := assert.New(t)
+	d := FromList(list.FromSlice([]tuple.Tuple2[point, int]{
+		tuple.Pair(point{1, 1}, 1), tuple.Pair(point{2, 2}, 2), tuple.Pair(point{3, 3}, 3),
+	}))
+
+	t.Run("Keys", func(t *testing.T) {
+		SUT := Keys(d)
+
+		asserts.ElementsMatch([]point{{1, 1}, {2, 2}, {3, 3}}, list.ToSlice(SUT))
+	})
+
+	t.Run("Values", func(t *testing.T) {
+		SUT := Values(d)
+
+		asserts.ElementsMatch([]int{1, 2, 3}, list.ToSlice(SUT))
+	})
+
+	t.Run("ToList matches Keys and Values", func(t *testing.T) {
+		SUT := ToList(d)
+
+		asserts.Equal(list.ToSlice(Keys(d)), list.ToSlice(list.Map(tuple.First[point, int], SUT)))
+		asserts.Equal(list.ToSlice(Values(d)), list.ToSlice(list.Map(tuple.Second[point, int], SUT)))
+	})
+
+	t.Run("FromList keeps the last value", func(t *testing.T) {
+		SUT := FromList(list.FromSlice([]tuple.Tuple2[point, int]{
+			tuple.Pair(point{1, 1}, 1), tuple.Pair(point{1, 1}, 2),
+		}))
+
+		asserts.Equal(Singleton(point{1, 1}, 2), SUT)
+	})
+}
+
+func TestTransform(t *testing.T) {
+	asserts := assert.New(t)
+	d := FromList(list.FromSlice([]tuple.Tuple2[point, int]{
+		tuple.Pair(point{1, 1}, 1), tuple.Pair(point{2, 2}, 2), tuple.Pair(point{3, 3}, 3),
+	}))
+
+	t.Run("Map", func(t *testing.T) {
+		SUT := Map(func(k point, v int) string { return string(rune('a' + v)) }, d)
+
+		asserts.Equal(maybe.Just[string]{Value: "c"}, Get(point{2, 2}, SUT))
+		asserts.Equal(Size(d), Size(SUT))
+	})
+
+	t.Run("Foldl", func(t *testing.T) {
+		SUT := Foldl(func(k point, v int, acc int) int { return acc + v }, 0, d)
+
+		asserts.Equal(6, SUT)
+	})
+
+	t.Run("Foldr is the reverse of Foldl", func(t *testing.T) {
+		cons := func(k point, v int, acc list.List[int]) list.List[int] { return list.Cons(v, acc) }
+
+		asserts.Equal(
+			list.ToSlice(list.Reverse(Foldr(cons, list.Empty[int](), d))),
+			list.ToSlice(Foldl(cons, list.Empty[int](), d)),
+		)
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		SUT := Filter(func(k point, v int) bool { return v > 1 }, d)
+
+		asserts.Equal(basics.Int(2), Size(SUT))
+		asserts.False(Member(point{1, 1}, SUT))
+	})
+
+	t.Run("Partition", func(t *testing.T) {
+		SUT := Partition(func(k point, v int) bool { return v > 1 }, d)
+
+		asserts.Equal(basics.Int(2), Size(tuple.First(SUT)))
+		asserts.Equal(Singleton(point{1, 1}, 1), tuple.Second(SUT))
+	})
+}
+
+func TestCombine(t *testing.T) {
+	asserts := assert.New(t)
+	small := FromList(list.FromSlice([]tuple.Tuple2[point, string]{
+		tuple.Pair(point{1, 1}, "small"),
+	}))
+	large := FromList(list.FromSlice([]tuple.Tuple2[point, string]{
+		tuple.Pair(point{1, 1}, "large"), tuple.Pair(point{2, 2}, "large"),
+	}))
+
+	t.Run("Union prefers the first dictionary", func(t *testing.T) {
+		asserts.Equal(maybe.Just[string]{Value: "small"}, Get(point{1, 1}, Union(small, large)))
+		asserts.Equal(maybe.Just[string]{Value: "large"}, Get(point{1, 1}, Union(large, small)))
+		asserts.Equal(basics.Int(2), Size(Union(small, large)))
+	})
+
+	t.Run("Intersect", func(t *testing.T) {
+		SUT := Intersect(large, small)
+
+		asserts.Equal(Singleton(point{1, 1}, "large"), SUT)
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		SUT := Diff(large, small)
+
+		asserts.Equal(Singleton(point{2, 2}, "large"), SUT)
+	})
+}
+
+func TestEquality(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Equal dictionaries", func(t *testing.T) {
+		x := Insert(point{1, 1}, 1, Singleton(point{2, 2}, 2))
+		y := Insert(point{2, 2}, 2, Singleton(point{1, 1}, 1))
+
+		asserts.True(basics.Eq(x, y))
+		asserts.Equal(basics.Hash(x), basics.Hash(y))
+	})
+
+	t.Run("Different values", func(t *testing.T) {
+		x := Singleton(point{1, 1}, 1)
+		y := Singleton(point{1, 1}, 2)
+
+		asserts.False(basics.Eq(x, y))
+	})
+
+	t.Run("Different sizes", func(t *testing.T) {
+		x := Singleton(point{1, 1}, 1)
+		y := Insert(point{2, 2}, 2, x)
+
+		asserts.False(basics.Eq(x, y))
+	})
+}
+
+func BenchmarkGet(b *testing.B) {
+	d := Empty[point, int]()
+	for i := 0; i < 10000; i++ {
+		d = Insert(point{i, i}, i, d)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Get(point{i % 10000, i % 10000}, d)
+	}
+}
+
+func BenchmarkGetBytes(b *testing.B) {
+	d := Empty[[16]byte, int]()
+	keys := make([][16]byte, 10000)
+	for i := range keys {
+		keys[i][0], keys[i][1] = byte(i), byte(i>>8)
+		d = Insert(keys[i], i, d)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Get(keys[i%10000], d)
+	}
+}
@@ -0,0 +1,42 @@
+// Package hashing has the hash functions shared by the Hashable types of this module.
+package hashing
+
+import (
+	"hash/maphash"
+	"math"
+)
+
+// Strings are hashed with a seed made when the program starts, so hashes are only stable within one process.
+var seed = maphash.MakeSeed()
+
+// Mix the bits of an integer so that nearby integers get unrelated hashes, using the splitmix64 finalizer.
+func Uint64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Hash a float so that 0 and -0, which are equal, get the same hash.
+func Float64(f float64) uint64 {
+	if f == 0 {
+		return Uint64(0)
+	}
+	return Uint64(math.Float64bits(f))
+}
+
+func String(s string) uint64 {
+	return maphash.String(seed, s)
+}
+
+// Add the hash x to the running hash h, for values made of a sequence of parts.
+// The order of the parts changes the result.
+func Combine(h, x uint64) uint64 {
+	return Uint64(h ^ (x + 0x9e3779b97f4a7c15 + h<<6 + h>>2))
+}
+
+func Bytes(b []byte) uint64 {
+	return maphash.Bytes(seed, b)
+}
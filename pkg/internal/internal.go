@@ -1,27 +1,26 @@
 package internal
 
 import (
-	"cmp"
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"github.com/Confidenceman02/scion-tools/pkg/basics"
 	"reflect"
+	"sync"
 )
 
-func CmpHelp(x any, y any) int {
-	switch x1 := x.(type) {
-	case basics.Int:
-		switch y1 := y.(type) {
-		case basics.Int:
-			return cmp.Compare(x1, y1)
+// CmpHelp compares two values of a type that is expected to be Comparable at runtime.
+// Container types like List and Tuple2 accept any element type, so their Cmp methods use this
+// to compare elements, which also covers nested containers like lists of tuples and tuples of lists.
+func CmpHelp[T any](x T, y T) int {
+	switch x1 := any(x).(type) {
+	case basics.Comparable[T]:
+		switch y1 := any(y).(type) {
+		case basics.Comparable[T]:
+			return x1.Cmp(y1)
 		default:
-			panic("Not an Int")
-		}
-	case basics.Float:
-		switch y1 := y.(type) {
-		case basics.Float:
-			return cmp.Compare(x1, y1)
-		default:
-			panic("Not a Float")
+			panic(fmt.Sprintf("Cmp Not implemented for: %v", reflect.TypeOf(y1)))
 		}
 	default:
 		panic(fmt.Sprintf("Cmp Not implemented for: %v", reflect.TypeOf(x1)))
@@ -32,3 +31,47 @@ type Cons_[A, B any] struct {
 	A A
 	B B
 }
+
+// JSON decoders for the interface types of this module, keyed by their reflect.Type.
+var jsonDecoders sync.Map
+
+// RegisterJSON makes UnmarshalJSON use decode for values of type T.
+//
+// Go cannot decode JSON into an interface, and lists, dicts, sets, maybes, results and tuples are
+// interfaces. A struct field holds one of them in its package's Field type, whose UnmarshalJSON calls
+// FromJSON, and a collection inside another one is decoded by its container looking up the decoder
+// of the element type here.
+// The Register functions of those packages add their FromJSON, so lists of dicts or maybes of
+// tuples can be decoded once their element types are registered.
+func RegisterJSON[T any](decode func([]byte) (T, error)) {
+	jsonDecoders.Store(reflect.TypeFor[T](), func(data []byte) (any, error) {
+		return decode(data)
+	})
+}
+
+// UnmarshalJSON decodes a value of type T, using the decoder registered for T if there is one
+// and encoding/json otherwise.
+func UnmarshalJSON[T any](data []byte) (T, error) {
+	var v T
+	if decode, ok := jsonDecoders.Load(reflect.TypeFor[T]()); ok {
+		d, err := decode.(func([]byte) (any, error))(data)
+		if err != nil {
+			return v, err
+		}
+		return d.(T), nil
+	}
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+// GobEncode encodes v with a gob encoder of its own, for containers that send their elements as slices.
+func GobEncode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+// GobDecode decodes data made by GobEncode into the value v points to.
+func GobDecode(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
@@ -0,0 +1,558 @@
+// Package decode turns JSON values into Go values, inspired by the Elm Json.Decode module.
+//
+// Build a Decoder out of the primitive decoders and combinators, then run it with
+// DecodeString. Every failure is reported as a structured Error that pinpoints
+// where in the JSON things went wrong.
+package decode
+
+import (
+	"fmt"
+	"github.com/Confidenceman02/scion-tools/pkg/array"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/dict"
+	"github.com/Confidenceman02/scion-tools/pkg/json/internal"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/result"
+	s "github.com/Confidenceman02/scion-tools/pkg/string"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// A Decoder knows how to turn a JSON Value into a Go value of type T.
+type Decoder[T any] interface {
+	decoder() *decoder[T]
+}
+
+/*
+Retrieve the internal decoder
+*/
+func (d *decoder[T]) decoder() *decoder[T] {
+	return d
+}
+
+type decoder[T any] struct {
+	run func(Value) result.Result[Error, T]
+}
+
+// Value represents a JavaScript value.
+type Value = internal.Value
+
+// ERRORS
+
+// A structured error describing exactly how the decoder failed. You can use
+// this to create more elaborate visualizations of a decoder problem.
+type Error interface {
+	error_() _error
+}
+
+type _error struct{}
+
+func (e _error) error_() _error {
+	return e
+}
+
+// FieldError - A field of an object could not be decoded.
+type FieldError struct {
+	_error
+	Name s.String
+	Err  Error
+}
+
+// IndexError - An entry of an array could not be decoded.
+type IndexError struct {
+	_error
+	Index basics.Int
+	Err   Error
+}
+
+// OneOfError - Every decoder given to OneOf failed.
+type OneOfError struct {
+	_error
+	Errors list.List[Error]
+}
+
+// Failure - The value itself did not have the expected shape.
+type Failure struct {
+	_error
+	Message s.String
+	Value   Value
+}
+
+// RUN DECODERS
+
+// Parse the given string into a JSON value and then run the Decoder on it.
+// This will fail if the string is not well-formed JSON or if the Decoder fails for some reason.
+func DecodeString[T any](d Decoder[T], str s.String) result.Result[Error, T] {
+	v, err := internal.Parse(string(str))
+	if err != nil {
+		return result.Err[Error, T]{
+			Err: Failure{Message: s.String("This is not valid JSON! " + err.Error()), Value: internal.String(str)},
+		}
+	}
+	return DecodeValue(d, v)
+}
+
+// Run a Decoder on some JSON Value.
+func DecodeValue[T any](d Decoder[T], v Value) result.Result[Error, T] {
+	return d.decoder().run(v)
+}
+
+// PRIMITIVES
+
+// Decode a JSON string into a String.
+var String Decoder[s.String] = &decoder[s.String]{
+	run: func(v Value) result.Result[Error, s.String] {
+		if str, ok := v.(internal.String); ok {
+			return result.Ok[Error, s.String]{Val: s.String(str)}
+		}
+		return expecting[s.String]("a STRING", v)
+	},
+}
+
+// Decode a JSON number into an Int.
+var Int Decoder[basics.Int] = &decoder[basics.Int]{
+	run: func(v Value) result.Result[Error, basics.Int] {
+		if n, ok := v.(internal.Number); ok {
+			if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+				return result.Ok[Error, basics.Int]{Val: basics.Int(i)}
+			}
+			// Numbers like 1e3 or 4.0 are still integers
+			if f, err := strconv.ParseFloat(string(n), 64); err == nil && f == math.Trunc(f) && math.Abs(f) < (1<<63) {
+				return result.Ok[Error, basics.Int]{Val: basics.Int(f)}
+			}
+		}
+		return expecting[basics.Int]("an INT", v)
+	},
+}
+
+// Decode a JSON number into a Float.
+var Float Decoder[basics.Float] = &decoder[basics.Float]{
+	run: func(v Value) result.Result[Error, basics.Float] {
+		if n, ok := v.(internal.Number); ok {
+			if f, err := strconv.ParseFloat(string(n), 32); err == nil {
+				return result.Ok[Error, basics.Float]{Val: basics.Float(f)}
+			}
+		}
+		return expecting[basics.Float]("a FLOAT", v)
+	},
+}
+
+// Decode a JSON boolean into a bool.
+var Bool Decoder[bool] = &decoder[bool]{
+	run: func(v Value) result.Result[Error, bool] {
+		if b, ok := v.(internal.Bool); ok {
+			return result.Ok[Error, bool]{Val: bool(b)}
+		}
+		return expecting[bool]("a BOOL", v)
+	},
+}
+
+// DATA STRUCTURES
+
+// Decode a nullable JSON value into a Maybe value.
+func Nullable[T any](d Decoder[T]) Decoder[maybe.Maybe[T]] {
+	return OneOf(list.FromSlice([]Decoder[maybe.Maybe[T]]{
+		Null[maybe.Maybe[T]](maybe.Nothing{}),
+		Map(func(x T) maybe.Maybe[T] { return maybe.Just[T]{Value: x} }, d),
+	}))
+}
+
+// Decode a JSON array into a List.
+func List[T any](d Decoder[T]) Decoder[list.List[T]] {
+	return &decoder[list.List[T]]{
+		run: func(v Value) result.Result[Error, list.List[T]] {
+			arr, ok := v.(internal.Array)
+			if !ok {
+				return expecting[list.List[T]]("a LIST", v)
+			}
+			xs, err := decodeEntries(d, arr)
+			if err != nil {
+				return result.Err[Error, list.List[T]]{Err: err}
+			}
+			return result.Ok[Error, list.List[T]]{Val: list.FromSlice(xs)}
+		},
+	}
+}
+
+// Decode a JSON array into an Array.
+func Array[T any](d Decoder[T]) Decoder[array.Array[T]] {
+	return &decoder[array.Array[T]]{
+		run: func(v Value) result.Result[Error, array.Array[T]] {
+			arr, ok := v.(internal.Array)
+			if !ok {
+				return expecting[array.Array[T]]("an ARRAY", v)
+			}
+			xs, err := decodeEntries(d, arr)
+			if err != nil {
+				return result.Err[Error, array.Array[T]]{Err: err}
+			}
+			return result.Ok[Error, array.Array[T]]{Val: array.FromList(list.FromSlice(xs))}
+		},
+	}
+}
+
+func decodeEntries[T any](d Decoder[T], arr internal.Array) ([]T, Error) {
+	xs := make([]T, 0, len(arr))
+	for i, item := range arr {
+		switch r := d.decoder().run(item).(type) {
+		case result.Ok[Error, T]:
+			xs = append(xs, r.Val)
+		case result.Err[Error, T]:
+			return nil, IndexError{Index: basics.Int(i), Err: r.Err}
+		}
+	}
+	return xs, nil
+}
+
+// Decode a JSON object into a Dict.
+func Dict[T any](d Decoder[T]) Decoder[dict.Dict[s.String, T]] {
+	return Map(dict.FromList[s.String, T], KeyValuePairs(d))
+}
+
+// Decode a JSON object into a List of pairs, keeping the order of the fields.
+func KeyValuePairs[T any](d Decoder[T]) Decoder[list.List[tuple.Tuple2[s.String, T]]] {
+	return &decoder[list.List[tuple.Tuple2[s.String, T]]]{
+		run: func(v Value) result.Result[Error, list.List[tuple.Tuple2[s.String, T]]] {
+			obj, ok := v.(internal.Object)
+			if !ok {
+				return expecting[list.List[tuple.Tuple2[s.String, T]]]("an OBJECT", v)
+			}
+			pairs := make([]tuple.Tuple2[s.String, T], 0, len(obj))
+			for _, m := range obj {
+				switch r := d.decoder().run(m.Value).(type) {
+				case result.Ok[Error, T]:
+					pairs = append(pairs, tuple.Pair(s.String(m.Key), r.Val))
+				case result.Err[Error, T]:
+					return result.Err[Error, list.List[tuple.Tuple2[s.String, T]]]{
+						Err: FieldError{Name: s.String(m.Key), Err: r.Err},
+					}
+				}
+			}
+			return result.Ok[Error, list.List[tuple.Tuple2[s.String, T]]]{Val: list.FromSlice(pairs)}
+		},
+	}
+}
+
+// OBJECT PRIMITIVES
+
+// Decode a JSON object, requiring a particular field.
+func Field[T any](name s.String, d Decoder[T]) Decoder[T] {
+	return &decoder[T]{
+		run: func(v Value) result.Result[Error, T] {
+			obj, ok := v.(internal.Object)
+			if !ok {
+				return expecting[T]("an OBJECT with a field named `"+string(name)+"`", v)
+			}
+			fieldValue, ok := obj.Get(string(name))
+			if !ok {
+				return expecting[T]("an OBJECT with a field named `"+string(name)+"`", v)
+			}
+			return result.MapError(
+				func(e Error) Error { return FieldError{Name: name, Err: e} },
+				d.decoder().run(fieldValue),
+			)
+		},
+	}
+}
+
+// Decode a nested JSON object, requiring certain fields.
+func At[T any](fields list.List[s.String], d Decoder[T]) Decoder[T] {
+	return list.Foldr(Field[T], d, fields)
+}
+
+// Decode a JSON array, requiring a particular index.
+func Index[T any](i basics.Int, d Decoder[T]) Decoder[T] {
+	return &decoder[T]{
+		run: func(v Value) result.Result[Error, T] {
+			arr, ok := v.(internal.Array)
+			if !ok {
+				return expecting[T]("an ARRAY", v)
+			}
+			if i < 0 || int(i) >= len(arr) {
+				return expecting[T](
+					fmt.Sprintf("a LONGER array. Need index %d but only see %d entries", i, len(arr)),
+					v,
+				)
+			}
+			return result.MapError(
+				func(e Error) Error { return IndexError{Index: i, Err: e} },
+				d.decoder().run(arr[i]),
+			)
+		},
+	}
+}
+
+// INCONSISTENT STRUCTURE
+
+// Helpful for dealing with optional fields. The decoder succeeds with Nothing
+// whenever the given decoder fails.
+func Maybe[T any](d Decoder[T]) Decoder[maybe.Maybe[T]] {
+	return OneOf(list.FromSlice([]Decoder[maybe.Maybe[T]]{
+		Map(func(x T) maybe.Maybe[T] { return maybe.Just[T]{Value: x} }, d),
+		Succeed[maybe.Maybe[T]](maybe.Nothing{}),
+	}))
+}
+
+// Try a bunch of different decoders. This can be useful if the JSON may come in a couple different formats.
+func OneOf[T any](decoders list.List[Decoder[T]]) Decoder[T] {
+	return &decoder[T]{
+		run: func(v Value) result.Result[Error, T] {
+			errs := []Error{}
+			for ds := decoders; ds.Cons() != nil; ds = ds.Cons().B {
+				switch r := ds.Cons().A.decoder().run(v).(type) {
+				case result.Ok[Error, T]:
+					return r
+				case result.Err[Error, T]:
+					errs = append(errs, r.Err)
+				}
+			}
+			return result.Err[Error, T]{Err: OneOfError{Errors: list.FromSlice(errs)}}
+		},
+	}
+}
+
+// MAPPING
+
+// Transform a decoder.
+func Map[A, value any](f func(A) value, d Decoder[A]) Decoder[value] {
+	return &decoder[value]{
+		run: func(v Value) result.Result[Error, value] {
+			return result.Map(f, d.decoder().run(v))
+		},
+	}
+}
+
+// Try two decoders and then combine the result. We can use this to decode objects with many fields.
+func Map2[A, B, value any](f func(A, B) value, da Decoder[A], db Decoder[B]) Decoder[value] {
+	return AndThen(func(a A) Decoder[value] {
+		return Map(func(b B) value { return f(a, b) }, db)
+	}, da)
+}
+
+// Try three decoders and then combine the result.
+func Map3[A, B, C, value any](f func(A, B, C) value, da Decoder[A], db Decoder[B], dc Decoder[C]) Decoder[value] {
+	return AndThen(func(a A) Decoder[value] {
+		return Map2(func(b B, c C) value { return f(a, b, c) }, db, dc)
+	}, da)
+}
+
+// Try four decoders and then combine the result.
+func Map4[A, B, C, D, value any](
+	f func(A, B, C, D) value,
+	da Decoder[A],
+	db Decoder[B],
+	dc Decoder[C],
+	dd Decoder[D],
+) Decoder[value] {
+	return AndThen(func(a A) Decoder[value] {
+		return Map3(func(b B, c C, d D) value { return f(a, b, c, d) }, db, dc, dd)
+	}, da)
+}
+
+// Try five decoders and then combine the result.
+func Map5[A, B, C, D, E, value any](
+	f func(A, B, C, D, E) value,
+	da Decoder[A],
+	db Decoder[B],
+	dc Decoder[C],
+	dd Decoder[D],
+	de Decoder[E],
+) Decoder[value] {
+	return AndThen(func(a A) Decoder[value] {
+		return Map4(func(b B, c C, d D, e E) value { return f(a, b, c, d, e) }, db, dc, dd, de)
+	}, da)
+}
+
+// Try six decoders and then combine the result.
+func Map6[A, B, C, D, E, F, value any](
+	f func(A, B, C, D, E, F) value,
+	da Decoder[A],
+	db Decoder[B],
+	dc Decoder[C],
+	dd Decoder[D],
+	de Decoder[E],
+	df Decoder[F],
+) Decoder[value] {
+	return AndThen(func(a A) Decoder[value] {
+		return Map5(func(b B, c C, d D, e E, f1 F) value { return f(a, b, c, d, e, f1) }, db, dc, dd, de, df)
+	}, da)
+}
+
+// Try seven decoders and then combine the result.
+func Map7[A, B, C, D, E, F, G, value any](
+	f func(A, B, C, D, E, F, G) value,
+	da Decoder[A],
+	db Decoder[B],
+	dc Decoder[C],
+	dd Decoder[D],
+	de Decoder[E],
+	df Decoder[F],
+	dg Decoder[G],
+) Decoder[value] {
+	return AndThen(func(a A) Decoder[value] {
+		return Map6(
+			func(b B, c C, d D, e E, f1 F, g G) value { return f(a, b, c, d, e, f1, g) },
+			db, dc, dd, de, df, dg,
+		)
+	}, da)
+}
+
+// Try eight decoders and then combine the result.
+func Map8[A, B, C, D, E, F, G, H, value any](
+	f func(A, B, C, D, E, F, G, H) value,
+	da Decoder[A],
+	db Decoder[B],
+	dc Decoder[C],
+	dd Decoder[D],
+	de Decoder[E],
+	df Decoder[F],
+	dg Decoder[G],
+	dh Decoder[H],
+) Decoder[value] {
+	return AndThen(func(a A) Decoder[value] {
+		return Map7(
+			func(b B, c C, d D, e E, f1 F, g G, h H) value { return f(a, b, c, d, e, f1, g, h) },
+			db, dc, dd, de, df, dg, dh,
+		)
+	}, da)
+}
+
+// FANCY DECODING
+
+// Decode a null value into some Go value.
+func Null[T any](x T) Decoder[T] {
+	return &decoder[T]{
+		run: func(v Value) result.Result[Error, T] {
+			if _, ok := v.(internal.Null); ok {
+				return result.Ok[Error, T]{Val: x}
+			}
+			return expecting[T]("null", v)
+		},
+	}
+}
+
+// Ignore the JSON and produce a certain Go value.
+func Succeed[T any](x T) Decoder[T] {
+	return &decoder[T]{
+		run: func(Value) result.Result[Error, T] {
+			return result.Ok[Error, T]{Val: x}
+		},
+	}
+}
+
+// Ignore the JSON and make the decoder fail. This is handy when used with
+// OneOf or AndThen where you want to give a custom error message in some case.
+func Fail[T any](msg s.String) Decoder[T] {
+	return &decoder[T]{
+		run: func(v Value) result.Result[Error, T] {
+			return result.Err[Error, T]{Err: Failure{Message: msg, Value: v}}
+		},
+	}
+}
+
+// Create decoders that depend on previous results.
+func AndThen[A, B any](f func(A) Decoder[B], d Decoder[A]) Decoder[B] {
+	return &decoder[B]{
+		run: func(v Value) result.Result[Error, B] {
+			return result.AndThen(
+				func(a A) result.Result[Error, B] { return f(a).decoder().run(v) },
+				d.decoder().run(v),
+			)
+		},
+	}
+}
+
+// Sometimes you have JSON with recursive structure, like nested comments.
+// You can use Lazy to make sure your decoder unrolls lazily.
+func Lazy[T any](thunk func() Decoder[T]) Decoder[T] {
+	return AndThen(func(struct{}) Decoder[T] { return thunk() }, Succeed(struct{}{}))
+}
+
+func expecting[T any](kind string, v Value) result.Result[Error, T] {
+	return result.Err[Error, T]{Err: Failure{Message: s.String("Expecting " + kind), Value: v}}
+}
+
+// ERROR MESSAGES
+
+// Convert a decoding error into a String that is nice for debugging.
+func ErrorToString(err Error) s.String {
+	return s.String(errorToStringHelp(err, []string{}))
+}
+
+func errorToStringHelp(err Error, context []string) string {
+errorToStringHelpL:
+	for {
+		switch e := err.(type) {
+		case FieldError:
+			var fieldName string
+			if isSimple(string(e.Name)) {
+				fieldName = "." + string(e.Name)
+			} else {
+				fieldName = "['" + string(e.Name) + "']"
+			}
+			err = e.Err
+			context = append(context, fieldName)
+			continue errorToStringHelpL
+		case IndexError:
+			err = e.Err
+			context = append(context, "["+strconv.Itoa(int(e.Index))+"]")
+			continue errorToStringHelpL
+		case OneOfError:
+			errs := list.ToSlice(e.Errors)
+			switch len(errs) {
+			case 0:
+				if len(context) == 0 {
+					return "Ran into a Json.Decode.oneOf with no possibilities!"
+				} else {
+					return "Ran into a Json.Decode.oneOf with no possibilities at json" + strings.Join(context, "")
+				}
+			case 1:
+				err = errs[0]
+				continue errorToStringHelpL
+			default:
+				var starter string
+				if len(context) == 0 {
+					starter = "Json.Decode.oneOf"
+				} else {
+					starter = "The Json.Decode.oneOf at json" + strings.Join(context, "")
+				}
+				parts := []string{starter + " failed in the following " + strconv.Itoa(len(errs)) + " ways:"}
+				for i, e1 := range errs {
+					parts = append(parts, "\n\n("+strconv.Itoa(i+1)+") "+indent(string(ErrorToString(e1))))
+				}
+				return strings.Join(parts, "\n\n")
+			}
+		case Failure:
+			var introduction string
+			if len(context) == 0 {
+				introduction = "Problem with the given value:\n\n"
+			} else {
+				introduction = "Problem with the value at json" + strings.Join(context, "") + ":\n\n    "
+			}
+			return introduction + indent(internal.Encode(4, e.Value)) + "\n\n" + string(e.Message)
+		default:
+			panic(fmt.Sprintf("Unknown decode error: %v", e))
+		}
+	}
+}
+
+// A field name is simple when it can be accessed with dot notation.
+func isSimple(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		isAlpha := ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+		if !isAlpha && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func indent(str string) string {
+	return strings.Join(strings.Split(str, "\n"), "\n    ")
+}
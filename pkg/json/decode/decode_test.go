@@ -0,0 +1,355 @@
+package decode
+
+import (
+	"fmt"
+	"github.com/Confidenceman02/scion-tools/pkg/array"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/dict"
+	"github.com/Confidenceman02/scion-tools/pkg/json/internal"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/result"
+	s "github.com/Confidenceman02/scion-tools/pkg/string"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type person struct {
+	name s.String
+	age  basics.Int
+}
+
+func ok[T any](v T) result.Result[Error, T] {
+	return result.Ok[Error, T]{Val: v}
+}
+
+func errorString[T any](r result.Result[Error, T]) s.String {
+	return result.ResultWith(
+		r,
+		func(e result.Err[Error, T]) s.String { return ErrorToString(e.Err) },
+		func(result.Ok[Error, T]) s.String { return "" },
+	)
+}
+
+func TestPrimitives(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("String", func(t *testing.T) {
+		asserts.Equal(ok[s.String]("hello"), DecodeString(String, `"hello"`))
+		asserts.Equal(
+			result.Err[Error, s.String]{Err: Failure{Message: "Expecting a STRING", Value: internal.Number("42")}},
+			DecodeString(String, "42"),
+		)
+	})
+	t.Run("Int", func(t *testing.T) {
+		asserts.Equal(ok[basics.Int](42), DecodeString(Int, "42"))
+		asserts.Equal(ok[basics.Int](-7), DecodeString(Int, "-7"))
+		asserts.Equal(ok[basics.Int](1000), DecodeString(Int, "1e3"))
+		asserts.Equal(ok[basics.Int](4), DecodeString(Int, "4.0"))
+		asserts.Equal(
+			result.Err[Error, basics.Int]{Err: Failure{Message: "Expecting an INT", Value: internal.Number("3.14")}},
+			DecodeString(Int, "3.14"),
+		)
+	})
+	t.Run("Float", func(t *testing.T) {
+		asserts.Equal(ok[basics.Float](3.14), DecodeString(Float, "3.14"))
+		asserts.Equal(ok[basics.Float](42), DecodeString(Float, "42"))
+		asserts.Equal(
+			result.Err[Error, basics.Float]{Err: Failure{Message: "Expecting a FLOAT", Value: internal.Bool(true)}},
+			DecodeString(Float, "true"),
+		)
+	})
+	t.Run("Bool", func(t *testing.T) {
+		asserts.Equal(ok(true), DecodeString(Bool, "true"))
+		asserts.Equal(ok(false), DecodeString(Bool, "false"))
+		asserts.Equal(
+			result.Err[Error, bool]{Err: Failure{Message: "Expecting a BOOL", Value: internal.Null{}}},
+			DecodeString(Bool, "null"),
+		)
+	})
+	t.Run("Invalid JSON", func(t *testing.T) {
+		SUT := DecodeString(Int, "{")
+
+		asserts.IsType(result.Err[Error, basics.Int]{}, SUT)
+		asserts.Contains(string(errorString(SUT)), "This is not valid JSON!")
+	})
+	t.Run("Trailing data", func(t *testing.T) {
+		asserts.IsType(result.Err[Error, basics.Int]{}, DecodeString(Int, "1 2"))
+	})
+}
+
+func TestDataStructures(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Nullable", func(t *testing.T) {
+		asserts.Equal(ok[maybe.Maybe[basics.Int]](maybe.Just[basics.Int]{Value: 13}), DecodeString(Nullable(Int), "13"))
+		asserts.Equal(ok[maybe.Maybe[basics.Int]](maybe.Nothing{}), DecodeString(Nullable(Int), "null"))
+		asserts.IsType(result.Err[Error, maybe.Maybe[basics.Int]]{}, DecodeString(Nullable(Int), "true"))
+	})
+	t.Run("List", func(t *testing.T) {
+		asserts.Equal(ok(list.FromSlice([]basics.Int{1, 2, 3})), DecodeString(List(Int), "[1,2,3]"))
+		asserts.Equal(ok(list.Empty[bool]()), DecodeString(List(Bool), "[]"))
+		asserts.Equal(
+			result.Err[Error, list.List[basics.Int]]{
+				Err: IndexError{Index: 1, Err: Failure{Message: "Expecting an INT", Value: internal.String("2")}},
+			},
+			DecodeString(List(Int), `[1,"2"]`),
+		)
+	})
+	t.Run("Array", func(t *testing.T) {
+		asserts.Equal(
+			ok(array.FromList(list.FromSlice([]basics.Int{1, 2, 3}))),
+			DecodeString(Array(Int), "[1,2,3]"),
+		)
+	})
+	t.Run("Dict", func(t *testing.T) {
+		asserts.Equal(
+			ok(dict.FromList(list.FromSlice([]tuple.Tuple2[s.String, basics.Int]{
+				tuple.Pair[s.String, basics.Int]("alice", 42),
+				tuple.Pair[s.String, basics.Int]("bob", 99),
+			}))),
+			DecodeString(Dict(Int), `{ "alice": 42, "bob": 99 }`),
+		)
+	})
+	t.Run("KeyValuePairs keeps field order", func(t *testing.T) {
+		asserts.Equal(
+			ok(list.FromSlice([]tuple.Tuple2[s.String, basics.Int]{
+				tuple.Pair[s.String, basics.Int]("zed", 1),
+				tuple.Pair[s.String, basics.Int]("alice", 2),
+			})),
+			DecodeString(KeyValuePairs(Int), `{ "zed": 1, "alice": 2 }`),
+		)
+	})
+	t.Run("A duplicate field keeps its first position and its last value", func(t *testing.T) {
+		asserts.Equal(
+			ok(list.FromSlice([]tuple.Tuple2[s.String, basics.Int]{
+				tuple.Pair[s.String, basics.Int]("zed", 3),
+				tuple.Pair[s.String, basics.Int]("alice", 2),
+			})),
+			DecodeString(KeyValuePairs(Int), `{ "zed": 1, "alice": 2, "zed": 3 }`),
+		)
+	})
+	t.Run("Large object", func(t *testing.T) {
+		SUT := DecodeString(Dict(Int), largeObject(100_000))
+
+		asserts.Equal(
+			ok(basics.Int(100_000)),
+			result.Map(func(d dict.Dict[s.String, basics.Int]) basics.Int { return dict.Size(d) }, SUT),
+		)
+	})
+}
+
+// An object with n fields, which is slow to parse if finding duplicate keys takes longer as the object grows.
+func largeObject(n int) s.String {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, `"key%d":%d`, i, i)
+	}
+	sb.WriteByte('}')
+	return s.String(sb.String())
+}
+
+func BenchmarkDecodeStringObject(b *testing.B) {
+	obj := largeObject(10_000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DecodeString(KeyValuePairs(Int), obj)
+	}
+}
+
+func TestObjectPrimitives(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Field", func(t *testing.T) {
+		asserts.Equal(ok[basics.Int](3), DecodeString(Field("x", Int), `{ "x": 3 }`))
+		asserts.Equal(ok[basics.Int](3), DecodeString(Field("x", Int), `{ "x": 3, "y": 4 }`))
+		asserts.Equal(
+			result.Err[Error, basics.Int]{
+				Err: FieldError{Name: "x", Err: Failure{Message: "Expecting an INT", Value: internal.Bool(true)}},
+			},
+			DecodeString(Field("x", Int), `{ "x": true }`),
+		)
+		asserts.Equal(
+			result.Err[Error, basics.Int]{
+				Err: Failure{
+					Message: "Expecting an OBJECT with a field named `x`",
+					Value:   internal.Object{{Key: "y", Value: internal.Number("4")}},
+				},
+			},
+			DecodeString(Field("x", Int), `{ "y": 4 }`),
+		)
+	})
+	t.Run("At", func(t *testing.T) {
+		json := `{ "person": { "name": "tom", "age": 42 } }`
+
+		asserts.Equal(ok[s.String]("tom"), DecodeString(At(list.FromSlice([]s.String{"person", "name"}), String), s.String(json)))
+		asserts.Equal(ok[basics.Int](42), DecodeString(At(list.FromSlice([]s.String{"person", "age"}), Int), s.String(json)))
+	})
+	t.Run("Index", func(t *testing.T) {
+		json := s.String(`[ "alice", "bob", "chuck" ]`)
+
+		asserts.Equal(ok[s.String]("alice"), DecodeString(Index(0, String), json))
+		asserts.Equal(ok[s.String]("chuck"), DecodeString(Index(2, String), json))
+		asserts.Equal(
+			"Problem with the given value:\n\n[\n        \"alice\",\n        \"bob\",\n        \"chuck\"\n    ]\n\nExpecting a LONGER array. Need index 3 but only see 3 entries",
+			string(errorString(DecodeString(Index(3, String), json))),
+		)
+	})
+}
+
+func TestInconsistentStructure(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Maybe", func(t *testing.T) {
+		json := s.String(`{ "name": "tom", "age": 42 }`)
+
+		asserts.Equal(ok[maybe.Maybe[basics.Int]](maybe.Just[basics.Int]{Value: 42}), DecodeString(Maybe(Field("age", Int)), json))
+		asserts.Equal(ok[maybe.Maybe[basics.Int]](maybe.Nothing{}), DecodeString(Maybe(Field("name", Int)), json))
+		asserts.Equal(ok[maybe.Maybe[basics.Int]](maybe.Nothing{}), DecodeString(Maybe(Field("height", Int)), json))
+		asserts.Equal(ok[maybe.Maybe[basics.Int]](maybe.Just[basics.Int]{Value: 42}), DecodeString(Field("age", Maybe(Int)), json))
+		asserts.Equal(ok[maybe.Maybe[basics.Int]](maybe.Nothing{}), DecodeString(Field("name", Maybe(Int)), json))
+		asserts.IsType(result.Err[Error, maybe.Maybe[basics.Int]]{}, DecodeString(Field("height", Maybe(Int)), json))
+	})
+	t.Run("OneOf", func(t *testing.T) {
+		badInt := OneOf(list.FromSlice([]Decoder[basics.Int]{Int, Null[basics.Int](0)}))
+
+		asserts.Equal(ok(list.FromSlice([]basics.Int{1, 2, 0, 4})), DecodeString(List(badInt), "[1,2,null,4]"))
+		asserts.Equal(
+			result.Err[Error, basics.Int]{Err: OneOfError{Errors: list.FromSlice([]Error{
+				Failure{Message: "Expecting an INT", Value: internal.Bool(true)},
+				Failure{Message: "Expecting null", Value: internal.Bool(true)},
+			})}},
+			DecodeString(badInt, "true"),
+		)
+	})
+}
+
+func TestMapping(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Map", func(t *testing.T) {
+		asserts.Equal(ok[basics.Int](5), DecodeString(Map(s.Length, String), `"hello"`))
+	})
+	t.Run("Map2", func(t *testing.T) {
+		decoder := Map2(
+			func(name s.String, age basics.Int) person { return person{name, age} },
+			Field("name", String),
+			Field("age", Int),
+		)
+
+		asserts.Equal(ok(person{"tom", 42}), DecodeString(decoder, `{ "name": "tom", "age": 42 }`))
+		asserts.Equal(
+			result.Err[Error, person]{Err: Failure{
+				Message: "Expecting an OBJECT with a field named `age`",
+				Value:   internal.Object{{Key: "name", Value: internal.String("tom")}},
+			}},
+			DecodeString(decoder, `{ "name": "tom" }`),
+		)
+	})
+	t.Run("Map8", func(t *testing.T) {
+		sum := func(a, b, c, d, e, f, g, h basics.Int) basics.Int { return a + b + c + d + e + f + g + h }
+		decoder := Map8(sum, Index(0, Int), Index(1, Int), Index(2, Int), Index(3, Int), Index(4, Int), Index(5, Int), Index(6, Int), Index(7, Int))
+
+		asserts.Equal(ok[basics.Int](36), DecodeString(decoder, "[1,2,3,4,5,6,7,8]"))
+	})
+}
+
+type comment struct {
+	message   s.String
+	responses list.List[comment]
+}
+
+func TestFancyDecoding(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Succeed", func(t *testing.T) {
+		asserts.Equal(ok[basics.Int](42), DecodeString(Succeed[basics.Int](42), "true"))
+		asserts.Equal(ok[basics.Int](42), DecodeString(Succeed[basics.Int](42), "[1,2,3]"))
+	})
+	t.Run("Fail", func(t *testing.T) {
+		asserts.Equal(
+			result.Err[Error, basics.Int]{Err: Failure{Message: "nope", Value: internal.Bool(true)}},
+			DecodeString(Fail[basics.Int]("nope"), "true"),
+		)
+	})
+	t.Run("AndThen", func(t *testing.T) {
+		info := func(version basics.Int) Decoder[s.String] {
+			switch version {
+			case 4:
+				return Field("info", String)
+			case 3:
+				return Field("data", String)
+			default:
+				return Fail[s.String]("Trying to decode info, but version " + s.FromInt(version) + " is not supported.")
+			}
+		}
+		decoder := AndThen(info, Field("version", Int))
+
+		asserts.Equal(ok[s.String]("yes"), DecodeString(decoder, `{ "version": 4, "info": "yes" }`))
+		asserts.Equal(ok[s.String]("old"), DecodeString(decoder, `{ "version": 3, "data": "old" }`))
+		asserts.IsType(result.Err[Error, s.String]{}, DecodeString(decoder, `{ "version": 1 }`))
+	})
+	t.Run("Lazy", func(t *testing.T) {
+		var decoder func() Decoder[comment]
+		decoder = func() Decoder[comment] {
+			return Map2(
+				func(m s.String, r list.List[comment]) comment { return comment{m, r} },
+				Field("message", String),
+				Field("responses", List(Lazy(decoder))),
+			)
+		}
+		SUT := DecodeString(decoder(), `{ "message": "hi", "responses": [ { "message": "hey", "responses": [] } ] }`)
+
+		asserts.Equal(
+			ok(comment{"hi", list.Singleton(comment{"hey", list.Empty[comment]()})}),
+			SUT,
+		)
+	})
+}
+
+func TestErrorToString(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Failure at the root", func(t *testing.T) {
+		asserts.Equal(
+			s.String("Problem with the given value:\n\ntrue\n\nExpecting an INT"),
+			errorString(DecodeString(Int, "true")),
+		)
+	})
+	t.Run("Failure in a nested value", func(t *testing.T) {
+		SUT := DecodeString(Field("people", List(Field("first name", String))), `{ "people": [ { "first name": 3 } ] }`)
+
+		asserts.Equal(
+			s.String("Problem with the value at json.people[0]['first name']:\n\n    3\n\nExpecting a STRING"),
+			errorString(SUT),
+		)
+	})
+	t.Run("Failure with an object value", func(t *testing.T) {
+		SUT := DecodeString(Field("user", Field("id", Int)), `{ "user": { "name": "tom" } }`)
+
+		asserts.Equal(
+			s.String("Problem with the value at json.user:\n\n    {\n        \"name\": \"tom\"\n    }\n\nExpecting an OBJECT with a field named `id`"),
+			errorString(SUT),
+		)
+	})
+	t.Run("OneOf", func(t *testing.T) {
+		decoder := Field("x", OneOf(list.FromSlice([]Decoder[basics.Int]{Int, Null[basics.Int](0)})))
+
+		asserts.Equal(
+			s.String("The Json.Decode.oneOf at json.x failed in the following 2 ways:\n\n\n\n(1) Problem with the given value:\n    \n    true\n    \n    Expecting an INT\n\n\n\n(2) Problem with the given value:\n    \n    true\n    \n    Expecting null"),
+			errorString(DecodeString(decoder, `{ "x": true }`)),
+		)
+	})
+	t.Run("OneOf with no possibilities", func(t *testing.T) {
+		asserts.Equal(
+			s.String("Ran into a Json.Decode.oneOf with no possibilities!"),
+			errorString(DecodeString(OneOf(list.Empty[Decoder[basics.Int]]()), "1")),
+		)
+	})
+}
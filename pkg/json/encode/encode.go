@@ -0,0 +1,119 @@
+// Package encode turns Go values into JSON values, inspired by the Elm Json.Encode module.
+//
+// The output is deterministic. Objects keep the order their fields were given in,
+// and dictionaries and sets are written from lowest key to highest key.
+package encode
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/array"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/dict"
+	"github.com/Confidenceman02/scion-tools/pkg/json/internal"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/set"
+	s "github.com/Confidenceman02/scion-tools/pkg/string"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Represents a JavaScript value.
+// This is the same type as decode.Value so anything you encode can be decoded again.
+type Value = internal.Value
+
+// Convert a Value into a prettified string. The first argument specifies the amount of indentation in the resulting string.
+func Encode(indent basics.Int, v Value) s.String {
+	return s.String(internal.Encode(int(indent), v))
+}
+
+// PRIMITIVES
+
+// Turn a String into a JSON string.
+func String(str s.String) Value {
+	return internal.String(str)
+}
+
+// Turn an Int into a JSON number.
+func Int(i basics.Int) Value {
+	return internal.Number(strconv.FormatInt(int64(i), 10))
+}
+
+// Turn a Float into a JSON number.
+// Just like JSON.stringify, NaN and infinite values are encoded as null.
+func Float(f basics.Float) Value {
+	x := float64(f)
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return internal.Null{}
+	}
+	if x == 0 {
+		// Negative zero is written as 0
+		return internal.Number("0")
+	}
+	abs := math.Abs(x)
+	if abs < 1e-6 || abs >= 1e21 {
+		return internal.Number(trimExponent(strconv.FormatFloat(x, 'e', -1, 32)))
+	}
+	return internal.Number(strconv.FormatFloat(x, 'f', -1, 32))
+}
+
+// Go pads exponents to two digits, JavaScript does not. 1e-07 becomes 1e-7.
+func trimExponent(str string) string {
+	mantissa, exponent, _ := strings.Cut(str, "e")
+	sign, digits := exponent[:1], strings.TrimLeft(exponent[1:], "0")
+	return mantissa + "e" + sign + digits
+}
+
+// Turn a bool into a JSON boolean.
+func Bool(b bool) Value {
+	return internal.Bool(b)
+}
+
+// Create a JSON null value.
+var Null Value = internal.Null{}
+
+// ARRAYS
+
+// Turn a List into a JSON array.
+func List[T any](f func(T) Value, xs list.List[T]) Value {
+	return internal.Array(list.ToSliceMap(f, xs))
+}
+
+// Turn an Array into a JSON array.
+func Array[T any](f func(T) Value, arr array.Array[T]) Value {
+	return internal.Array(
+		array.Foldl(func(x T, acc internal.Array) internal.Array { return append(acc, f(x)) }, internal.Array{}, arr),
+	)
+}
+
+// Turn a Set into a JSON array, from lowest to highest.
+func Set[T basics.Comparable[T]](f func(T) Value, st set.Set[T]) Value {
+	return internal.Array(
+		set.Foldl(func(x T, acc internal.Array) internal.Array { return append(acc, f(x)) }, internal.Array{}, st),
+	)
+}
+
+// OBJECTS
+
+// Create a JSON object. Fields are written in the order they are given.
+// When a field name appears more than once the last value wins, just like a JavaScript object.
+func Object(pairs list.List[tuple.Tuple2[s.String, Value]]) Value {
+	var obj internal.ObjectBuilder
+	for t := range list.Values(pairs) {
+		obj.Set(string(tuple.First(t)), tuple.Second(t))
+	}
+	return obj.Object()
+}
+
+// Turn a Dict into a JSON object. Fields are written from lowest key to highest key.
+// Each key is written once, so toKey should not turn two keys into the same string.
+func Dict[K basics.Comparable[K], V any](toKey func(K) s.String, toValue func(V) Value, d dict.Dict[K, V]) Value {
+	return dict.Foldl(
+		// Dict keys are unique, so there are no duplicate fields to look for
+		func(k K, v V, obj internal.Object) internal.Object {
+			return append(obj, internal.Member{Key: string(toKey(k)), Value: toValue(v)})
+		},
+		internal.Object{},
+		d,
+	)
+}
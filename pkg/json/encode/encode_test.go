@@ -0,0 +1,171 @@
+package encode
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/array"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/dict"
+	"github.com/Confidenceman02/scion-tools/pkg/json/decode"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/result"
+	"github.com/Confidenceman02/scion-tools/pkg/set"
+	s "github.com/Confidenceman02/scion-tools/pkg/string"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestPrimitives(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("String", func(t *testing.T) {
+		asserts.Equal(s.String(`"hello"`), Encode(0, String("hello")))
+		asserts.Equal(s.String(`"quote \" and <tag>"`), Encode(0, String(`quote " and <tag>`)))
+	})
+	t.Run("Int", func(t *testing.T) {
+		asserts.Equal(s.String("42"), Encode(0, Int(42)))
+		asserts.Equal(s.String("-7"), Encode(0, Int(-7)))
+	})
+	t.Run("Float", func(t *testing.T) {
+		asserts.Equal(s.String("3.14"), Encode(0, Float(3.14)))
+		asserts.Equal(s.String("1.5"), Encode(0, Float(1.5)))
+		asserts.Equal(s.String("100"), Encode(0, Float(100)))
+		asserts.Equal(s.String("0"), Encode(0, Float(basics.Float(math.Copysign(0, -1)))))
+		asserts.Equal(s.String("1e-7"), Encode(0, Float(1e-7)))
+		asserts.Equal(s.String("1e+21"), Encode(0, Float(1e21)))
+		asserts.Equal(s.String("null"), Encode(0, Float(basics.Float(math.NaN()))))
+		asserts.Equal(s.String("null"), Encode(0, Float(basics.Float(math.Inf(1)))))
+	})
+	t.Run("Bool", func(t *testing.T) {
+		asserts.Equal(s.String("true"), Encode(0, Bool(true)))
+		asserts.Equal(s.String("false"), Encode(0, Bool(false)))
+	})
+	t.Run("Null", func(t *testing.T) {
+		asserts.Equal(s.String("null"), Encode(0, Null))
+	})
+}
+
+func TestArrays(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("List", func(t *testing.T) {
+		asserts.Equal(s.String("[1,3,4]"), Encode(0, List(Int, list.FromSlice([]basics.Int{1, 3, 4}))))
+		asserts.Equal(s.String("[]"), Encode(0, List(Int, list.Empty[basics.Int]())))
+	})
+	t.Run("Array", func(t *testing.T) {
+		asserts.Equal(
+			s.String("[true,false]"),
+			Encode(0, Array(Bool, array.FromList(list.FromSlice([]bool{true, false})))),
+		)
+	})
+	t.Run("Set", func(t *testing.T) {
+		asserts.Equal(s.String("[1,2,3]"), Encode(0, Set(Int, set.FromList(list.FromSlice([]basics.Int{3, 1, 2, 1})))))
+	})
+}
+
+func TestObjects(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Object keeps field order", func(t *testing.T) {
+		SUT := Object(list.FromSlice([]tuple.Tuple2[s.String, Value]{
+			tuple.Pair[s.String, Value]("name", String("Tom")),
+			tuple.Pair[s.String, Value]("age", Int(42)),
+		}))
+
+		asserts.Equal(s.String(`{"name":"Tom","age":42}`), Encode(0, SUT))
+	})
+	t.Run("Object duplicate fields", func(t *testing.T) {
+		SUT := Object(list.FromSlice([]tuple.Tuple2[s.String, Value]{
+			tuple.Pair[s.String, Value]("a", Int(1)),
+			tuple.Pair[s.String, Value]("b", Int(2)),
+			tuple.Pair[s.String, Value]("a", Int(3)),
+		}))
+
+		asserts.Equal(s.String(`{"a":3,"b":2}`), Encode(0, SUT))
+	})
+	t.Run("Dict is written in key order", func(t *testing.T) {
+		d := dict.FromList(list.FromSlice([]tuple.Tuple2[basics.Int, s.String]{
+			tuple.Pair[basics.Int, s.String](3, "c"),
+			tuple.Pair[basics.Int, s.String](1, "a"),
+			tuple.Pair[basics.Int, s.String](2, "b"),
+		}))
+
+		asserts.Equal(s.String(`{"1":"a","2":"b","3":"c"}`), Encode(0, Dict(s.FromInt, String, d)))
+	})
+	t.Run("Dict output is deterministic", func(t *testing.T) {
+		d1 := dict.Empty[s.String, basics.Int]()
+		d2 := dict.Empty[s.String, basics.Int]()
+		for i := basics.Int(0); i < 100; i++ {
+			d1 = dict.Insert(s.FromInt(i), i, d1)
+			d2 = dict.Insert(s.FromInt(99-i), 99-i, d2)
+		}
+
+		asserts.Equal(Encode(0, Dict(basics.Identity[s.String], Int, d1)), Encode(0, Dict(basics.Identity[s.String], Int, d2)))
+	})
+	t.Run("Large Object and Dict", func(t *testing.T) {
+		pairs := largePairs(100_000)
+		d := dict.FromList(pairs)
+		fields := list.Map(func(p tuple.Tuple2[basics.Int, Value]) tuple.Tuple2[s.String, Value] {
+			return tuple.MapFirst(s.FromInt, p)
+		}, pairs)
+
+		asserts.Equal(
+			Encode(0, Object(fields)),
+			Encode(0, Dict(s.FromInt, basics.Identity[Value], d)),
+		)
+	})
+}
+
+func largePairs(n basics.Int) list.List[tuple.Tuple2[basics.Int, Value]] {
+	return list.Map(func(i basics.Int) tuple.Tuple2[basics.Int, Value] { return tuple.Pair(i, Int(i)) }, list.Range(0, n-1))
+}
+
+// Objects and dicts with many fields are slow to encode if finding duplicate keys takes longer as the object grows.
+func BenchmarkObject(b *testing.B) {
+	fields := list.Map(func(p tuple.Tuple2[basics.Int, Value]) tuple.Tuple2[s.String, Value] {
+		return tuple.MapFirst(s.FromInt, p)
+	}, largePairs(10_000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Object(fields)
+	}
+}
+
+func BenchmarkDict(b *testing.B) {
+	d := dict.FromList(largePairs(10_000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Dict(s.FromInt, basics.Identity[Value], d)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	asserts := assert.New(t)
+	value := Object(list.FromSlice([]tuple.Tuple2[s.String, Value]{
+		tuple.Pair[s.String, Value]("name", String("Tom")),
+		tuple.Pair[s.String, Value]("tags", List(String, list.FromSlice([]s.String{"a", "b"}))),
+		tuple.Pair[s.String, Value]("empty", List(Int, list.Empty[basics.Int]())),
+	}))
+
+	t.Run("Compact", func(t *testing.T) {
+		asserts.Equal(s.String(`{"name":"Tom","tags":["a","b"],"empty":[]}`), Encode(0, value))
+	})
+	t.Run("Indented", func(t *testing.T) {
+		asserts.Equal(
+			s.String("{\n    \"name\": \"Tom\",\n    \"tags\": [\n        \"a\",\n        \"b\"\n    ],\n    \"empty\": []\n}"),
+			Encode(4, value),
+		)
+	})
+	t.Run("Round trip through decode", func(t *testing.T) {
+		decoder := decode.Field("tags", decode.List(decode.String))
+
+		asserts.Equal(
+			result.Ok[decode.Error, list.List[s.String]]{Val: list.FromSlice([]s.String{"a", "b"})},
+			decode.DecodeValue(decoder, value),
+		)
+		asserts.Equal(
+			result.Ok[decode.Error, list.List[s.String]]{Val: list.FromSlice([]s.String{"a", "b"})},
+			decode.DecodeString(decoder, Encode(2, value)),
+		)
+	})
+}
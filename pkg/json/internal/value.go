@@ -0,0 +1,226 @@
+// Package internal holds the JSON value representation shared by the decode and encode packages.
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Value is a parsed JSON value. Unlike the values produced by encoding/json,
+// objects remember the order of their fields.
+type Value interface {
+	value()
+}
+
+type Null struct{}
+
+type Bool bool
+
+// Number keeps the literal text of a JSON number so no precision is lost before decoding.
+type Number string
+
+type String string
+
+type Array []Value
+
+type Object []Member
+
+type Member struct {
+	Key   string
+	Value Value
+}
+
+func (Null) value()   {}
+func (Bool) value()   {}
+func (Number) value() {}
+func (String) value() {}
+func (Array) value()  {}
+func (Object) value() {}
+
+// Get the value of a field, if the object has it.
+func (o Object) Get(key string) (Value, bool) {
+	for _, m := range o {
+		if m.Key == key {
+			return m.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Parse a JSON string into a Value.
+func Parse(str string) (Value, error) {
+	dec := json.NewDecoder(strings.NewReader(str))
+	dec.UseNumber()
+
+	v, err := parseHelp(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("Unexpected data after the end of the JSON value")
+	}
+	return v, nil
+}
+
+func parseHelp(dec *json.Decoder) (Value, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("Unexpected end of JSON input")
+		}
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case nil:
+		return Null{}, nil
+	case bool:
+		return Bool(t), nil
+	case json.Number:
+		return Number(t), nil
+	case string:
+		return String(t), nil
+	case json.Delim:
+		switch t {
+		case '[':
+			arr := Array{}
+			for dec.More() {
+				v, err := parseHelp(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return arr, nil
+		case '{':
+			var obj ObjectBuilder
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				v, err := parseHelp(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj.Set(keyTok.(string), v)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return obj.Object(), nil
+		}
+	}
+	return nil, errors.New("Unexpected token in JSON")
+}
+
+// An ObjectBuilder makes an object one field at a time. It keeps the position of every key,
+// so setting a field takes the same time however many fields the object already has.
+type ObjectBuilder struct {
+	members Object
+	index   map[string]int
+}
+
+// Set a field of the object. Later duplicate keys win, but keep the position
+// of the first occurrence just like a JavaScript object.
+func (b *ObjectBuilder) Set(key string, v Value) {
+	if i, ok := b.index[key]; ok {
+		b.members[i].Value = v
+		return
+	}
+	if b.index == nil {
+		b.index = make(map[string]int)
+	}
+	b.index[key] = len(b.members)
+	b.members = append(b.members, Member{Key: key, Value: v})
+}
+
+// Get the object that has been built.
+func (b *ObjectBuilder) Object() Object {
+	if b.members == nil {
+		return Object{}
+	}
+	return b.members
+}
+
+// Encode a Value into a JSON string. Use an indent of zero for compact output,
+// otherwise every nested value is placed on a new line indented by the given amount of spaces.
+func Encode(indent int, v Value) string {
+	var sb strings.Builder
+	encodeHelp(&sb, strings.Repeat(" ", max(indent, 0)), "", v)
+	return sb.String()
+}
+
+func encodeHelp(sb *strings.Builder, indent string, prefix string, v Value) {
+	switch v := v.(type) {
+	case Null:
+		sb.WriteString("null")
+	case Bool:
+		if v {
+			sb.WriteString("true")
+		} else {
+			sb.WriteString("false")
+		}
+	case Number:
+		sb.WriteString(string(v))
+	case String:
+		sb.WriteString(Quote(string(v)))
+	case Array:
+		if len(v) == 0 {
+			sb.WriteString("[]")
+			return
+		}
+		sb.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			newline(sb, indent, prefix+indent)
+			encodeHelp(sb, indent, prefix+indent, item)
+		}
+		newline(sb, indent, prefix)
+		sb.WriteByte(']')
+	case Object:
+		if len(v) == 0 {
+			sb.WriteString("{}")
+			return
+		}
+		sb.WriteByte('{')
+		for i, m := range v {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			newline(sb, indent, prefix+indent)
+			sb.WriteString(Quote(m.Key))
+			sb.WriteByte(':')
+			if indent != "" {
+				sb.WriteByte(' ')
+			}
+			encodeHelp(sb, indent, prefix+indent, m.Value)
+		}
+		newline(sb, indent, prefix)
+		sb.WriteByte('}')
+	}
+}
+
+func newline(sb *strings.Builder, indent string, prefix string) {
+	if indent != "" {
+		sb.WriteByte('\n')
+		sb.WriteString(prefix)
+	}
+}
+
+// Quote a string as a JSON string literal.
+func Quote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	// Encoding a string never fails
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,78 @@
+package list
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/internal"
+)
+
+// Lists made by FromSlice, Map and Filter keep their cons cells in blocks of contiguous memory.
+//
+// A block holds n cells that are allocated together and linked in order, so a list of a million
+// elements costs two allocations instead of two million, and walking it reads memory in order
+// the way walking a slice does. The cells are ordinary cons cells, so a list made from a block
+// looks and behaves exactly like one built with Cons, and consing onto it or taking its tail
+// shares the block as usual.
+//
+// A block is only freed once none of its cells are reachable, so holding on to the tail of a
+// long list keeps the whole block alive.
+func block[T any](n int, rest List[T]) []list[T] {
+	cells := make([]internal.Cons_[T, List[T]], n)
+	nodes := make([]list[T], n)
+	for i := range nodes {
+		nodes[i].Cons_ = &cells[i]
+		if i+1 < n {
+			cells[i].B = &nodes[i+1]
+		} else {
+			cells[i].B = rest
+		}
+	}
+	return nodes
+}
+
+// Make a list from the elements of a slice, copying them into a block.
+func fromBlock[T any](arr []T, rest List[T]) List[T] {
+	if len(arr) == 0 {
+		return rest
+	}
+	nodes := block(len(arr), rest)
+	for i, x := range arr {
+		nodes[i].A = x
+	}
+	return &nodes[0]
+}
+
+// Count the cells of a list.
+func length[T any](xs List[T]) int {
+	n := 0
+	for c := xs.Cons(); c != nil; c = c.B.Cons() {
+		n++
+	}
+	return n
+}
+
+// Apply a function to every element of a list, writing the results into one block.
+// The list is walked twice, once to count its cells and once to map them, which is still
+// cheaper than allocating every cell on its own.
+func mapBlock[A, B any](f func(A) B, xs List[A]) List[B] {
+	n := length(xs)
+	if n == 0 {
+		return Empty[B]()
+	}
+	nodes := block(n, Empty[B]())
+	i := 0
+	for c := xs.Cons(); c != nil; c = c.B.Cons() {
+		nodes[i].A = f(c.A)
+		i++
+	}
+	return &nodes[0]
+}
+
+// Keep the elements that pass the test, writing them into one block.
+func filterBlock[T any](isGood func(T) bool, xs List[T]) List[T] {
+	var kept []T
+	for c := xs.Cons(); c != nil; c = c.B.Cons() {
+		if isGood(c.A) {
+			kept = append(kept, c.A)
+		}
+	}
+	return fromBlock(kept, Empty[T]())
+}
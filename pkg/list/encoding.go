@@ -0,0 +1,93 @@
+package list
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"github.com/Confidenceman02/scion-tools/pkg/internal"
+)
+
+// A list is encoded as a JSON array.
+func (c *list[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ToSlice[T](c))
+}
+
+// An empty list is encoded as an empty JSON array.
+func (c empty[T]) MarshalJSON() ([]byte, error) {
+	return []byte("[]"), nil
+}
+
+// Decode a list from a JSON array.
+// Lists, dicts, sets, maybes, results and tuples inside the list need their own Register call.
+func FromJSON[T any](data []byte) (List[T], error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	b := NewBuilder[T]()
+	for _, r := range raw {
+		x, err := internal.UnmarshalJSON[T](r)
+		if err != nil {
+			return nil, err
+		}
+		b.Append(x)
+	}
+	return b.Freeze(), nil
+}
+
+// Register sets up List[T] to be decoded inside other values and sent through encoding/gob.
+func Register[T any]() {
+	gob.Register(&list[T]{})
+	gob.Register(empty[T]{})
+	internal.RegisterJSON(FromJSON[T])
+}
+
+// A Field holds a List as a struct field that encoding/json can decode.
+// A Field with no List is encoded as an empty list.
+//
+//	type Post struct {
+//		Tags list.Field[string]
+//	}
+type Field[T any] struct {
+	List List[T]
+}
+
+func (f Field[T]) MarshalJSON() ([]byte, error) {
+	if f.List == nil {
+		return json.Marshal(Empty[T]())
+	}
+	return json.Marshal(f.List)
+}
+
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	xs, err := FromJSON[T](data)
+	if err != nil {
+		return err
+	}
+	f.List = xs
+	return nil
+}
+
+func (c *list[T]) GobEncode() ([]byte, error) {
+	return internal.GobEncode(ToSlice[T](c))
+}
+
+func (c *list[T]) GobDecode(data []byte) error {
+	var xs []T
+	if err := internal.GobDecode(data, &xs); err != nil {
+		return err
+	}
+	if len(xs) == 0 {
+		return errors.New("list: cannot decode an empty list into a non-empty one")
+	}
+	*c = *FromSlice(xs).(*list[T])
+	return nil
+}
+
+func (c empty[T]) GobEncode() ([]byte, error) {
+	return []byte{}, nil
+}
+
+func (c *empty[T]) GobDecode(data []byte) error {
+	return nil
+}
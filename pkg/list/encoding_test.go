@@ -0,0 +1,97 @@
+package list
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type payload struct {
+	Items List[basics.Int]
+}
+
+func TestJSON(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Marshal", func(t *testing.T) {
+		SUT, err := json.Marshal(payload{Items: Range(1, 3)})
+
+		asserts.NoError(err)
+		asserts.Equal(`{"Items":[1,2,3]}`, string(SUT))
+	})
+	t.Run("Marshal an empty list", func(t *testing.T) {
+		SUT, err := json.Marshal(Empty[basics.Int]())
+
+		asserts.NoError(err)
+		asserts.Equal(`[]`, string(SUT))
+	})
+	t.Run("FromJSON", func(t *testing.T) {
+		SUT, err := FromJSON[basics.Int]([]byte(`[1, 2, 3]`))
+
+		asserts.NoError(err)
+		asserts.Equal(Range(1, 3), SUT)
+	})
+	t.Run("FromJSON with an empty array", func(t *testing.T) {
+		SUT, err := FromJSON[basics.Int]([]byte(`[]`))
+
+		asserts.NoError(err)
+		asserts.Equal(Empty[basics.Int](), SUT)
+	})
+	t.Run("FromJSON with invalid JSON", func(t *testing.T) {
+		_, err1 := FromJSON[basics.Int]([]byte(`{"a": 1}`))
+		_, err2 := FromJSON[basics.Int]([]byte(`[1, "two"]`))
+
+		asserts.Error(err1)
+		asserts.Error(err2)
+	})
+	t.Run("Round trip nested lists", func(t *testing.T) {
+		Register[basics.Int]()
+		xs := FromSlice([]List[basics.Int]{Range(1, 2), Empty[basics.Int](), Singleton[basics.Int](3)})
+		data, err := json.Marshal(xs)
+		asserts.NoError(err)
+
+		SUT, err := FromJSON[List[basics.Int]](data)
+
+		asserts.NoError(err)
+		asserts.Equal(`[[1,2],[],[3]]`, string(data))
+		asserts.Equal(xs, SUT)
+	})
+	t.Run("Round trip a struct with a Field", func(t *testing.T) {
+		type post struct {
+			Tags  Field[string]
+			Empty Field[string]
+		}
+		data, err := json.Marshal(post{Tags: Field[string]{List: FromSlice([]string{"go", "elm"})}})
+		asserts.NoError(err)
+		asserts.Equal(`{"Tags":["go","elm"],"Empty":[]}`, string(data))
+
+		var SUT post
+		asserts.NoError(json.Unmarshal(data, &SUT))
+
+		asserts.Equal([]string{"go", "elm"}, ToSlice(SUT.Tags.List))
+		asserts.Equal(Empty[string](), SUT.Empty.List)
+	})
+}
+
+func TestGob(t *testing.T) {
+	asserts := assert.New(t)
+	Register[basics.Int]()
+
+	roundTrip := func(p payload) payload {
+		var buf bytes.Buffer
+		asserts.NoError(gob.NewEncoder(&buf).Encode(p))
+		var decoded payload
+		asserts.NoError(gob.NewDecoder(&buf).Decode(&decoded))
+		return decoded
+	}
+
+	t.Run("Round trip", func(t *testing.T) {
+		asserts.Equal(payload{Items: Range(1, 3)}, roundTrip(payload{Items: Range(1, 3)}))
+	})
+	t.Run("Round trip an empty list", func(t *testing.T) {
+		asserts.Equal(payload{Items: Empty[basics.Int]()}, roundTrip(payload{Items: Empty[basics.Int]()}))
+	})
+}
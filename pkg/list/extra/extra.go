@@ -0,0 +1,363 @@
+// Package extra has convenience functions for working with lists, in the spirit of elm-community/list-extra.
+// They are built on the folds of the list package and loops, so they are safe to use on long lists.
+package extra
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"slices"
+)
+
+// Numeric types are the number types of basics and every Go number type, and any type based on them.
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Basics
+
+// Extract the last element of a list.
+func Last[A any](xs list.List[A]) maybe.Maybe[A] {
+	return list.Foldl(
+		func(x A, _ maybe.Maybe[A]) maybe.Maybe[A] { return maybe.Just[A]{Value: x} },
+		maybe.Maybe[A](maybe.Nothing{}),
+		xs,
+	)
+}
+
+// Get the sum of the list elements, for any Numeric type.
+func Sum[T Numeric](xs list.List[T]) T {
+	return list.Foldl(func(x T, acc T) T { return acc + x }, 0, xs)
+}
+
+// Get the product of the list elements, for any Numeric type.
+func Product[T Numeric](xs list.List[T]) T {
+	return list.Foldl(func(x T, acc T) T { return acc * x }, 1, xs)
+}
+
+// Find the first element that has the largest value of the given function.
+func MaximumBy[A any, B basics.Comparable[B]](f func(A) B, xs list.List[A]) maybe.Maybe[A] {
+	return extremumBy(f, 1, xs)
+}
+
+// Find the first element that has the smallest value of the given function.
+func MinimumBy[A any, B basics.Comparable[B]](f func(A) B, xs list.List[A]) maybe.Maybe[A] {
+	return extremumBy(f, -1, xs)
+}
+
+// Keep the element whose f value compares with the best one so far as ord, starting from the first element.
+func extremumBy[A any, B basics.Comparable[B]](f func(A) B, ord int, xs list.List[A]) maybe.Maybe[A] {
+	return list.ListWith(
+		xs,
+		func(list.List[A]) maybe.Maybe[A] { return maybe.Nothing{} },
+		func(x A, rest list.List[A]) maybe.Maybe[A] {
+			best := list.Foldl(
+				func(y A, acc tuple.Tuple2[A, B]) tuple.Tuple2[A, B] {
+					fy := f(y)
+					if fy.Cmp(tuple.Second(acc))*ord > 0 {
+						return tuple.Pair(y, fy)
+					}
+					return acc
+				},
+				tuple.Pair(x, f(x)),
+				rest,
+			)
+			return maybe.Just[A]{Value: tuple.First(best)}
+		},
+	)
+}
+
+// Find the first element that satisfies the test.
+func Find[A any](pred func(A) bool, xs list.List[A]) maybe.Maybe[A] {
+	for ; xs.Cons() != nil; xs = xs.Cons().B {
+		if pred(xs.Cons().A) {
+			return maybe.Just[A]{Value: xs.Cons().A}
+		}
+	}
+	return maybe.Nothing{}
+}
+
+// Find the index of the first element that satisfies the test.
+func FindIndex[A any](pred func(A) bool, xs list.List[A]) maybe.Maybe[basics.Int] {
+	for i := basics.Int(0); xs.Cons() != nil; i, xs = i+1, xs.Cons().B {
+		if pred(xs.Cons().A) {
+			return maybe.Just[basics.Int]{Value: i}
+		}
+	}
+	return maybe.Nothing{}
+}
+
+// Find the index of the first element that is the same as the given value, compared with basics.Eq.
+func ElemIndex[A any](x A, xs list.List[A]) maybe.Maybe[basics.Int] {
+	return FindIndex(func(y A) bool { return basics.Eq(x, y) }, xs)
+}
+
+// Remove duplicate values, keeping the first instance of each element.
+// Values are compared with basics.Eq, and found with basics.Hash, so this takes O(n) time.
+// Values that are Equatable but not Hashable cannot be hashed, so they are compared with every
+// value kept so far, which takes O(n²) time.
+func Unique[A any](xs list.List[A]) list.List[A] {
+	seen := map[uint64][]A{}
+	var unhashable []A
+	kept := list.Foldl(
+		func(x A, acc list.List[A]) list.List[A] {
+			if !canHash(x) {
+				for _, y := range unhashable {
+					if basics.Eq(x, y) {
+						return acc
+					}
+				}
+				unhashable = append(unhashable, x)
+				return list.Cons(x, acc)
+			}
+			h := basics.Hash(x)
+			for _, y := range seen[h] {
+				if basics.Eq(x, y) {
+					return acc
+				}
+			}
+			seen[h] = append(seen[h], x)
+			return list.Cons(x, acc)
+		},
+		list.Empty[A](),
+		xs,
+	)
+	return list.Reverse(kept)
+}
+
+// Determine if basics.Hash agrees with basics.Eq for a value. It does unless the value decides
+// for itself when it is equal without saying how to hash itself.
+func canHash[A any](x A) bool {
+	_, equatable := any(x).(basics.Equatable[A])
+	_, hashable := any(x).(basics.Hashable)
+	return hashable || !equatable
+}
+
+// Indexed
+
+// Reduce a list from the left, with the index of each element (starting at zero).
+func IndexedFoldl[A, B any](f func(basics.Int, A, B) B, acc B, xs list.List[A]) B {
+	var i basics.Int
+	return list.Foldl(
+		func(x A, acc B) B {
+			acc = f(i, x, acc)
+			i++
+			return acc
+		},
+		acc,
+		xs,
+	)
+}
+
+// Reduce a list from the right, with the index of each element (starting at zero).
+func IndexedFoldr[A, B any](f func(basics.Int, A, B) B, acc B, xs list.List[A]) B {
+	i := list.Length(xs)
+	return list.Foldr(
+		func(x A, acc B) B {
+			i--
+			return f(i, x, acc)
+		},
+		acc,
+		xs,
+	)
+}
+
+// Keep elements that satisfy a test that is also given the index of each element (starting at zero).
+func IndexedFilter[A any](isGood func(basics.Int, A) bool, xs list.List[A]) list.List[A] {
+	return IndexedFoldr(
+		func(i basics.Int, x A, acc list.List[A]) list.List[A] {
+			if isGood(i, x) {
+				return list.Cons(x, acc)
+			}
+			return acc
+		},
+		list.Empty[A](),
+		xs,
+	)
+}
+
+// Same as list.FilterMap but the function is also given the index of each element (starting at zero).
+func IndexedFilterMap[A, B any](f func(basics.Int, A) maybe.Maybe[B], xs list.List[A]) list.List[B] {
+	return IndexedFoldr(
+		func(i basics.Int, x A, acc list.List[B]) list.List[B] {
+			return maybe.MaybeWith(
+				f(i, x),
+				func(j maybe.Just[B]) list.List[B] { return list.Cons(j.Value, acc) },
+				func(maybe.Nothing) list.List[B] { return acc },
+			)
+		},
+		list.Empty[B](),
+		xs,
+	)
+}
+
+// Combine
+
+// Combine two lists into a list of pairs. If one list is longer, the extra elements are dropped.
+func Zip[A, B any](xs list.List[A], ys list.List[B]) list.List[tuple.Tuple2[A, B]] {
+	return list.Map2(tuple.Pair[A, B], xs, ys)
+}
+
+// Combine three lists into a list of triples. If one list is longer, the extra elements are dropped.
+func Zip3[A, B, C any](xs list.List[A], ys list.List[B], zs list.List[C]) list.List[tuple.Tuple3[A, B, C]] {
+	return list.Map3(tuple.Triple[A, B, C], xs, ys, zs)
+}
+
+// Decompose a list of triples into a triple of lists.
+func Unzip3[A, B, C any](triples list.List[tuple.Tuple3[A, B, C]]) tuple.Tuple3[list.List[A], list.List[B], list.List[C]] {
+	return list.Foldr(
+		func(t tuple.Tuple3[A, B, C], acc tuple.Tuple3[list.List[A], list.List[B], list.List[C]]) tuple.Tuple3[list.List[A], list.List[B], list.List[C]] {
+			return tuple.Triple(
+				list.Cons(tuple.First3(t), tuple.First3(acc)),
+				list.Cons(tuple.Second3(t), tuple.Second3(acc)),
+				list.Cons(tuple.Third(t), tuple.Third(acc)),
+			)
+		},
+		tuple.Triple(list.Empty[A](), list.Empty[B](), list.Empty[C]()),
+		triples,
+	)
+}
+
+// Put a separator list between each of the lists, and concatenate the result.
+func Join[A any](sep list.List[A], xss list.List[list.List[A]]) list.List[A] {
+	return list.Concat(list.Intersperse(sep, xss))
+}
+
+// Sublists
+
+// Take the first n members of a list, and the rest of the list.
+func SplitAt[A any](n basics.Int, xs list.List[A]) tuple.Tuple2[list.List[A], list.List[A]] {
+	return tuple.Pair(list.Take(n, xs), list.Drop(n, xs))
+}
+
+// Take the longest prefix of elements that satisfy the test.
+func TakeWhile[A any](pred func(A) bool, xs list.List[A]) list.List[A] {
+	var kept []A
+	for ; xs.Cons() != nil && pred(xs.Cons().A); xs = xs.Cons().B {
+		kept = append(kept, xs.Cons().A)
+	}
+	return list.FromSlice(kept)
+}
+
+// Drop the longest prefix of elements that satisfy the test.
+func DropWhile[A any](pred func(A) bool, xs list.List[A]) list.List[A] {
+	for xs.Cons() != nil && pred(xs.Cons().A) {
+		xs = xs.Cons().B
+	}
+	return xs
+}
+
+// Take the longest prefix of elements that satisfy the test, and the rest of the list.
+func Span[A any](pred func(A) bool, xs list.List[A]) tuple.Tuple2[list.List[A], list.List[A]] {
+	return tuple.Pair(TakeWhile(pred, xs), DropWhile(pred, xs))
+}
+
+// Group adjacent elements together, starting a new group each time the test does not hold
+// for an element and the one after it.
+func GroupBy[A any](f func(A, A) bool, xs list.List[A]) list.List[list.List[A]] {
+	return list.Foldr(
+		func(x A, groups list.List[list.List[A]]) list.List[list.List[A]] {
+			return list.ListWith(
+				groups,
+				func(list.List[list.List[A]]) list.List[list.List[A]] { return list.Singleton(list.Singleton(x)) },
+				func(group list.List[A], rest list.List[list.List[A]]) list.List[list.List[A]] {
+					if f(x, group.Cons().A) {
+						return list.Cons(list.Cons(x, group), rest)
+					}
+					return list.Cons(list.Singleton(x), groups)
+				},
+			)
+		},
+		list.Empty[list.List[A]](),
+		xs,
+	)
+}
+
+// Split a list into chunks of n elements. The last chunk has the elements left over, so it can be shorter.
+// Gives an empty list when n is less than 1.
+func Chunks[A any](n basics.Int, xs list.List[A]) list.List[list.List[A]] {
+	if n < 1 {
+		return list.Empty[list.List[A]]()
+	}
+	return list.FromSliceMap(list.FromSlice[A], slices.Collect(slices.Chunk(list.ToSlice(xs), int(n))))
+}
+
+// Get every window of size elements, moving step elements between windows.
+// Windows that would run past the end of the list are dropped.
+// Gives an empty list when size or step is less than 1.
+func Sliding[A any](size basics.Int, step basics.Int, xs list.List[A]) list.List[list.List[A]] {
+	if size < 1 || step < 1 {
+		return list.Empty[list.List[A]]()
+	}
+	slc := list.ToSlice(xs)
+	var windows [][]A
+	for i := 0; i+int(size) <= len(slc); i += int(step) {
+		windows = append(windows, slc[i:i+int(size)])
+	}
+	return list.FromSliceMap(list.FromSlice[A], windows)
+}
+
+// Turn the rows of a list of lists into columns. Rows longer than the shortest row have their extra elements dropped.
+func Transpose[A any](xss list.List[list.List[A]]) list.List[list.List[A]] {
+	rows := list.ToSlice(xss)
+	if len(rows) == 0 {
+		return list.Empty[list.List[A]]()
+	}
+	var columns [][]A
+	for {
+		column := make([]A, len(rows))
+		for i, row := range rows {
+			if row.Cons() == nil {
+				return list.FromSliceMap(list.FromSlice[A], columns)
+			}
+			column[i] = row.Cons().A
+			rows[i] = row.Cons().B
+		}
+		columns = append(columns, column)
+	}
+}
+
+// Build
+
+// Reduce a list from the left, keeping every intermediate value, starting with the initial one.
+func Scanl[A, B any](f func(A, B) B, acc B, xs list.List[A]) list.List[B] {
+	scanned := list.Foldl(
+		func(x A, accs list.List[B]) list.List[B] {
+			return list.Cons(f(x, accs.Cons().A), accs)
+		},
+		list.Singleton(acc),
+		xs,
+	)
+	return list.Reverse(scanned)
+}
+
+// Build a list by applying a function to a value, then to its result, until it gives Nothing.
+// The list starts with the given value.
+func Iterate[A any](f func(A) maybe.Maybe[A], x A) list.List[A] {
+	xs := []A{x}
+	for {
+		next, ok := f(x).(maybe.Just[A])
+		if !ok {
+			return list.FromSlice(xs)
+		}
+		x = next.Value
+		xs = append(xs, x)
+	}
+}
+
+// Repeat the elements of a list until there are n of them.
+// Gives an empty list when the list is empty or n is less than 1.
+func Cycle[A any](n basics.Int, xs list.List[A]) list.List[A] {
+	slc := list.ToSlice(xs)
+	if n < 1 || len(slc) == 0 {
+		return list.Empty[A]()
+	}
+	cycled := make([]A, n)
+	for i := range cycled {
+		cycled[i] = slc[i%len(slc)]
+	}
+	return list.FromSlice(cycled)
+}
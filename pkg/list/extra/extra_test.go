@@ -0,0 +1,218 @@
+package extra
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func ints(xs ...int) list.List[int] {
+	return list.FromSlice(xs)
+}
+
+func lists(xss list.List[list.List[int]]) [][]int {
+	return list.ToSliceMap(list.ToSlice[int], xss)
+}
+
+func TestBasics(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Last", func(t *testing.T) {
+		asserts.Equal(maybe.Just[int]{Value: 3}, Last(ints(1, 2, 3)))
+		asserts.Equal(maybe.Nothing{}, Last(ints()))
+	})
+
+	t.Run("Sum and Product of Go and basics numbers", func(t *testing.T) {
+		asserts.Equal(6, Sum(ints(1, 2, 3)))
+		asserts.Equal(uint8(24), Product(list.FromSlice([]uint8{2, 3, 4})))
+		asserts.Equal(basics.Float64(1.5), Sum(list.FromSlice([]basics.Float64{1, 0.5})))
+		asserts.Equal(0, Sum(ints()))
+		asserts.Equal(1, Product(ints()))
+	})
+
+	t.Run("MaximumBy and MinimumBy keep the first of equal elements", func(t *testing.T) {
+		words := list.FromSlice([]string{"bb", "a", "cc", "d"})
+		length := func(s string) basics.Int { return basics.Int(len(s)) }
+
+		asserts.Equal(maybe.Just[string]{Value: "bb"}, MaximumBy(length, words))
+		asserts.Equal(maybe.Just[string]{Value: "a"}, MinimumBy(length, words))
+		asserts.Equal(maybe.Nothing{}, MaximumBy(length, list.Empty[string]()))
+	})
+
+	t.Run("Find, FindIndex and ElemIndex", func(t *testing.T) {
+		even := func(x int) bool { return x%2 == 0 }
+
+		asserts.Equal(maybe.Just[int]{Value: 4}, Find(even, ints(1, 3, 4, 6)))
+		asserts.Equal(maybe.Nothing{}, Find(even, ints(1, 3)))
+		asserts.Equal(maybe.Just[basics.Int]{Value: 2}, FindIndex(even, ints(1, 3, 4, 6)))
+		asserts.Equal(maybe.Just[basics.Int]{Value: 1}, ElemIndex(3, ints(1, 3, 3)))
+		asserts.Equal(maybe.Nothing{}, ElemIndex(5, ints(1, 3, 3)))
+	})
+
+	t.Run("Unique keeps the first of each value", func(t *testing.T) {
+		asserts.Equal([]int{3, 1, 2}, list.ToSlice(Unique(ints(3, 1, 3, 2, 1))))
+		asserts.Equal(
+			[][]int{{1}, {2}},
+			lists(Unique(list.FromSlice([]list.List[int]{ints(1), ints(2), ints(1)}))),
+		)
+	})
+	t.Run("Unique compares values that are Equatable but not Hashable with Eq", func(t *testing.T) {
+		SUT := Unique(list.FromSlice([]caseless{"a", "A", "b", "B", "a"}))
+
+		asserts.Equal([]caseless{"a", "b"}, list.ToSlice(SUT))
+	})
+}
+
+// A string that is equal to the same string in any case, but has no Hash method.
+type caseless string
+
+func (c caseless) Equal(y caseless) bool {
+	return strings.EqualFold(string(c), string(y))
+}
+
+func TestIndexed(t *testing.T) {
+	asserts := assert.New(t)
+	xs := list.FromSlice([]string{"a", "b", "c"})
+
+	t.Run("IndexedFoldl", func(t *testing.T) {
+		SUT := IndexedFoldl(func(i basics.Int, x string, acc []string) []string {
+			return append(acc, x+string(rune('0'+i)))
+		}, nil, xs)
+
+		asserts.Equal([]string{"a0", "b1", "c2"}, SUT)
+	})
+
+	t.Run("IndexedFoldr", func(t *testing.T) {
+		SUT := IndexedFoldr(func(i basics.Int, x string, acc []string) []string {
+			return append(acc, x+string(rune('0'+i)))
+		}, nil, xs)
+
+		asserts.Equal([]string{"c2", "b1", "a0"}, SUT)
+	})
+
+	t.Run("IndexedFilter", func(t *testing.T) {
+		SUT := IndexedFilter(func(i basics.Int, _ string) bool { return i != 1 }, xs)
+
+		asserts.Equal([]string{"a", "c"}, list.ToSlice(SUT))
+	})
+
+	t.Run("IndexedFilterMap", func(t *testing.T) {
+		SUT := IndexedFilterMap(func(i basics.Int, x string) maybe.Maybe[basics.Int] {
+			if x == "b" {
+				return maybe.Nothing{}
+			}
+			return maybe.Just[basics.Int]{Value: i}
+		}, xs)
+
+		asserts.Equal([]basics.Int{0, 2}, list.ToSlice(SUT))
+	})
+
+	t.Run("Long lists", func(t *testing.T) {
+		long := list.Range(1, 100000)
+		SUT := IndexedFoldr(func(i basics.Int, x basics.Int, acc basics.Int) basics.Int {
+			return acc + x - i
+		}, 0, long)
+
+		asserts.Equal(basics.Int(100000), SUT)
+	})
+}
+
+func TestCombine(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Zip drops extra elements", func(t *testing.T) {
+		SUT := Zip(ints(1, 2, 3), list.FromSlice([]string{"a", "b"}))
+
+		asserts.Equal([]tuple.Tuple2[int, string]{tuple.Pair(1, "a"), tuple.Pair(2, "b")}, list.ToSlice(SUT))
+	})
+
+	t.Run("Zip3 and Unzip3", func(t *testing.T) {
+		SUT := Unzip3(Zip3(ints(1, 2), list.FromSlice([]string{"a", "b"}), list.FromSlice([]bool{true, false})))
+
+		asserts.Equal([]int{1, 2}, list.ToSlice(tuple.First3(SUT)))
+		asserts.Equal([]string{"a", "b"}, list.ToSlice(tuple.Second3(SUT)))
+		asserts.Equal([]bool{true, false}, list.ToSlice(tuple.Third(SUT)))
+	})
+
+	t.Run("Join", func(t *testing.T) {
+		SUT := Join(ints(0), list.FromSlice([]list.List[int]{ints(1, 2), ints(3), ints(4)}))
+
+		asserts.Equal([]int{1, 2, 0, 3, 0, 4}, list.ToSlice(SUT))
+	})
+}
+
+func TestSublists(t *testing.T) {
+	asserts := assert.New(t)
+	small := func(x int) bool { return x < 3 }
+
+	t.Run("SplitAt", func(t *testing.T) {
+		SUT := SplitAt(2, ints(1, 2, 3))
+
+		asserts.Equal([]int{1, 2}, list.ToSlice(tuple.First(SUT)))
+		asserts.Equal([]int{3}, list.ToSlice(tuple.Second(SUT)))
+	})
+
+	t.Run("TakeWhile, DropWhile and Span", func(t *testing.T) {
+		xs := ints(1, 2, 3, 1)
+
+		asserts.Equal([]int{1, 2}, list.ToSlice(TakeWhile(small, xs)))
+		asserts.Equal([]int{3, 1}, list.ToSlice(DropWhile(small, xs)))
+		asserts.Equal([]int{1, 2}, list.ToSlice(tuple.First(Span(small, xs))))
+		asserts.Equal([]int{3, 1}, list.ToSlice(tuple.Second(Span(small, xs))))
+	})
+
+	t.Run("GroupBy compares adjacent elements", func(t *testing.T) {
+		SUT := GroupBy(func(a, b int) bool { return a+1 == b }, ints(1, 2, 3, 5, 6, 8))
+
+		asserts.Equal([][]int{{1, 2, 3}, {5, 6}, {8}}, lists(SUT))
+		asserts.Equal([][]int{}, lists(GroupBy(func(a, b int) bool { return true }, ints())))
+	})
+
+	t.Run("Chunks", func(t *testing.T) {
+		asserts.Equal([][]int{{1, 2}, {3, 4}, {5}}, lists(Chunks(2, ints(1, 2, 3, 4, 5))))
+		asserts.Equal([][]int{}, lists(Chunks(0, ints(1, 2))))
+	})
+
+	t.Run("Sliding", func(t *testing.T) {
+		asserts.Equal([][]int{{1, 2, 3}, {2, 3, 4}}, lists(Sliding(3, 1, ints(1, 2, 3, 4))))
+		asserts.Equal([][]int{{1, 2}, {4, 5}}, lists(Sliding(2, 3, ints(1, 2, 3, 4, 5))))
+		asserts.Equal([][]int{}, lists(Sliding(5, 1, ints(1, 2))))
+	})
+
+	t.Run("Transpose", func(t *testing.T) {
+		SUT := Transpose(list.FromSlice([]list.List[int]{ints(1, 2, 3), ints(4, 5), ints(6, 7, 8)}))
+
+		asserts.Equal([][]int{{1, 4, 6}, {2, 5, 7}}, lists(SUT))
+		asserts.Equal([][]int{}, lists(Transpose(list.Empty[list.List[int]]())))
+	})
+}
+
+func TestBuild(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Scanl", func(t *testing.T) {
+		SUT := Scanl(func(x int, acc int) int { return acc + x }, 0, ints(1, 2, 3))
+
+		asserts.Equal([]int{0, 1, 3, 6}, list.ToSlice(SUT))
+	})
+
+	t.Run("Iterate", func(t *testing.T) {
+		SUT := Iterate(func(x int) maybe.Maybe[int] {
+			if x >= 100 {
+				return maybe.Nothing{}
+			}
+			return maybe.Just[int]{Value: x * 2}
+		}, 1)
+
+		asserts.Equal([]int{1, 2, 4, 8, 16, 32, 64, 128}, list.ToSlice(SUT))
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		asserts.Equal([]int{1, 2, 3, 1, 2}, list.ToSlice(Cycle(5, ints(1, 2, 3))))
+		asserts.Equal([]int{}, list.ToSlice(Cycle(5, ints())))
+	})
+}
@@ -6,10 +6,11 @@ import (
 	"fmt"
 	"github.com/Confidenceman02/scion-tools/pkg/basics"
 	"github.com/Confidenceman02/scion-tools/pkg/internal"
+	"github.com/Confidenceman02/scion-tools/pkg/internal/hashing"
 	"github.com/Confidenceman02/scion-tools/pkg/maybe"
 	. "github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"iter"
 	"reflect"
-	"slices"
 )
 
 type List[T any] interface {
@@ -48,7 +49,7 @@ func (x *list[T]) Cmp(y basics.Comparable[List[T]]) int {
 		return +1
 	case *list[T]:
 		// traverse conses until end of a list or a mismatch
-		var ord = cmpHelp(x.A, y.A)
+		var ord = internal.CmpHelp(x.A, y.A)
 		var x1 *list[T] = x
 		var y1 *list[T] = y
 		for !IsEmpty(x1.B) && !IsEmpty(y1.B) && ord == 0 {
@@ -58,7 +59,7 @@ func (x *list[T]) Cmp(y basics.Comparable[List[T]]) int {
 				case *list[T]:
 					x1 = x2
 					y1 = y2
-					ord = cmpHelp(x2.A, y2.A)
+					ord = internal.CmpHelp(x2.A, y2.A)
 					continue
 				}
 			default:
@@ -83,28 +84,50 @@ func (x *list[T]) Cmp(y basics.Comparable[List[T]]) int {
 	}
 }
 
-func cmpHelp[T any](x T, y T) int {
-	switch x1 := any(x).(type) {
-	case List[T]:
-		switch y1 := any(y).(type) {
-		case List[T]:
-			return x1.Cmp(y1)
-		default:
-			panic("I was expecting a List[T]")
-		}
-	default:
-		return internal.CmpHelp(x, y)
-	}
-}
-
 func (x empty[T]) Cmp(y basics.Comparable[List[T]]) int {
-	if reflect.DeepEqual(x, y) {
+	if IsEmpty(y.T()) {
 		return 0
 	} else {
 		return -1
 	}
 }
 
+// Equality
+
+// Lists are equal when they have the same elements in the same order, compared with basics.Eq.
+func (x *list[T]) Equal(y List[T]) bool {
+	return equal[T](x, y)
+}
+
+func (x empty[T]) Equal(y List[T]) bool {
+	return IsEmpty(y)
+}
+
+func (x *list[T]) Hash() uint64 {
+	return hash[T](x)
+}
+
+func (x empty[T]) Hash() uint64 {
+	return hash[T](x)
+}
+
+func equal[T any](xs List[T], ys List[T]) bool {
+	for ; xs.Cons() != nil && ys.Cons() != nil; xs, ys = xs.Cons().B, ys.Cons().B {
+		if !basics.Eq(xs.Cons().A, ys.Cons().A) {
+			return false
+		}
+	}
+	return xs.Cons() == nil && ys.Cons() == nil
+}
+
+func hash[T any](xs List[T]) uint64 {
+	h := hashing.Uint64(0)
+	for ; xs.Cons() != nil; xs = xs.Cons().B {
+		h = hashing.Combine(h, basics.Hash(xs.Cons().A))
+	}
+	return h
+}
+
 // Create
 
 // Create a list with no elements
@@ -167,7 +190,7 @@ func Cons[T any](val T, l List[T]) List[T] {
 
 // Apply a function to every element of a list.
 func Map[A, B any](f func(A) B, xs List[A]) List[B] {
-	return Foldr(func(a A, b List[B]) List[B] { return Cons(f(a), b) }, Empty[B](), xs)
+	return mapBlock(f, xs)
 }
 
 // Same as map but the function is also applied to the index of each element (starting at zero).
@@ -233,13 +256,7 @@ func foldrHelper[A, B any](fn func(A, B) B, acc B, ctr basics.Int, ls List[A]) B
 
 // Keep elements that satisfy the test.
 func Filter[T any](isGood func(T) bool, list List[T]) List[T] {
-	return Foldr(func(x T, xs List[T]) List[T] {
-		if isGood(x) {
-			return Cons(x, xs)
-		} else {
-			return xs
-		}
-	}, Empty[T](), list)
+	return filterBlock(isGood, list)
 }
 
 // Filter out certain values. For example, maybe you have a bunch of strings from an
@@ -258,9 +275,9 @@ func maybeCons[A, B any](f func(A) maybe.Maybe[B], mx A, xs List[B]) List[B] {
 
 // Utilities
 
-// Determine the length of a list.
+// Determine the length of a list. This takes O(n) time, since it counts the cells one by one.
 func Length[T any](ls List[T]) basics.Int {
-	return Foldl(func(_ T, y basics.Int) basics.Int { return y + 1 }, 0, ls)
+	return basics.Int(length(ls))
 }
 
 // Reverse a list.
@@ -421,45 +438,202 @@ func map5Help[A, B, C, D, E, result any](f func(A, B, C, D, E) result, vs List[A
 
 // Sort
 
-// Sort values from lowest to highest.
+// Sort values from lowest to highest. The sort is stable, so equal values keep their order.
 func Sort[T basics.Comparable[T]](xs List[T]) List[T] {
-	slc := ToSlice(xs)
-	slices.SortFunc(
-		slc,
-		func(a, b T) int {
-			return a.Cmp(b)
-		},
-	)
-	return FromSlice(slc)
+	return mergeSort(xs, func(a, b T) int { return a.Cmp(b) })
 }
 
-// Sort values by a derived property.
-func SortBy[A basics.Comparable[A]](f func(A) A, xs List[A]) List[A] {
-	slc := ToSlice(xs)
-	slices.SortFunc(
-		slc,
-		func(a, b A) int {
-			return f(a).Cmp(f(b))
-		},
-	)
-	return FromSlice(slc)
+// Sort values by a derived property, which can be any Comparable type. The sort is stable,
+// and f is called once per element, so expensive properties are not computed again for every comparison.
+func SortBy[A any, B basics.Comparable[B]](f func(A) B, xs List[A]) List[A] {
+	return sortByKey(f, func(a, b B) int { return a.Cmp(b) }, xs)
 }
 
-// Sort values with a custom comparison function.
+// Sort values by a derived property, from highest to lowest. Values with equal properties keep their order.
+func SortByDescending[A any, B basics.Comparable[B]](f func(A) B, xs List[A]) List[A] {
+	return sortByKey(f, func(a, b B) int { return b.Cmp(a) }, xs)
+}
+
+// Sort values with a custom comparison function. The sort is stable.
 func SortWith[A any](f func(a A, b A) basics.Order, xs List[A]) List[A] {
-	slc := ToSlice(xs)
-	slices.SortFunc(slc, func(a, b A) int {
-		ord := f(a, b)
-		switch ord.(type) {
-		case basics.EQ:
-			return 0
-		case basics.LT:
-			return -1
+	return mergeSort(xs, func(a, b A) int { return orderToInt(f(a, b)) })
+}
+
+// Compare values by a derived property. Use it with SortWith, and add more properties with ThenBy.
+//
+//	SortWith(ThenBy(By(age), name), people)
+//
+// Unlike SortBy, properties are not cached: f is called twice for every comparison,
+// so prefer SortBy when a single property is expensive to compute.
+func By[A any, B basics.Comparable[B]](f func(A) B) func(A, A) basics.Order {
+	return func(a, b A) basics.Order { return basics.Compare(f(a), f(b)) }
+}
+
+// Compare values by a derived property, from highest to lowest.
+func ByDescending[A any, B basics.Comparable[B]](f func(A) B) func(A, A) basics.Order {
+	return func(a, b A) basics.Order { return basics.Compare(f(b), f(a)) }
+}
+
+// Compare values with a comparison function, and break ties with a derived property.
+func ThenBy[A any, B basics.Comparable[B]](ord func(A, A) basics.Order, f func(A) B) func(A, A) basics.Order {
+	return thenWith(ord, By(f))
+}
+
+// Compare values with a comparison function, and break ties with a derived property, from highest to lowest.
+func ThenByDescending[A any, B basics.Comparable[B]](ord func(A, A) basics.Order, f func(A) B) func(A, A) basics.Order {
+	return thenWith(ord, ByDescending(f))
+}
+
+func thenWith[A any](first func(A, A) basics.Order, second func(A, A) basics.Order) func(A, A) basics.Order {
+	return func(a, b A) basics.Order {
+		ord := first(a, b)
+		if _, tie := ord.(basics.EQ); tie {
+			return second(a, b)
+		}
+		return ord
+	}
+}
+
+func orderToInt(ord basics.Order) int {
+	switch ord.(type) {
+	case basics.EQ:
+		return 0
+	case basics.LT:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// A value together with the property it is sorted by.
+type keyed[A, B any] struct {
+	key   B
+	value A
+}
+
+// Sort by a property computed once per element, decorating each element with its property
+// while copying it into the sort's cells and undecorating the sorted cells.
+func sortByKey[A, B any](f func(A) B, cmp func(a, b B) int, xs List[A]) List[A] {
+	sorted := sortCells(
+		xs,
+		func(x A) keyed[A, B] { return keyed[A, B]{key: f(x), value: x} },
+		func(a, b keyed[A, B]) int { return cmp(a.key, b.key) },
+	)
+	var head, last *list[A]
+	for c := sorted.head; c != nil; c = next(c) {
+		l := &list[A]{&internal.Cons_[A, List[A]]{A: c.A.value, B: Empty[A]()}}
+		if head == nil {
+			head = l
+		} else {
+			last.B = l
+		}
+		last = l
+	}
+	if head == nil {
+		return Empty[A]()
+	}
+	return head
+}
+
+func mergeSort[T any](xs List[T], cmp func(a, b T) int) List[T] {
+	sorted := sortCells(xs, func(x T) T { return x }, cmp)
+	if sorted.head == nil {
+		return Empty[T]()
+	}
+	sorted.last.B = Empty[T]()
+	return sorted.head
+}
+
+// A run of sorted cells, from head to last. The last cell has no tail yet.
+type run[T any] struct {
+	head *list[T]
+	last *list[T]
+}
+
+// The cell after c in a run, or nil at the end of the run.
+func next[T any](c *list[T]) *list[T] {
+	if c.B == nil {
+		return nil
+	}
+	return c.B.(*list[T])
+}
+
+// Stably sort the elements of a list, mapped with f, with a merge sort on cons cells.
+// The elements are copied into new cells once, gathering them into runs that are already sorted.
+// No other list can see those cells, so the runs are merged by relinking them in place,
+// which means the sort makes n cells and nothing else per element.
+func sortCells[A, T any](xs List[A], f func(A) T, cmp func(a, b T) int) run[T] {
+	var runs []run[T]
+	var cur run[T]
+	descending := false
+	for ; xs.Cons() != nil; xs = xs.Cons().B {
+		c := &list[T]{&internal.Cons_[T, List[T]]{A: f(xs.Cons().A)}}
+		switch {
+		case cur.head == nil:
+			cur = run[T]{head: c, last: c}
+			descending = false
+		case cur.head == cur.last && cmp(cur.head.A, c.A) > 0:
+			// A run can also be strictly descending, and is built backwards
+			c.B = cur.head
+			cur.head = c
+			descending = true
+		case descending && cmp(cur.head.A, c.A) > 0:
+			c.B = cur.head
+			cur.head = c
+		case !descending && cmp(cur.last.A, c.A) <= 0:
+			cur.last.B = c
+			cur.last = c
 		default:
-			return 1
+			runs = append(runs, cur)
+			cur = run[T]{head: c, last: c}
+			descending = false
+		}
+	}
+	if cur.head == nil {
+		return cur
+	}
+	runs = append(runs, cur)
+	// Merging neighbouring runs, and preferring the left one on ties, keeps the sort stable
+	for len(runs) > 1 {
+		merged := runs[:0]
+		for i := 0; i < len(runs); i += 2 {
+			if i+1 == len(runs) {
+				merged = append(merged, runs[i])
+			} else {
+				merged = append(merged, merge(runs[i], runs[i+1], cmp))
+			}
 		}
-	})
-	return FromSlice(slc)
+		runs = merged
+	}
+	return runs[0]
+}
+
+func merge[T any](xs run[T], ys run[T], cmp func(a, b T) int) run[T] {
+	var out run[T]
+	x, y := xs.head, ys.head
+	for x != nil && y != nil {
+		var c *list[T]
+		if cmp(x.A, y.A) <= 0 {
+			c, x = x, next(x)
+		} else {
+			c, y = y, next(y)
+		}
+		if out.head == nil {
+			out.head = c
+		} else {
+			out.last.B = c
+		}
+		out.last = c
+	}
+	switch {
+	case x != nil:
+		out.last.B = x
+		out.last = xs.last
+	case y != nil:
+		out.last.B = y
+		out.last = ys.last
+	}
+	return out
 }
 
 // Deconstruct
@@ -627,34 +801,132 @@ func ListWith[T any, R any](l1 List[T], e func(List[T]) R, ab func(T, List[T]) R
 
 // Utils
 
-// Create a List from a Go slice
+// Create a List from a Go slice.
+// The elements are copied into cons cells that sit together in memory, so making the list
+// takes two allocations however long it is.
 func FromSlice[T any](arr []T) List[T] {
-	var result List[T] = Empty[T]()
-	for i := len(arr) - 1; i >= 0; i-- {
-		result = Cons(arr[i], result)
-	}
-	return result
+	return fromBlock(arr, Empty[T]())
 }
 func FromSliceMap[A any, B any](f func(A) B, arr []A) List[B] {
-	var result List[B] = Empty[B]()
-	for i := len(arr) - 1; i >= 0; i-- {
-		result = Cons(f(arr[i]), result)
+	if len(arr) == 0 {
+		return Empty[B]()
 	}
-	return result
+	nodes := block(len(arr), Empty[B]())
+	for i, x := range arr {
+		nodes[i].A = f(x)
+	}
+	return &nodes[0]
 }
 
 func ToSlice[T any](xs List[T]) []T {
-	var arr []T = []T{}
-	for ; xs.Cons() != nil; xs = xs.Cons().B {
-		arr = append(arr, xs.Cons().A)
+	arr := make([]T, 0, length(xs))
+	for c := xs.Cons(); c != nil; c = c.B.Cons() {
+		arr = append(arr, c.A)
 	}
 	return arr
 }
 
 func ToSliceMap[A any, B any](f func(A) B, xs List[A]) []B {
-	var arr []B = []B{}
-	for ; xs.Cons() != nil; xs = xs.Cons().B {
-		arr = append(arr, f(xs.Cons().A))
+	arr := make([]B, 0, length(xs))
+	for c := xs.Cons(); c != nil; c = c.B.Cons() {
+		arr = append(arr, f(c.A))
 	}
 	return arr
 }
+
+// Iterators
+
+// Get a sequence of the elements of a list, from first to last.
+// This is called Values because All already checks every element against a test.
+//
+//	for x := range Values(xs) {
+//		fmt.Println(x)
+//	}
+func Values[T any](xs List[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for ; xs.Cons() != nil; xs = xs.Cons().B {
+			if !yield(xs.Cons().A) {
+				return
+			}
+		}
+	}
+}
+
+// Get a sequence of the elements of a list, from last to first.
+// A list can only be walked from the front, so the elements are gathered into a slice before the first one is yielded.
+func Backward[T any](xs List[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		arr := ToSlice(xs)
+		for i := len(arr) - 1; i >= 0; i-- {
+			if !yield(arr[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Build a list from a sequence, keeping the order of the elements.
+func Collect[T any](seq iter.Seq[T]) List[T] {
+	b := NewBuilder[T]()
+	for x := range seq {
+		b.Append(x)
+	}
+	return b.Freeze()
+}
+
+// Builder
+
+// A Builder makes a list by adding elements to the end in place, which takes O(1) time per element
+// instead of the O(n) time of Append. Call Freeze to get the list, which never changes afterwards.
+// Adding to a builder after Freeze first copies the elements added so far.
+//
+// A Builder is not safe for use by multiple goroutines at the same time.
+type Builder[T any] struct {
+	head   *list[T]
+	last   *list[T]
+	length basics.Int
+	frozen bool
+}
+
+// Create an empty builder.
+func NewBuilder[T any]() *Builder[T] {
+	return &Builder[T]{}
+}
+
+// Add an element to the end of the list.
+func (b *Builder[T]) Append(val T) {
+	if b.frozen {
+		b.copy()
+	}
+	l := &list[T]{&internal.Cons_[T, List[T]]{A: val, B: Empty[T]()}}
+	if b.head == nil {
+		b.head = l
+	} else {
+		b.last.B = l
+	}
+	b.last = l
+	b.length++
+}
+
+// Determine the number of elements in the builder.
+func (b *Builder[T]) Length() basics.Int {
+	return b.length
+}
+
+// Get the list built so far.
+func (b *Builder[T]) Freeze() List[T] {
+	if b.head == nil {
+		return Empty[T]()
+	}
+	b.frozen = true
+	return b.head
+}
+
+// The frozen list shares its elements with the builder, so they are copied before the builder changes them.
+func (b *Builder[T]) copy() {
+	var xs List[T] = b.head
+	b.head, b.last, b.length, b.frozen = nil, nil, 0, false
+	for ; xs.Cons() != nil; xs = xs.Cons().B {
+		b.Append(xs.Cons().A)
+	}
+}
@@ -2,10 +2,14 @@ package list
 
 import (
 	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/char"
 	"github.com/Confidenceman02/scion-tools/pkg/internal"
 	"github.com/Confidenceman02/scion-tools/pkg/maybe"
 	. "github.com/Confidenceman02/scion-tools/pkg/tuple"
 	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -59,6 +63,59 @@ func TestCmp(t *testing.T) {
 		asserts.Equal(basics.LT{}, basics.Compare(l13, l14))
 	})
 
+	t.Run("Compare: When cons char.Char", func(t *testing.T) {
+		l1 := FromSlice([]char.Char{'a', 'b', 'c'})
+		l2 := FromSlice([]char.Char{'a', 'b', 'd'})
+		l3 := FromSlice([]List[char.Char]{l1})
+		l4 := FromSlice([]List[char.Char]{l2})
+
+		asserts.Equal(-1, l1.Cmp(l2))
+		asserts.Equal(basics.GT{}, basics.Compare(l2, l1))
+		asserts.Equal(basics.LT{}, basics.Compare(l3, l4))
+		asserts.Equal(basics.EQ{}, basics.Compare(l3, l3))
+	})
+
+	t.Run("Compare: When cons Tuple2", func(t *testing.T) {
+		l1 := FromSlice([]Tuple2[basics.Int, basics.Int]{Pair[basics.Int, basics.Int](1, 2), Pair[basics.Int, basics.Int](3, 4)})
+		l2 := FromSlice([]Tuple2[basics.Int, basics.Int]{Pair[basics.Int, basics.Int](1, 2), Pair[basics.Int, basics.Int](3, 5)})
+
+		asserts.Equal(basics.LT{}, basics.Compare(l1, l2))
+		asserts.Equal(basics.EQ{}, basics.Compare(l2, l2))
+	})
+
+	t.Run("Compare: Tuple2 of lists", func(t *testing.T) {
+		t1 := Pair(Range(1, 3), Singleton[basics.Int](1))
+		t2 := Pair(Range(1, 3), Singleton[basics.Int](2))
+		t3 := Pair(Range(1, 4), Singleton[basics.Int](0))
+
+		asserts.Equal(basics.LT{}, basics.Compare(t1, t2))
+		asserts.Equal(basics.LT{}, basics.Compare(t2, t3))
+		asserts.Equal(basics.EQ{}, basics.Compare(t3, t3))
+	})
+
+	t.Run("Sort Tuple2", func(t *testing.T) {
+		SUT := Sort(FromSlice([]Tuple2[basics.Int, basics.Int]{
+			Pair[basics.Int, basics.Int](2, 1),
+			Pair[basics.Int, basics.Int](1, 2),
+			Pair[basics.Int, basics.Int](1, 1),
+		}))
+
+		asserts.Equal(
+			[]Tuple2[basics.Int, basics.Int]{
+				Pair[basics.Int, basics.Int](1, 1),
+				Pair[basics.Int, basics.Int](1, 2),
+				Pair[basics.Int, basics.Int](2, 1),
+			},
+			ToSlice(SUT),
+		)
+	})
+
+	t.Run("Sort char.Char", func(t *testing.T) {
+		SUT := Sort(FromSlice([]char.Char{'c', 'A', 'b', 'a'}))
+
+		asserts.Equal([]char.Char{'A', 'a', 'b', 'c'}, ToSlice(SUT))
+	})
+
 	t.Run("Compare: When cons basics.Float", func(t *testing.T) {
 		l1 := Singleton[basics.Float](1.0)
 		l2 := Singleton[basics.Float](1.0)
@@ -594,9 +651,99 @@ func TestCombineFunctions(t *testing.T) {
 	})
 }
 
-func testSortFUnctions(t *testing.T) {
-	// asserts := assert.New(t)
+func TestSortFunctions(t *testing.T) {
+	asserts := assert.New(t)
+	type person struct {
+		name basics.Int
+		age  basics.Int
+	}
+	age := func(p person) basics.Int { return p.age }
+	name := func(p person) basics.Int { return p.name }
+	people := FromSlice([]person{{1, 30}, {2, 20}, {3, 30}, {4, 20}, {5, 40}})
+
+	t.Run("Sort matches a stable slice sort", func(t *testing.T) {
+		r := rand.New(rand.NewSource(7))
+		for _, n := range []int{0, 1, 2, 3, 10, 100, 1000} {
+			xs := make([]Tuple2[basics.Int, basics.Int], n)
+			for i := range xs {
+				xs[i] = Pair(basics.Int(r.Intn(10)), basics.Int(i))
+			}
+			want := slices.Clone(xs)
+			slices.SortStableFunc(want, func(a, b Tuple2[basics.Int, basics.Int]) int {
+				return First(a).Cmp(First(b))
+			})
+
+			SUT := SortBy(First[basics.Int, basics.Int], FromSlice(xs))
+
+			asserts.Equal(want, ToSlice(SUT))
+		}
+	})
+
+	t.Run("Sort runs that are ascending and descending", func(t *testing.T) {
+		SUT := Sort(FromSlice([]basics.Int{1, 2, 2, 3, 9, 8, 7, 4, 5, 5, 0}))
+
+		asserts.Equal([]basics.Int{0, 1, 2, 2, 3, 4, 5, 5, 7, 8, 9}, ToSlice(SUT))
+	})
+
+	t.Run("Sort leaves the list alone", func(t *testing.T) {
+		xs := FromSlice([]basics.Int{3, 1, 2})
+		Sort(xs)
+
+		asserts.Equal([]basics.Int{3, 1, 2}, ToSlice(xs))
+	})
+
+	t.Run("Sort long lists", func(t *testing.T) {
+		SUT := Sort(Reverse(Range(1, 100000)))
+
+		asserts.Equal(ToSlice(Range(1, 100000)), ToSlice(SUT))
+	})
+
+	t.Run("SortBy keeps equal elements in order", func(t *testing.T) {
+		SUT := SortBy(age, people)
+
+		asserts.Equal([]person{{2, 20}, {4, 20}, {1, 30}, {3, 30}, {5, 40}}, ToSlice(SUT))
+	})
+
+	t.Run("SortBy computes each key once", func(t *testing.T) {
+		calls := 0
+		SortBy(func(x basics.Int) basics.Int { calls++; return -x }, Range(1, 100))
 
+		asserts.Equal(100, calls)
+	})
+
+	t.Run("SortByDescending keeps equal elements in order", func(t *testing.T) {
+		SUT := SortByDescending(age, people)
+
+		asserts.Equal([]person{{5, 40}, {1, 30}, {3, 30}, {2, 20}, {4, 20}}, ToSlice(SUT))
+	})
+
+	t.Run("SortWith is stable", func(t *testing.T) {
+		SUT := SortWith(func(a, b person) basics.Order { return basics.Compare(a.age, b.age) }, people)
+
+		asserts.Equal(ToSlice(SortBy(age, people)), ToSlice(SUT))
+	})
+
+	t.Run("ThenBy", func(t *testing.T) {
+		SUT := SortWith(ThenByDescending(By(age), name), people)
+
+		asserts.Equal([]person{{4, 20}, {2, 20}, {3, 30}, {1, 30}, {5, 40}}, ToSlice(SUT))
+	})
+
+	t.Run("ByDescending and ThenBy", func(t *testing.T) {
+		SUT := SortWith(ThenBy(ByDescending(age), name), people)
+
+		asserts.Equal([]person{{5, 40}, {1, 30}, {3, 30}, {2, 20}, {4, 20}}, ToSlice(SUT))
+	})
+
+	t.Run("ThenBy compares with the first property once per comparison", func(t *testing.T) {
+		calls, comparisons := 0, 0
+		first := func(a, b person) basics.Order { calls++; return basics.Compare(a.name, b.name) }
+		ord := ThenBy(ThenBy(ThenBy(first, age), age), age)
+
+		SortWith(func(a, b person) basics.Order { comparisons++; return ord(a, b) }, people)
+
+		asserts.Equal(comparisons, calls)
+	})
 }
 
 func TestDeconstructFunctions(t *testing.T) {
@@ -713,3 +860,260 @@ func TestEmpty(t *testing.T) {
 		asserts.Equal(empty[basics.Int]{}, SUT)
 	})
 }
+
+func TestBuilder(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Append and Freeze", func(t *testing.T) {
+		b := NewBuilder[basics.Int]()
+		b.Append(1)
+		b.Append(2)
+		b.Append(3)
+
+		asserts.Equal(FromSlice([]basics.Int{1, 2, 3}), b.Freeze())
+		asserts.Equal(basics.Int(3), b.Length())
+	})
+	t.Run("Freeze with no elements", func(t *testing.T) {
+		asserts.Equal(Empty[basics.Int](), NewBuilder[basics.Int]().Freeze())
+	})
+	t.Run("Frozen lists never change", func(t *testing.T) {
+		b := NewBuilder[basics.Int]()
+		b.Append(1)
+		b.Append(2)
+		frozen := b.Freeze()
+		b.Append(3)
+		again := b.Freeze()
+		b.Append(4)
+
+		asserts.Equal([]basics.Int{1, 2}, ToSlice(frozen))
+		asserts.Equal([]basics.Int{1, 2, 3}, ToSlice(again))
+		asserts.Equal([]basics.Int{1, 2, 3, 4}, ToSlice(b.Freeze()))
+		asserts.Equal(basics.Int(4), b.Length())
+	})
+}
+
+func TestIterators(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Values", func(t *testing.T) {
+		var SUT []basics.Int
+		for x := range Values(Range(1, 5)) {
+			SUT = append(SUT, x)
+		}
+
+		asserts.Equal([]basics.Int{1, 2, 3, 4, 5}, SUT)
+	})
+	t.Run("Values stops early", func(t *testing.T) {
+		var SUT []basics.Int
+		for x := range Values(Range(1, 5)) {
+			if x > 2 {
+				break
+			}
+			SUT = append(SUT, x)
+		}
+
+		asserts.Equal([]basics.Int{1, 2}, SUT)
+	})
+	t.Run("Backward", func(t *testing.T) {
+		var SUT []basics.Int
+		for x := range Backward(Range(1, 5)) {
+			SUT = append(SUT, x)
+		}
+
+		asserts.Equal([]basics.Int{5, 4, 3, 2, 1}, SUT)
+	})
+	t.Run("Collect", func(t *testing.T) {
+		asserts.Equal(Range(1, 5), Collect(Values(Range(1, 5))))
+		asserts.Equal(Reverse(Range(1, 5)), Collect(Backward(Range(1, 5))))
+		asserts.Equal(Empty[basics.Int](), Collect(Values(Empty[basics.Int]())))
+	})
+}
+
+func TestEquality(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Same elements", func(t *testing.T) {
+		asserts.True(basics.Eq(Range(1, 3), FromSlice([]basics.Int{1, 2, 3})))
+		asserts.Equal(basics.Hash(Range(1, 3)), basics.Hash(FromSlice([]basics.Int{1, 2, 3})))
+	})
+	t.Run("Different elements or lengths", func(t *testing.T) {
+		asserts.False(basics.Eq(Range(1, 3), FromSlice([]basics.Int{1, 2, 4})))
+		asserts.False(basics.Eq(Range(1, 3), Range(1, 4)))
+		asserts.False(basics.Eq(Range(1, 4), Range(1, 3)))
+		asserts.False(basics.Eq(Empty[basics.Int](), Range(1, 3)))
+		asserts.NotEqual(basics.Hash(Range(1, 3)), basics.Hash(Range(1, 4)))
+	})
+	t.Run("Empty lists", func(t *testing.T) {
+		asserts.True(basics.Eq(Empty[basics.Int](), Drop(3, Range(1, 3))))
+		asserts.Equal(basics.Hash(Empty[basics.Int]()), basics.Hash(Drop(3, Range(1, 3))))
+		asserts.Equal(basics.EQ{}, basics.Compare(Empty[basics.Int](), Drop(3, Range(1, 3))))
+	})
+	t.Run("Elements are compared with Eq", func(t *testing.T) {
+		xs := FromSlice([]caseless{"Go", "Elm"})
+		ys := FromSlice([]caseless{"GO", "ELM"})
+
+		asserts.True(basics.Eq(xs, ys))
+		asserts.Equal(basics.Hash(xs), basics.Hash(ys))
+		asserts.True(Member[caseless]("elm", xs))
+	})
+}
+
+// A string that is equal to the same string in any case.
+type caseless string
+
+func (c caseless) Equal(y caseless) bool {
+	return strings.EqualFold(string(c), string(y))
+}
+
+func (c caseless) Hash() uint64 {
+	return basics.Hash(strings.ToLower(string(c)))
+}
+
+func TestBlocks(t *testing.T) {
+	asserts := assert.New(t)
+	xs := make([]int, 1000)
+	for i := range xs {
+		xs[i] = i
+	}
+	even := func(x int) bool { return x%2 == 0 }
+	double := func(x int) int { return x * 2 }
+
+	t.Run("Lists made from a slice look the same as lists made with Cons", func(t *testing.T) {
+		asserts.Equal(consFromSlice(xs), FromSlice(xs))
+		asserts.Equal(Empty[int](), FromSlice([]int{}))
+		asserts.Equal(consFromSlice(xs), FromSliceMap(basics.Identity[int], xs))
+	})
+
+	t.Run("Map and Filter give the same lists as folding with Cons", func(t *testing.T) {
+		asserts.Equal(mapByFoldr(double, consFromSlice(xs)), Map(double, FromSlice(xs)))
+		asserts.Equal(filterByFoldr(even, consFromSlice(xs)), Filter(even, FromSlice(xs)))
+		asserts.Equal(Empty[int](), Filter(func(int) bool { return false }, FromSlice(xs)))
+	})
+
+	t.Run("FromSlice copies the slice", func(t *testing.T) {
+		arr := []int{1, 2, 3}
+		SUT := FromSlice(arr)
+		arr[0] = 100
+
+		asserts.Equal([]int{1, 2, 3}, ToSlice(SUT))
+	})
+
+	t.Run("Consing onto a block and taking its tail share it", func(t *testing.T) {
+		SUT := FromSlice(xs)
+		tail := Drop(1, SUT)
+
+		asserts.Same(SUT.Cons().B, tail)
+		asserts.Same(SUT, Cons(-1, SUT).Cons().B)
+		asserts.Equal(basics.Int(999), Length(tail))
+		asserts.Equal(xs[1:], ToSlice(tail))
+	})
+}
+
+// Make a list from a slice with a separate cons cell for each element, as FromSlice did before blocks.
+func consFromSlice[T any](arr []T) List[T] {
+	var result List[T] = Empty[T]()
+	for i := len(arr) - 1; i >= 0; i-- {
+		result = Cons(arr[i], result)
+	}
+	return result
+}
+
+// Map and Filter as they were before lists were kept in blocks.
+func mapByFoldr[A, B any](f func(A) B, xs List[A]) List[B] {
+	return Foldr(func(a A, b List[B]) List[B] { return Cons(f(a), b) }, Empty[B](), xs)
+}
+
+func filterByFoldr[T any](isGood func(T) bool, xs List[T]) List[T] {
+	return Foldr(func(x T, acc List[T]) List[T] {
+		if isGood(x) {
+			return Cons(x, acc)
+		}
+		return acc
+	}, Empty[T](), xs)
+}
+
+func benchmarkSlice(b *testing.B) []int {
+	xs := make([]int, 1_000_000)
+	for i := range xs {
+		xs[i] = i
+	}
+	b.ResetTimer()
+	return xs
+}
+
+func BenchmarkFromSlice(b *testing.B) {
+	b.Run("block", func(b *testing.B) {
+		xs := benchmarkSlice(b)
+		for i := 0; i < b.N; i++ {
+			FromSlice(xs)
+		}
+	})
+	b.Run("cons", func(b *testing.B) {
+		xs := benchmarkSlice(b)
+		for i := 0; i < b.N; i++ {
+			consFromSlice(xs)
+		}
+	})
+}
+
+func BenchmarkMap(b *testing.B) {
+	double := func(x int) int { return x * 2 }
+	b.Run("block", func(b *testing.B) {
+		xs := FromSlice(benchmarkSlice(b))
+		for i := 0; i < b.N; i++ {
+			Map(double, xs)
+		}
+	})
+	b.Run("cons", func(b *testing.B) {
+		xs := consFromSlice(benchmarkSlice(b))
+		for i := 0; i < b.N; i++ {
+			mapByFoldr(double, xs)
+		}
+	})
+}
+
+func BenchmarkFilter(b *testing.B) {
+	even := func(x int) bool { return x%2 == 0 }
+	b.Run("block", func(b *testing.B) {
+		xs := FromSlice(benchmarkSlice(b))
+		for i := 0; i < b.N; i++ {
+			Filter(even, xs)
+		}
+	})
+	b.Run("cons", func(b *testing.B) {
+		xs := consFromSlice(benchmarkSlice(b))
+		for i := 0; i < b.N; i++ {
+			filterByFoldr(even, xs)
+		}
+	})
+}
+
+func BenchmarkLength(b *testing.B) {
+	b.Run("block", func(b *testing.B) {
+		xs := FromSlice(benchmarkSlice(b))
+		for i := 0; i < b.N; i++ {
+			Length(xs)
+		}
+	})
+	b.Run("cons", func(b *testing.B) {
+		xs := consFromSlice(benchmarkSlice(b))
+		for i := 0; i < b.N; i++ {
+			Length(xs)
+		}
+	})
+}
+
+func BenchmarkToSlice(b *testing.B) {
+	b.Run("block", func(b *testing.B) {
+		xs := FromSlice(benchmarkSlice(b))
+		for i := 0; i < b.N; i++ {
+			ToSlice(xs)
+		}
+	})
+	b.Run("cons", func(b *testing.B) {
+		xs := consFromSlice(benchmarkSlice(b))
+		for i := 0; i < b.N; i++ {
+			ToSlice(xs)
+		}
+	})
+}
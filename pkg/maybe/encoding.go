@@ -0,0 +1,72 @@
+package maybe
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"github.com/Confidenceman02/scion-tools/pkg/internal"
+)
+
+// Just is encoded as its value.
+func (j Just[A]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Value)
+}
+
+// Nothing is encoded as null.
+func (n Nothing) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+// Decode a Maybe from JSON, where null is Nothing and any other value is Just that value.
+func FromJSON[A any](data []byte) (Maybe[A], error) {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return Nothing{}, nil
+	}
+	v, err := internal.UnmarshalJSON[A](data)
+	if err != nil {
+		return nil, err
+	}
+	return Just[A]{Value: v}, nil
+}
+
+// Register sets up Maybe[A] to be decoded inside other values and sent through encoding/gob.
+func Register[A any]() {
+	gob.Register(Just[A]{})
+	gob.Register(Nothing{})
+	internal.RegisterJSON(FromJSON[A])
+}
+
+// A Field holds a Maybe as a struct field that encoding/json can decode.
+// A Field with no Maybe is encoded as Nothing.
+//
+//	type User struct {
+//		Nickname maybe.Field[string]
+//	}
+type Field[A any] struct {
+	Maybe Maybe[A]
+}
+
+func (f Field[A]) MarshalJSON() ([]byte, error) {
+	if f.Maybe == nil {
+		return json.Marshal(Nothing{})
+	}
+	return json.Marshal(f.Maybe)
+}
+
+func (f *Field[A]) UnmarshalJSON(data []byte) error {
+	m, err := FromJSON[A](data)
+	if err != nil {
+		return err
+	}
+	f.Maybe = m
+	return nil
+}
+
+// Nothing has no exported fields, so it sends no data through gob.
+func (n Nothing) GobEncode() ([]byte, error) {
+	return []byte{}, nil
+}
+
+func (n *Nothing) GobDecode([]byte) error {
+	return nil
+}
@@ -0,0 +1,84 @@
+package maybe
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	. "github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type payload struct {
+	Age Maybe[Int]
+}
+
+func TestJSON(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Marshal Just", func(t *testing.T) {
+		SUT, err := json.Marshal(payload{Age: Just[Int]{Value: 42}})
+
+		asserts.NoError(err)
+		asserts.Equal(`{"Age":42}`, string(SUT))
+	})
+	t.Run("Marshal Nothing", func(t *testing.T) {
+		SUT, err := json.Marshal(payload{Age: Nothing{}})
+
+		asserts.NoError(err)
+		asserts.Equal(`{"Age":null}`, string(SUT))
+	})
+	t.Run("FromJSON", func(t *testing.T) {
+		SUT1, err1 := FromJSON[Int]([]byte(`42`))
+		SUT2, err2 := FromJSON[Int]([]byte(` null `))
+		_, err3 := FromJSON[Int]([]byte(`"42"`))
+
+		asserts.NoError(err1)
+		asserts.NoError(err2)
+		asserts.Error(err3)
+		asserts.Equal(Just[Int]{Value: 42}, SUT1)
+		asserts.Equal(Nothing{}, SUT2)
+	})
+	t.Run("Round trip", func(t *testing.T) {
+		for _, m := range []Maybe[string]{Just[string]{Value: "hi"}, Nothing{}} {
+			data, err := json.Marshal(m)
+			asserts.NoError(err)
+
+			SUT, err := FromJSON[string](data)
+
+			asserts.NoError(err)
+			asserts.Equal(m, SUT)
+		}
+	})
+	t.Run("Round trip a struct with a Field", func(t *testing.T) {
+		type user struct {
+			Age      Field[Int]
+			Nickname Field[string]
+			Email    Field[string]
+		}
+		data, err := json.Marshal(user{Age: Field[Int]{Maybe: Just[Int]{Value: 42}}, Nickname: Field[string]{Maybe: Nothing{}}})
+		asserts.NoError(err)
+		asserts.Equal(`{"Age":42,"Nickname":null,"Email":null}`, string(data))
+
+		var SUT user
+		asserts.NoError(json.Unmarshal(data, &SUT))
+
+		asserts.Equal(Just[Int]{Value: 42}, SUT.Age.Maybe)
+		asserts.Equal(Nothing{}, SUT.Nickname.Maybe)
+		asserts.Equal(Nothing{}, SUT.Email.Maybe)
+	})
+}
+
+func TestGob(t *testing.T) {
+	asserts := assert.New(t)
+	Register[Int]()
+
+	for _, m := range []Maybe[Int]{Just[Int]{Value: 42}, Nothing{}} {
+		var buf bytes.Buffer
+		asserts.NoError(gob.NewEncoder(&buf).Encode(payload{Age: m}))
+		var SUT payload
+		asserts.NoError(gob.NewDecoder(&buf).Decode(&SUT))
+
+		asserts.Equal(payload{Age: m}, SUT)
+	}
+}
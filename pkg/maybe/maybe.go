@@ -3,6 +3,8 @@ package maybe
 
 import (
 	"fmt"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/internal/hashing"
 	"reflect"
 )
 
@@ -34,6 +36,23 @@ type Nothing struct {
 	_maybe
 }
 
+// EQUALITY
+
+// Just is equal to another Just with an equal value, compared with basics.Eq.
+// Nothing has no type parameter to be Equatable with, so basics.Eq compares it with reflection.
+func (j Just[A]) Equal(m Maybe[A]) bool {
+	o, ok := m.(Just[A])
+	return ok && basics.Eq(j.Value, o.Value)
+}
+
+func (j Just[A]) Hash() uint64 {
+	return hashing.Combine(hashing.Uint64(1), basics.Hash(j.Value))
+}
+
+func (n Nothing) Hash() uint64 {
+	return hashing.Uint64(0)
+}
+
 // Common helpers
 
 // Provide a default value, turning an optional value into a normal value.
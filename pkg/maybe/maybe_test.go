@@ -3,6 +3,7 @@ package maybe
 import (
 	. "github.com/Confidenceman02/scion-tools/pkg/basics"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -191,3 +192,39 @@ func TestWithDefault(t *testing.T) {
 		asserts.Equal(2, WithDefault(22, SUT))
 	})
 }
+
+func TestEquality(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Just", func(t *testing.T) {
+		var x, y Maybe[caseless] = Just[caseless]{Value: "Go"}, Just[caseless]{Value: "GO"}
+
+		asserts.True(Eq(x, y))
+		asserts.Equal(Hash(x), Hash(y))
+		asserts.False(Eq[Maybe[caseless]](x, Just[caseless]{Value: "Elm"}))
+	})
+	t.Run("Nothing", func(t *testing.T) {
+		var x, y Maybe[caseless] = Nothing{}, Nothing{}
+
+		asserts.True(Eq(x, y))
+		asserts.Equal(Hash(x), Hash(y))
+	})
+	t.Run("Just and Nothing", func(t *testing.T) {
+		var x, y Maybe[Int] = Just[Int]{Value: 0}, Nothing{}
+
+		asserts.False(Eq(x, y))
+		asserts.False(Eq(y, x))
+		asserts.NotEqual(Hash(x), Hash(y))
+	})
+}
+
+// A string that is equal to the same string in any case.
+type caseless string
+
+func (c caseless) Equal(y caseless) bool {
+	return strings.EqualFold(string(c), string(y))
+}
+
+func (c caseless) Hash() uint64 {
+	return Hash(strings.ToLower(string(c)))
+}
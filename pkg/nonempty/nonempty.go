@@ -0,0 +1,185 @@
+// Package nonempty has a list type that always has at least one element, so functions like Head,
+// Maximum and Foldl1 can give back a value instead of a Maybe. It is handy wherever emptiness is
+// ruled out by construction, like a failed validation that carries at least one error.
+package nonempty
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+)
+
+// NonEmpty is a first element followed by a list of the rest.
+//
+// The zero value is a list with the zero value of T as its only element.
+type NonEmpty[T any] struct {
+	head T
+	tail list.List[T]
+}
+
+// Create a non-empty list from its first element and the rest.
+func New[T any](head T, tail list.List[T]) NonEmpty[T] {
+	return NonEmpty[T]{head: head, tail: tail}
+}
+
+// Create a non-empty list with only one element.
+func Singleton[T any](x T) NonEmpty[T] {
+	return New(x, list.Empty[T]())
+}
+
+// Add an element to the front of a non-empty list.
+func Cons[T any](x T, xs NonEmpty[T]) NonEmpty[T] {
+	return New(x, ToList(xs))
+}
+
+// Use a list as a non-empty list, or get Nothing when it is empty.
+func FromList[T any](xs list.List[T]) maybe.Maybe[NonEmpty[T]] {
+	c := xs.Cons()
+	if c == nil {
+		return maybe.Nothing{}
+	}
+	return maybe.Just[NonEmpty[T]]{Value: New(c.A, c.B)}
+}
+
+// Convert a non-empty list into a list. This takes O(1) time.
+func ToList[T any](xs NonEmpty[T]) list.List[T] {
+	return list.Cons(xs.head, xs.rest())
+}
+
+// The tail of the zero value is nil.
+func (xs NonEmpty[T]) rest() list.List[T] {
+	if xs.tail == nil {
+		return list.Empty[T]()
+	}
+	return xs.tail
+}
+
+// Deconstruct
+
+// Extract the first element.
+func Head[T any](xs NonEmpty[T]) T {
+	return xs.head
+}
+
+// Extract the elements after the first, which may be none.
+func Tail[T any](xs NonEmpty[T]) list.List[T] {
+	return xs.rest()
+}
+
+// Extract the last element.
+func Last[T any](xs NonEmpty[T]) T {
+	return list.Foldl(func(x T, _ T) T { return x }, xs.head, xs.rest())
+}
+
+// Determine the length of a non-empty list, which is at least one.
+func Length[T any](xs NonEmpty[T]) basics.Int {
+	return 1 + list.Length(xs.rest())
+}
+
+// Find the maximum element.
+func Maximum[T basics.Comparable[T]](xs NonEmpty[T]) T {
+	return list.Foldl[T, T](basics.Max, xs.head, xs.rest()).T()
+}
+
+// Find the minimum element.
+func Minimum[T basics.Comparable[T]](xs NonEmpty[T]) T {
+	return list.Foldl[T, T](basics.Min, xs.head, xs.rest()).T()
+}
+
+// Reduce a non-empty list from the left, starting with the first element.
+//
+//	Foldl1(func(x, acc String) String { return acc + x }, New[String]("a", list.FromSlice([]String{"b", "c"}))) // "abc"
+func Foldl1[T any](f func(T, T) T, xs NonEmpty[T]) T {
+	return list.Foldl(f, xs.head, xs.rest())
+}
+
+// Reduce a non-empty list from the right, starting with the last element.
+//
+//	Foldr1(func(x, acc String) String { return acc + x }, New[String]("a", list.FromSlice([]String{"b", "c"}))) // "cba"
+func Foldr1[T any](f func(T, T) T, xs NonEmpty[T]) T {
+	c := list.Reverse(ToList(xs)).Cons()
+	return list.Foldl(f, c.A, c.B)
+}
+
+// Transform
+
+// Apply a function to every element.
+func Map[A, B any](f func(A) B, xs NonEmpty[A]) NonEmpty[B] {
+	return New(f(xs.head), list.Map(f, xs.rest()))
+}
+
+// Put two non-empty lists together.
+func Append[T any](xs NonEmpty[T], ys NonEmpty[T]) NonEmpty[T] {
+	return New(xs.head, list.Append(xs.rest(), ToList(ys)))
+}
+
+// Sort
+
+// Sort values from lowest to highest.
+func Sort[T basics.Comparable[T]](xs NonEmpty[T]) NonEmpty[T] {
+	return fromSorted(list.Sort(ToList(xs)))
+}
+
+// Sort values with a custom comparison function.
+func SortWith[T any](f func(a T, b T) basics.Order, xs NonEmpty[T]) NonEmpty[T] {
+	return fromSorted(list.SortWith(f, ToList(xs)))
+}
+
+// Sorting keeps every element, so the sorted list is never empty.
+func fromSorted[T any](xs list.List[T]) NonEmpty[T] {
+	c := xs.Cons()
+	return New(c.A, c.B)
+}
+
+// Group the elements of a list into runs, putting each element in the same group as the one before it
+// when the function gives true for the pair. Every group has at least one element.
+//
+//	GroupBy(func(a, b Int) bool { return a+1 == b }, list.FromSlice([]Int{1, 2, 3, 5, 6, 8})) // [[1, 2, 3], [5, 6], [8]]
+func GroupBy[T any](f func(T, T) bool, xs list.List[T]) list.List[NonEmpty[T]] {
+	return list.Foldr(
+		func(x T, groups list.List[NonEmpty[T]]) list.List[NonEmpty[T]] {
+			c := groups.Cons()
+			if c != nil && f(x, c.A.head) {
+				return list.Cons(Cons(x, c.A), c.B)
+			}
+			return list.Cons(Singleton(x), groups)
+		},
+		list.Empty[NonEmpty[T]](),
+		xs,
+	)
+}
+
+// Equality
+
+// Non-empty lists are equal when their elements are, compared with Eq.
+func (xs NonEmpty[T]) Equal(ys NonEmpty[T]) bool {
+	return basics.Eq(ToList(xs), ToList(ys))
+}
+
+// A non-empty list hashes the same as the list of its elements.
+func (xs NonEmpty[T]) Hash() uint64 {
+	return basics.Hash(ToList(xs))
+}
+
+// Encoding
+
+// A non-empty list is encoded as a JSON array.
+func (xs NonEmpty[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ToList(xs))
+}
+
+// NonEmpty is a struct, so it can be decoded with json.Unmarshal. Decoding an empty array is an error.
+func (xs *NonEmpty[T]) UnmarshalJSON(data []byte) error {
+	l, err := list.FromJSON[T](data)
+	if err != nil {
+		return err
+	}
+	c := l.Cons()
+	if c == nil {
+		return errors.New("nonempty: cannot decode an empty array")
+	}
+	*xs = New(c.A, c.B)
+	return nil
+}
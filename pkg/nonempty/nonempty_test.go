@@ -0,0 +1,172 @@
+package nonempty
+
+import (
+	"encoding/json"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/result"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ints(x basics.Int, xs ...basics.Int) NonEmpty[basics.Int] {
+	return New(x, list.FromSlice(xs))
+}
+
+func slice[T any](xs NonEmpty[T]) []T {
+	return list.ToSlice(ToList(xs))
+}
+
+func TestCreate(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Singleton, Cons and New", func(t *testing.T) {
+		asserts.Equal([]basics.Int{1}, slice(Singleton[basics.Int](1)))
+		asserts.Equal([]basics.Int{0, 1}, slice(Cons(0, Singleton[basics.Int](1))))
+		asserts.Equal([]basics.Int{1, 2, 3}, slice(ints(1, 2, 3)))
+		asserts.Equal([]basics.Int{1}, slice(New[basics.Int](1, nil)))
+	})
+
+	t.Run("FromList", func(t *testing.T) {
+		asserts.Equal(maybe.Nothing{}, FromList(list.Empty[basics.Int]()))
+		asserts.Equal(
+			maybe.Just[[]basics.Int]{Value: []basics.Int{1, 2}},
+			maybe.Map(slice[basics.Int], FromList(list.Range(1, 2))),
+		)
+	})
+
+	t.Run("The zero value has one element", func(t *testing.T) {
+		var SUT NonEmpty[basics.Int]
+
+		asserts.Equal([]basics.Int{0}, slice(SUT))
+		asserts.Equal(basics.Int(1), Length(SUT))
+		asserts.True(list.IsEmpty(Tail(SUT)))
+	})
+}
+
+func TestDeconstruct(t *testing.T) {
+	asserts := assert.New(t)
+	xs := ints(3, 1, 4, 1, 5)
+
+	t.Run("Head, Tail and Last", func(t *testing.T) {
+		asserts.Equal(basics.Int(3), Head(xs))
+		asserts.Equal([]basics.Int{1, 4, 1, 5}, list.ToSlice(Tail(xs)))
+		asserts.Equal(basics.Int(5), Last(xs))
+		asserts.Equal(basics.Int(7), Last(Singleton[basics.Int](7)))
+	})
+
+	t.Run("Length", func(t *testing.T) {
+		asserts.Equal(basics.Int(5), Length(xs))
+	})
+
+	t.Run("Maximum and Minimum", func(t *testing.T) {
+		asserts.Equal(basics.Int(5), Maximum(xs))
+		asserts.Equal(basics.Int(1), Minimum(xs))
+		asserts.Equal(basics.Int(2), Maximum(Singleton[basics.Int](2)))
+	})
+
+	t.Run("Foldl1 and Foldr1", func(t *testing.T) {
+		words := New[string]("a", list.FromSlice([]string{"b", "c"}))
+		join := func(x, acc string) string { return acc + x }
+
+		asserts.Equal("abc", Foldl1(join, words))
+		asserts.Equal("cba", Foldr1(join, words))
+		asserts.Equal("a", Foldr1(join, Singleton("a")))
+	})
+}
+
+func TestTransform(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Map", func(t *testing.T) {
+		SUT := Map(func(x basics.Int) basics.Int { return x * 2 }, ints(1, 2, 3))
+
+		asserts.Equal([]basics.Int{2, 4, 6}, slice(SUT))
+	})
+
+	t.Run("Append", func(t *testing.T) {
+		asserts.Equal([]basics.Int{1, 2, 3, 4}, slice(Append(ints(1, 2), ints(3, 4))))
+	})
+
+	t.Run("Sort and SortWith", func(t *testing.T) {
+		asserts.Equal([]basics.Int{1, 1, 3, 4, 5}, slice(Sort(ints(3, 1, 4, 1, 5))))
+		asserts.Equal(
+			[]basics.Int{5, 4, 3, 1, 1},
+			slice(SortWith(func(a, b basics.Int) basics.Order { return basics.Compare(b, a) }, ints(3, 1, 4, 1, 5))),
+		)
+	})
+
+	t.Run("GroupBy", func(t *testing.T) {
+		SUT := GroupBy(func(a, b basics.Int) bool { return a+1 == b }, list.FromSlice([]basics.Int{1, 2, 3, 5, 6, 8}))
+
+		asserts.Equal([][]basics.Int{{1, 2, 3}, {5, 6}, {8}}, list.ToSliceMap(slice[basics.Int], SUT))
+		asserts.True(list.IsEmpty(GroupBy(func(a, b basics.Int) bool { return true }, list.Empty[basics.Int]())))
+	})
+}
+
+func TestValidation(t *testing.T) {
+	asserts := assert.New(t)
+	validate := func(age basics.Int, name string) result.Result[NonEmpty[string], string] {
+		var problems []string
+		if age < 0 {
+			problems = append(problems, "age is negative")
+		}
+		if name == "" {
+			problems = append(problems, "name is empty")
+		}
+		return maybe.MaybeWith(
+			FromList(list.FromSlice(problems)),
+			func(j maybe.Just[NonEmpty[string]]) result.Result[NonEmpty[string], string] {
+				return result.Err[NonEmpty[string], string]{Err: j.Value}
+			},
+			func(maybe.Nothing) result.Result[NonEmpty[string], string] {
+				return result.Ok[NonEmpty[string], string]{Val: name}
+			},
+		)
+	}
+
+	t.Run("A failed validation carries at least one error", func(t *testing.T) {
+		SUT := validate(-1, "")
+
+		asserts.Equal(
+			result.Err[NonEmpty[string], string]{Err: New("age is negative", list.FromSlice([]string{"name is empty"}))},
+			SUT,
+		)
+		asserts.Equal(result.Ok[NonEmpty[string], string]{Val: "Ada"}, validate(30, "Ada"))
+	})
+}
+
+func TestEquality(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Equal and Hash", func(t *testing.T) {
+		asserts.True(basics.Eq(ints(1, 2), Cons(1, Singleton[basics.Int](2))))
+		asserts.False(basics.Eq(ints(1, 2), ints(1, 2, 3)))
+		asserts.Equal(basics.Hash(ints(1, 2)), basics.Hash(Cons(1, Singleton[basics.Int](2))))
+		asserts.Equal(basics.Hash(list.Range(1, 2)), basics.Hash(ints(1, 2)))
+	})
+}
+
+func TestJSON(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Encode and decode", func(t *testing.T) {
+		type payload struct {
+			Errors NonEmpty[string]
+		}
+		data, err := json.Marshal(payload{Errors: New("a", list.FromSlice([]string{"b"}))})
+		asserts.NoError(err)
+		asserts.JSONEq(`{"Errors":["a","b"]}`, string(data))
+
+		var SUT payload
+		asserts.NoError(json.Unmarshal(data, &SUT))
+		asserts.Equal([]string{"a", "b"}, slice(SUT.Errors))
+	})
+
+	t.Run("Decoding an empty array fails", func(t *testing.T) {
+		var SUT NonEmpty[string]
+
+		asserts.Error(json.Unmarshal([]byte(`[]`), &SUT))
+	})
+}
@@ -0,0 +1,1010 @@
+// Package advanced is the parser package with custom context and problem types,
+// inspired by the Elm Parser.Advanced module.
+//
+// Parser[C, X, T] is parameterised over a context type C and a problem type X.
+// Contexts are pushed with InContext and every DeadEnd carries the stack of
+// contexts it was in, so you can render errors like "I got stuck while parsing
+// a function body" with the exact row and column of each step.
+//
+// Rows and columns start at 1 and columns count characters, not bytes.
+// Offsets are byte offsets into the source string.
+package advanced
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/char"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/result"
+	"github.com/Confidenceman02/scion-tools/pkg/set"
+	s "github.com/Confidenceman02/scion-tools/pkg/string"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// A Parser knows how to turn a String into a value of type T.
+// It can get stuck with problems of type X while in contexts of type C.
+type Parser[C, X, T any] interface {
+	parser() *parser[C, X, T]
+}
+
+/*
+Retrieve the internal parser
+*/
+func (p *parser[C, X, T]) parser() *parser[C, X, T] {
+	return p
+}
+
+type parser[C, X, T any] struct {
+	parse func(state[C]) pStep[C, X, T]
+}
+
+// Unit is used for parsers that only consume input, like Symbol and Spaces.
+type Unit = struct{}
+
+type state[C any] struct {
+	src     string
+	offset  int
+	indent  int
+	context list.List[Located[C]]
+	row     int
+	col     int
+}
+
+// The outcome of a single parse. Progress records whether any input was consumed,
+// which decides if OneOf is allowed to try the next alternative.
+type pStep[C, X, T any] struct {
+	good     bool
+	progress bool
+	value    T
+	state    state[C]
+	bag      *bag[C, X]
+}
+
+func good[C, X, T any](progress bool, value T, s state[C]) pStep[C, X, T] {
+	return pStep[C, X, T]{good: true, progress: progress, value: value, state: s}
+}
+
+func bad[C, X, T any](progress bool, b *bag[C, X]) pStep[C, X, T] {
+	return pStep[C, X, T]{progress: progress, bag: b}
+}
+
+// PROBLEMS
+
+// A DeadEnd describes a place where parsing failed, the problem it ran into and
+// the stack of contexts it was in. The innermost context comes first.
+type DeadEnd[C, X any] struct {
+	Row          basics.Int
+	Col          basics.Int
+	Problem      X
+	ContextStack list.List[Located[C]]
+}
+
+// Located is a context along with the position where it was entered.
+type Located[C any] struct {
+	Row     basics.Int
+	Col     basics.Int
+	Context C
+}
+
+// A bag of dead ends. Appending is constant time, which keeps OneOf cheap
+// when many alternatives fail. A nil bag is empty.
+type bag[C, X any] struct {
+	left    *bag[C, X]
+	deadEnd *DeadEnd[C, X]
+	right   *bag[C, X]
+}
+
+func fromState[C, X any](s state[C], x X) *bag[C, X] {
+	return fromInfo(s.row, s.col, x, s.context)
+}
+
+func fromInfo[C, X any](row int, col int, x X, context list.List[Located[C]]) *bag[C, X] {
+	return &bag[C, X]{deadEnd: &DeadEnd[C, X]{Row: basics.Int(row), Col: basics.Int(col), Problem: x, ContextStack: context}}
+}
+
+func appendBag[C, X any](b1 *bag[C, X], b2 *bag[C, X]) *bag[C, X] {
+	return &bag[C, X]{left: b1, right: b2}
+}
+
+func bagToList[C, X any](b *bag[C, X], acc list.List[DeadEnd[C, X]]) list.List[DeadEnd[C, X]] {
+bagToListL:
+	for {
+		switch {
+		case b == nil:
+			return acc
+		case b.deadEnd != nil:
+			acc = list.Cons(*b.deadEnd, acc)
+			b = b.left
+			continue bagToListL
+		default:
+			acc = bagToList(b.right, acc)
+			b = b.left
+			continue bagToListL
+		}
+	}
+}
+
+// RUN
+
+// Try a parser. If it succeeds you get the value, otherwise you get the list of
+// dead ends that explain where and why it failed.
+func Run[C, X, T any](p Parser[C, X, T], src s.String) result.Result[list.List[DeadEnd[C, X]], T] {
+	step := p.parser().parse(state[C]{src: string(src), offset: 0, indent: 1, context: list.Empty[Located[C]](), row: 1, col: 1})
+	if step.good {
+		return result.Ok[list.List[DeadEnd[C, X]], T]{Val: step.value}
+	}
+	return result.Err[list.List[DeadEnd[C, X]], T]{Err: bagToList(step.bag, list.Empty[DeadEnd[C, X]]())}
+}
+
+// CONTEXT
+
+// Run a parser in a context. Any dead end it reaches will have the context,
+// and the position where the context was entered, on top of its ContextStack.
+func InContext[C, X, T any](context C, p Parser[C, X, T]) Parser[C, X, T] {
+	return &parser[C, X, T]{
+		parse: func(s0 state[C]) pStep[C, X, T] {
+			inner := s0
+			inner.context = list.Cons(Located[C]{Row: basics.Int(s0.row), Col: basics.Int(s0.col), Context: context}, s0.context)
+			step := p.parser().parse(inner)
+			if step.good {
+				step.state.context = s0.context
+			}
+			return step
+		},
+	}
+}
+
+// PRIMITIVES
+
+// A parser that succeeds without chomping any characters.
+func Succeed[C, X, T any](a T) Parser[C, X, T] {
+	return &parser[C, X, T]{
+		parse: func(s state[C]) pStep[C, X, T] {
+			return good[C, X](false, a, s)
+		},
+	}
+}
+
+// Indicate that a parser has reached a dead end with the given problem.
+func Problem[C, X, T any](x X) Parser[C, X, T] {
+	return &parser[C, X, T]{
+		parse: func(s state[C]) pStep[C, X, T] {
+			return bad[C, X, T](false, fromState(s, x))
+		},
+	}
+}
+
+// MAPPING
+
+// Transform the result of a parser.
+func Map[C, X, A, B any](f func(A) B, p Parser[C, X, A]) Parser[C, X, B] {
+	return &parser[C, X, B]{
+		parse: func(s0 state[C]) pStep[C, X, B] {
+			step := p.parser().parse(s0)
+			if !step.good {
+				return bad[C, X, B](step.progress, step.bag)
+			}
+			return good[C, X](step.progress, f(step.value), step.state)
+		},
+	}
+}
+
+// Run two parsers in sequence and combine their results.
+func Map2[C, X, A, B, value any](f func(A, B) value, pa Parser[C, X, A], pb Parser[C, X, B]) Parser[C, X, value] {
+	return &parser[C, X, value]{
+		parse: func(s0 state[C]) pStep[C, X, value] {
+			stepA := pa.parser().parse(s0)
+			if !stepA.good {
+				return bad[C, X, value](stepA.progress, stepA.bag)
+			}
+			stepB := pb.parser().parse(stepA.state)
+			if !stepB.good {
+				return bad[C, X, value](stepA.progress || stepB.progress, stepB.bag)
+			}
+			return good[C, X](stepA.progress || stepB.progress, f(stepA.value, stepB.value), stepB.state)
+		},
+	}
+}
+
+// Keep the value of a parser by handing it to a function parser. This is the |= operator from Elm.
+func Keep[C, X, A, B any](pf Parser[C, X, func(A) B], pa Parser[C, X, A]) Parser[C, X, B] {
+	return Map2(func(f func(A) B, a A) B { return f(a) }, pf, pa)
+}
+
+// Run a parser but ignore its value. This is the |. operator from Elm.
+func Skip[C, X, K, I any](keep Parser[C, X, K], ignore Parser[C, X, I]) Parser[C, X, K] {
+	return Map2(func(k K, _ I) K { return k }, keep, ignore)
+}
+
+// Run the first parser for its side effects and keep the value of the second.
+func ignoreThen[C, X, I, K any](ignore Parser[C, X, I], keep Parser[C, X, K]) Parser[C, X, K] {
+	return Map2(func(_ I, k K) K { return k }, ignore, keep)
+}
+
+// Parse one thing and then parse another thing based on the first value.
+func AndThen[C, X, A, B any](f func(A) Parser[C, X, B], p Parser[C, X, A]) Parser[C, X, B] {
+	return &parser[C, X, B]{
+		parse: func(s0 state[C]) pStep[C, X, B] {
+			stepA := p.parser().parse(s0)
+			if !stepA.good {
+				return bad[C, X, B](stepA.progress, stepA.bag)
+			}
+			stepB := f(stepA.value).parser().parse(stepA.state)
+			if !stepB.good {
+				return bad[C, X, B](stepA.progress || stepB.progress, stepB.bag)
+			}
+			return good[C, X](stepA.progress || stepB.progress, stepB.value, stepB.state)
+		},
+	}
+}
+
+// Helper to define recursive parsers.
+func Lazy[C, X, T any](thunk func() Parser[C, X, T]) Parser[C, X, T] {
+	return &parser[C, X, T]{
+		parse: func(s state[C]) pStep[C, X, T] {
+			return thunk().parser().parse(s)
+		},
+	}
+}
+
+// BACKTRACKING
+
+// Try a bunch of different parsers. If a parser fails without chomping any
+// characters, the next one is tried. If it chomped characters, OneOf fails
+// right away unless the parser was made Backtrackable.
+func OneOf[C, X, T any](parsers list.List[Parser[C, X, T]]) Parser[C, X, T] {
+	return &parser[C, X, T]{
+		parse: func(s0 state[C]) pStep[C, X, T] {
+			var b *bag[C, X]
+			for ps := parsers; ps.Cons() != nil; ps = ps.Cons().B {
+				step := ps.Cons().A.parser().parse(s0)
+				if step.good || step.progress {
+					return step
+				}
+				b = appendBag(b, step.bag)
+			}
+			return bad[C, X, T](false, b)
+		},
+	}
+}
+
+// Make a parser backtrackable, so OneOf can try other alternatives even when it chomped characters.
+func Backtrackable[C, X, T any](p Parser[C, X, T]) Parser[C, X, T] {
+	return &parser[C, X, T]{
+		parse: func(s0 state[C]) pStep[C, X, T] {
+			step := p.parser().parse(s0)
+			step.progress = false
+			return step
+		},
+	}
+}
+
+// Succeed and commit to the current path, as if characters had been chomped.
+// This is the opposite of Backtrackable.
+func Commit[C, X, T any](a T) Parser[C, X, T] {
+	return &parser[C, X, T]{
+		parse: func(s state[C]) pStep[C, X, T] {
+			return good[C, X](true, a, s)
+		},
+	}
+}
+
+// LOOPS
+
+// Step decides if a Loop keeps going or is done.
+type Step[S, A any] interface {
+	step() _step[S, A]
+}
+
+type _step[S, A any] struct{}
+
+func (st _step[S, A]) step() _step[S, A] {
+	return st
+}
+
+// Continue - Keep looping with a new state.
+type Continue[S, A any] struct {
+	_step[S, A]
+	State S
+}
+
+// Done - Stop looping and produce a value.
+type Done[S, A any] struct {
+	_step[S, A]
+	Val A
+}
+
+// A parser that can loop indefinitely. Each iteration runs the parser returned by
+// the callback, which decides to Continue with a new state or be Done.
+func Loop[C, X, S, A any](st S, callback func(S) Parser[C, X, Step[S, A]]) Parser[C, X, A] {
+	return &parser[C, X, A]{
+		parse: func(s0 state[C]) pStep[C, X, A] {
+			loopState, progress := st, false
+			for {
+				step := callback(loopState).parser().parse(s0)
+				progress = progress || step.progress
+				if !step.good {
+					return bad[C, X, A](progress, step.bag)
+				}
+				if done, ok := step.value.(Done[S, A]); ok {
+					return good[C, X](progress, done.Val, step.state)
+				}
+				loopState = step.value.(Continue[S, A]).State
+				s0 = step.state
+			}
+		},
+	}
+}
+
+// TOKENS
+
+// A Token is a string to match along with the problem to report when it is not there.
+type Token[X any] struct {
+	Str       s.String
+	Expecting X
+}
+
+// Parse symbols like ( and ,.
+func Symbol[C, X any](t Token[X]) Parser[C, X, Unit] {
+	return token[C](t)
+}
+
+func token[C, X any](t Token[X]) Parser[C, X, Unit] {
+	str := string(t.Str)
+	progress := str != ""
+	return &parser[C, X, Unit]{
+		parse: func(s state[C]) pStep[C, X, Unit] {
+			newOffset, newRow, newCol := isSubString(str, s.offset, s.row, s.col, s.src)
+			if newOffset == -1 {
+				return bad[C, X, Unit](false, fromState(s, t.Expecting))
+			}
+			return good[C, X](progress, Unit{}, state[C]{src: s.src, offset: newOffset, indent: s.indent, context: s.context, row: newRow, col: newCol})
+		},
+	}
+}
+
+// Parse keywords like let, case, and type. Unlike Symbol, the keyword must not be
+// followed by a letter, digit or underscore, so a "let" keyword does not match letters.
+func Keyword[C, X any](t Token[X]) Parser[C, X, Unit] {
+	str := string(t.Str)
+	progress := str != ""
+	return &parser[C, X, Unit]{
+		parse: func(s state[C]) pStep[C, X, Unit] {
+			newOffset, newRow, newCol := isSubString(str, s.offset, s.row, s.col, s.src)
+			if newOffset == -1 || isSubChar(isVarChar, newOffset, s.src) >= 0 {
+				return bad[C, X, Unit](false, fromState(s, t.Expecting))
+			}
+			return good[C, X](progress, Unit{}, state[C]{src: s.src, offset: newOffset, indent: s.indent, context: s.context, row: newRow, col: newCol})
+		},
+	}
+}
+
+func isVarChar(c char.Char) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || c == '_'
+}
+
+// Parse an end of input, reporting the given problem when there is more.
+func End[C, X any](x X) Parser[C, X, Unit] {
+	return &parser[C, X, Unit]{
+		parse: func(s state[C]) pStep[C, X, Unit] {
+			if len(s.src) == s.offset {
+				return good[C, X](false, Unit{}, s)
+			}
+			return bad[C, X, Unit](false, fromState(s, x))
+		},
+	}
+}
+
+// NUMBERS
+
+// Parse integers like 42. The first problem is reported when there is no number,
+// the second when the number is not an integer.
+func Int[C, X any](expecting X, invalid X) Parser[C, X, basics.Int] {
+	return Number[C](NumberConfig[X, basics.Int]{
+		Int:       result.Ok[X, func(basics.Int) basics.Int]{Val: basics.Identity[basics.Int]},
+		Hex:       result.Err[X, func(basics.Int) basics.Int]{Err: invalid},
+		Octal:     result.Err[X, func(basics.Int) basics.Int]{Err: invalid},
+		Binary:    result.Err[X, func(basics.Int) basics.Int]{Err: invalid},
+		Float:     result.Err[X, func(basics.Float) basics.Int]{Err: invalid},
+		Invalid:   invalid,
+		Expecting: expecting,
+	})
+}
+
+// Parse floats like 4.5 or 1e10. Integers like 42 are accepted as well.
+func Float[C, X any](expecting X, invalid X) Parser[C, X, basics.Float] {
+	return Number[C](NumberConfig[X, basics.Float]{
+		Int:       result.Ok[X, func(basics.Int) basics.Float]{Val: func(i basics.Int) basics.Float { return basics.Float(i) }},
+		Hex:       result.Err[X, func(basics.Int) basics.Float]{Err: invalid},
+		Octal:     result.Err[X, func(basics.Int) basics.Float]{Err: invalid},
+		Binary:    result.Err[X, func(basics.Int) basics.Float]{Err: invalid},
+		Float:     result.Ok[X, func(basics.Float) basics.Float]{Val: basics.Identity[basics.Float]},
+		Invalid:   invalid,
+		Expecting: expecting,
+	})
+}
+
+// NumberConfig says how to handle each kind of number. An Err rejects that kind of number
+// with the given problem. Invalid is reported for malformed numbers and integers too big
+// for an Int, and Expecting when there is no number at all.
+type NumberConfig[X, T any] struct {
+	Int       result.Result[X, func(basics.Int) T]
+	Hex       result.Result[X, func(basics.Int) T]
+	Octal     result.Result[X, func(basics.Int) T]
+	Binary    result.Result[X, func(basics.Int) T]
+	Float     result.Result[X, func(basics.Float) T]
+	Invalid   X
+	Expecting X
+}
+
+// Parse a bunch of different kinds of numbers without backtracking.
+// Hexadecimal numbers look like 0x1F, octal numbers 0o17 and binary numbers 0b101.
+func Number[C, X, T any](c NumberConfig[X, T]) Parser[C, X, T] {
+	return &parser[C, X, T]{
+		parse: func(s state[C]) pStep[C, X, T] {
+			if isAsciiCode('0', s.offset, s.src) {
+				zeroOffset := s.offset + 1
+				baseOffset := zeroOffset + 1
+				switch {
+				case isAsciiCode('x', zeroOffset, s.src):
+					end, n, fits := consumeBase16(baseOffset, s.src)
+					return finalizeInt(c.Invalid, c.Hex, baseOffset, end, n, fits, s)
+				case isAsciiCode('o', zeroOffset, s.src):
+					end, n, fits := consumeBase(8, baseOffset, s.src)
+					return finalizeInt(c.Invalid, c.Octal, baseOffset, end, n, fits, s)
+				case isAsciiCode('b', zeroOffset, s.src):
+					end, n, fits := consumeBase(2, baseOffset, s.src)
+					return finalizeInt(c.Invalid, c.Binary, baseOffset, end, n, fits, s)
+				default:
+					return finalizeFloat(c, zeroOffset, 0, true, s)
+				}
+			}
+			end, n, fits := consumeBase(10, s.offset, s.src)
+			return finalizeFloat(c, end, n, fits, s)
+		},
+	}
+}
+
+// An integer that does not fit in an Int is invalid, rather than wrapping around to some other number.
+func finalizeInt[C, X, T any](invalid X, handler result.Result[X, func(basics.Int) T], startOffset int, endOffset int, n int, fits bool, s state[C]) pStep[C, X, T] {
+	switch h := handler.(type) {
+	case result.Ok[X, func(basics.Int) T]:
+		if startOffset == endOffset {
+			return bad[C, X, T](s.offset < startOffset, fromState(s, invalid))
+		}
+		if !fits {
+			return bad[C, X, T](true, fromState(s, invalid))
+		}
+		return good[C, X](true, h.Val(basics.Int(n)), bumpOffset(endOffset, s))
+	case result.Err[X, func(basics.Int) T]:
+		return bad[C, X, T](true, fromState(s, h.Err))
+	default:
+		return bad[C, X, T](true, fromState(s, invalid))
+	}
+}
+
+func finalizeFloat[C, X, T any](c NumberConfig[X, T], intOffset int, n int, fits bool, s state[C]) pStep[C, X, T] {
+	floatOffset := consumeDotAndExp(intOffset, s.src)
+	switch {
+	case floatOffset < 0:
+		// A negative offset points at an exponent with no digits
+		return bad[C, X, T](true, fromInfo(s.row, s.col-(floatOffset+s.offset), c.Invalid, s.context))
+	case s.offset == floatOffset:
+		return bad[C, X, T](false, fromState(s, c.Expecting))
+	case intOffset == floatOffset:
+		return finalizeInt(c.Invalid, c.Int, s.offset, intOffset, n, fits, s)
+	}
+	var toValue func(basics.Float) T
+	switch f := c.Float.(type) {
+	case result.Ok[X, func(basics.Float) T]:
+		toValue = f.Val
+	case result.Err[X, func(basics.Float) T]:
+		return bad[C, X, T](true, fromState(s, f.Err))
+	default:
+		return bad[C, X, T](true, fromState(s, c.Invalid))
+	}
+	f, err := strconv.ParseFloat(s.src[s.offset:floatOffset], 32)
+	if err != nil {
+		return bad[C, X, T](true, fromState(s, c.Invalid))
+	}
+	return good[C, X](true, toValue(basics.Float(f)), bumpOffset(floatOffset, s))
+}
+
+func consumeDotAndExp(offset int, src string) int {
+	if isAsciiCode('.', offset, src) {
+		return consumeExp(chompBase10(offset+1, src), src)
+	}
+	return consumeExp(offset, src)
+}
+
+func consumeExp(offset int, src string) int {
+	if isAsciiCode('e', offset, src) || isAsciiCode('E', offset, src) {
+		eOffset := offset + 1
+		expOffset := eOffset
+		if isAsciiCode('+', eOffset, src) || isAsciiCode('-', eOffset, src) {
+			expOffset = eOffset + 1
+		}
+		newOffset := chompBase10(expOffset, src)
+		if expOffset == newOffset {
+			return -newOffset
+		}
+		return newOffset
+	}
+	return offset
+}
+
+func chompBase10(offset int, src string) int {
+	for offset < len(src) && '0' <= src[offset] && src[offset] <= '9' {
+		offset++
+	}
+	return offset
+}
+
+// Consume the digits of a number, also reporting if the number fits in an int.
+func consumeBase(base int, offset int, src string) (int, int, bool) {
+	total, fits := 0, true
+	for ; offset < len(src); offset++ {
+		digit := int(src[offset]) - '0'
+		if digit < 0 || base <= digit {
+			break
+		}
+		total, fits = addDigit(base, total, digit, fits)
+	}
+	return offset, total, fits
+}
+
+func consumeBase16(offset int, src string) (int, int, bool) {
+	total, fits := 0, true
+	for ; offset < len(src); offset++ {
+		code := src[offset]
+		switch {
+		case '0' <= code && code <= '9':
+			total, fits = addDigit(16, total, int(code-'0'), fits)
+		case 'A' <= code && code <= 'F':
+			total, fits = addDigit(16, total, int(code-'A')+10, fits)
+		case 'a' <= code && code <= 'f':
+			total, fits = addDigit(16, total, int(code-'a')+10, fits)
+		default:
+			return offset, total, fits
+		}
+	}
+	return offset, total, fits
+}
+
+func addDigit(base int, total int, digit int, fits bool) (int, bool) {
+	if !fits || total > (math.MaxInt-digit)/base {
+		return 0, false
+	}
+	return base*total + digit, true
+}
+
+func bumpOffset[C any](newOffset int, s state[C]) state[C] {
+	return state[C]{src: s.src, offset: newOffset, indent: s.indent, context: s.context, row: s.row, col: s.col + (newOffset - s.offset)}
+}
+
+// VARIABLES
+
+// VariableConfig describes what a variable looks like. Names in Reserved are
+// rejected with the Expecting problem.
+type VariableConfig[X any] struct {
+	Start     func(char.Char) bool
+	Inner     func(char.Char) bool
+	Reserved  set.Set[s.String]
+	Expecting X
+}
+
+// Parse variable names like userName or _tmp.
+func Variable[C, X any](c VariableConfig[X]) Parser[C, X, s.String] {
+	return &parser[C, X, s.String]{
+		parse: func(s0 state[C]) pStep[C, X, s.String] {
+			firstOffset := isSubChar(c.Start, s0.offset, s0.src)
+			if firstOffset == -1 {
+				return bad[C, X, s.String](false, fromState(s0, c.Expecting))
+			}
+			var s1 state[C]
+			if firstOffset == -2 {
+				s1 = chompWhileHelp(c.Inner, s0.offset+1, s0.row+1, 1, s0)
+			} else {
+				s1 = chompWhileHelp(c.Inner, firstOffset, s0.row, s0.col+1, s0)
+			}
+			name := s.String(s0.src[s0.offset:s1.offset])
+			if c.Reserved != nil && set.Member(name, c.Reserved) {
+				return bad[C, X, s.String](false, fromState(s0, c.Expecting))
+			}
+			return good[C, X](true, name, s1)
+		},
+	}
+}
+
+// SEQUENCES
+
+// Trailing says whether a Sequence allows a separator after the last item.
+type Trailing interface {
+	trailing() _trailing
+}
+
+type _trailing struct{}
+
+func (t _trailing) trailing() _trailing {
+	return t
+}
+
+// Forbidden - A trailing separator is an error, like [1,2,3].
+type Forbidden struct{ _trailing }
+
+// Optional - A trailing separator may appear, like [1,2,3,].
+type Optional struct{ _trailing }
+
+// Mandatory - Every item is followed by a separator, like {a;b;c;}.
+type Mandatory struct{ _trailing }
+
+// SequenceConfig describes a sequence of items like [1, 2, 3].
+type SequenceConfig[C, X, T any] struct {
+	Start     Token[X]
+	Separator Token[X]
+	End       Token[X]
+	Spaces    Parser[C, X, Unit]
+	Item      Parser[C, X, T]
+	Trailing  Trailing
+}
+
+// Handle things like lists and records that have a start, a separator between items and an end.
+func Sequence[C, X, T any](c SequenceConfig[C, X, T]) Parser[C, X, list.List[T]] {
+	return ignoreThen(
+		token[C](c.Start),
+		ignoreThen(c.Spaces, sequenceEnd(token[C](c.End), c.Spaces, c.Item, token[C](c.Separator), c.Trailing)),
+	)
+}
+
+func sequenceEnd[C, X, T any](ender Parser[C, X, Unit], ws Parser[C, X, Unit], parseItem Parser[C, X, T], sep Parser[C, X, Unit], trailing Trailing) Parser[C, X, list.List[T]] {
+	chompRest := func(item T) Parser[C, X, list.List[T]] {
+		switch trailing.(type) {
+		case Optional:
+			return Loop(list.Singleton(item), func(revItems list.List[T]) Parser[C, X, Step[list.List[T], list.List[T]]] {
+				return sequenceEndOptional(ender, ws, parseItem, sep, revItems)
+			})
+		case Mandatory:
+			return Skip(
+				ignoreThen(ws, ignoreThen(sep, ignoreThen(ws,
+					Loop(list.Singleton(item), func(revItems list.List[T]) Parser[C, X, Step[list.List[T], list.List[T]]] {
+						return sequenceEndMandatory(ws, parseItem, sep, revItems)
+					}),
+				))),
+				ender,
+			)
+		default:
+			return Loop(list.Singleton(item), func(revItems list.List[T]) Parser[C, X, Step[list.List[T], list.List[T]]] {
+				return sequenceEndForbidden(ender, ws, parseItem, sep, revItems)
+			})
+		}
+	}
+	return OneOf(list.FromSlice([]Parser[C, X, list.List[T]]{
+		AndThen(chompRest, parseItem),
+		Map(func(_ Unit) list.List[T] { return list.Empty[T]() }, ender),
+	}))
+}
+
+func sequenceEndForbidden[C, X, T any](ender Parser[C, X, Unit], ws Parser[C, X, Unit], parseItem Parser[C, X, T], sep Parser[C, X, Unit], revItems list.List[T]) Parser[C, X, Step[list.List[T], list.List[T]]] {
+	return ignoreThen(ws, OneOf(list.FromSlice([]Parser[C, X, Step[list.List[T], list.List[T]]]{
+		ignoreThen(sep, ignoreThen(ws, Map(continueWith(revItems), parseItem))),
+		Map(doneWith[T, Unit](revItems), ender),
+	})))
+}
+
+func sequenceEndOptional[C, X, T any](ender Parser[C, X, Unit], ws Parser[C, X, Unit], parseItem Parser[C, X, T], sep Parser[C, X, Unit], revItems list.List[T]) Parser[C, X, Step[list.List[T], list.List[T]]] {
+	parseEnd := Map(doneWith[T, Unit](revItems), ender)
+	return ignoreThen(ws, OneOf(list.FromSlice([]Parser[C, X, Step[list.List[T], list.List[T]]]{
+		ignoreThen(sep, ignoreThen(ws, OneOf(list.FromSlice([]Parser[C, X, Step[list.List[T], list.List[T]]]{
+			Map(continueWith(revItems), parseItem),
+			parseEnd,
+		})))),
+		parseEnd,
+	})))
+}
+
+func sequenceEndMandatory[C, X, T any](ws Parser[C, X, Unit], parseItem Parser[C, X, T], sep Parser[C, X, Unit], revItems list.List[T]) Parser[C, X, Step[list.List[T], list.List[T]]] {
+	return OneOf(list.FromSlice([]Parser[C, X, Step[list.List[T], list.List[T]]]{
+		Map(continueWith(revItems), Skip(parseItem, Skip(ws, Skip(sep, ws)))),
+		Map(doneWith[T, Unit](revItems), Succeed[C, X](Unit{})),
+	}))
+}
+
+func continueWith[T any](revItems list.List[T]) func(T) Step[list.List[T], list.List[T]] {
+	return func(item T) Step[list.List[T], list.List[T]] {
+		return Continue[list.List[T], list.List[T]]{State: list.Cons(item, revItems)}
+	}
+}
+
+func doneWith[T, A any](revItems list.List[T]) func(A) Step[list.List[T], list.List[T]] {
+	return func(_ A) Step[list.List[T], list.List[T]] {
+		return Done[list.List[T], list.List[T]]{Val: list.Reverse(revItems)}
+	}
+}
+
+// WHITESPACE
+
+// Parse zero or more ' ', '\n', and '\r' characters.
+func Spaces[C, X any]() Parser[C, X, Unit] {
+	return ChompWhile[C, X](func(c char.Char) bool {
+		return c == ' ' || c == '\n' || c == '\r'
+	})
+}
+
+// Parse single line comments that start with the given token, stopping before the newline.
+func LineComment[C, X any](start Token[X]) Parser[C, X, Unit] {
+	return ignoreThen(token[C](start), ChompUntilEndOr[C, X]("\n"))
+}
+
+// Nesting says whether multi-line comments can contain other multi-line comments.
+type Nesting interface {
+	nesting() _nesting
+}
+
+type _nesting struct{}
+
+func (n _nesting) nesting() _nesting {
+	return n
+}
+
+// NotNestable - The comment ends at the first closing string.
+type NotNestable struct{ _nesting }
+
+// Nestable - Every opening string needs its own closing string.
+type Nestable struct{ _nesting }
+
+// Parse multi-line comments, including the opening and closing tokens.
+func MultiComment[C, X any](open Token[X], close Token[X], nesting Nesting) Parser[C, X, Unit] {
+	if _, ok := nesting.(Nestable); ok {
+		return nestableComment[C](open, close)
+	}
+	return ignoreThen(token[C](open), ignoreThen(ChompUntil[C](close), token[C](close)))
+}
+
+func nestableComment[C, X any](open Token[X], close Token[X]) Parser[C, X, Unit] {
+	if open.Str == "" {
+		return Problem[C, X, Unit](open.Expecting)
+	}
+	if close.Str == "" {
+		return Problem[C, X, Unit](close.Expecting)
+	}
+	openChar, _ := utf8.DecodeRuneInString(string(open.Str))
+	closeChar, _ := utf8.DecodeRuneInString(string(close.Str))
+	isNotRelevant := func(c char.Char) bool { return rune(c) != openChar && rune(c) != closeChar }
+	chompOpen := token[C](open)
+	chompClose := token[C](close)
+	return ignoreThen(chompOpen, Loop(1, func(nestLevel int) Parser[C, X, Step[int, Unit]] {
+		return ignoreThen(ChompWhile[C, X](isNotRelevant), OneOf(list.FromSlice([]Parser[C, X, Step[int, Unit]]{
+			Map(func(_ Unit) Step[int, Unit] {
+				if nestLevel == 1 {
+					return Done[int, Unit]{Val: Unit{}}
+				}
+				return Continue[int, Unit]{State: nestLevel - 1}
+			}, chompClose),
+			Map(func(_ Unit) Step[int, Unit] { return Continue[int, Unit]{State: nestLevel + 1} }, chompOpen),
+			Map(func(_ Unit) Step[int, Unit] { return Continue[int, Unit]{State: nestLevel} }, ChompIf[C](isAnyChar, close.Expecting)),
+		})))
+	}))
+}
+
+func isAnyChar(_ char.Char) bool {
+	return true
+}
+
+// CHOMPING
+
+// Chomp one character if it passes the test, reporting the given problem otherwise.
+func ChompIf[C, X any](isGood func(char.Char) bool, expecting X) Parser[C, X, Unit] {
+	return &parser[C, X, Unit]{
+		parse: func(s state[C]) pStep[C, X, Unit] {
+			newOffset := isSubChar(isGood, s.offset, s.src)
+			switch newOffset {
+			case -1:
+				return bad[C, X, Unit](false, fromState(s, expecting))
+			case -2:
+				return good[C, X](true, Unit{}, state[C]{src: s.src, offset: s.offset + 1, indent: s.indent, context: s.context, row: s.row + 1, col: 1})
+			default:
+				return good[C, X](true, Unit{}, state[C]{src: s.src, offset: newOffset, indent: s.indent, context: s.context, row: s.row, col: s.col + 1})
+			}
+		},
+	}
+}
+
+// Chomp zero or more characters if they pass the test.
+func ChompWhile[C, X any](isGood func(char.Char) bool) Parser[C, X, Unit] {
+	return &parser[C, X, Unit]{
+		parse: func(s state[C]) pStep[C, X, Unit] {
+			s1 := chompWhileHelp(isGood, s.offset, s.row, s.col, s)
+			return good[C, X](s.offset < s1.offset, Unit{}, s1)
+		},
+	}
+}
+
+func chompWhileHelp[C any](isGood func(char.Char) bool, offset int, row int, col int, s0 state[C]) state[C] {
+	for {
+		switch newOffset := isSubChar(isGood, offset, s0.src); newOffset {
+		case -1:
+			return state[C]{src: s0.src, offset: offset, indent: s0.indent, context: s0.context, row: row, col: col}
+		case -2:
+			offset, row, col = offset+1, row+1, 1
+		default:
+			offset, col = newOffset, col+1
+		}
+	}
+}
+
+// Chomp until you see the string of the given token. The string itself is not chomped,
+// and the token's problem is reported at the end of input if it never shows up.
+func ChompUntil[C, X any](t Token[X]) Parser[C, X, Unit] {
+	return &parser[C, X, Unit]{
+		parse: func(s state[C]) pStep[C, X, Unit] {
+			newOffset, newRow, newCol := findSubString(string(t.Str), s.offset, s.row, s.col, s.src)
+			if newOffset == -1 {
+				return bad[C, X, Unit](false, fromInfo(newRow, newCol, t.Expecting, s.context))
+			}
+			return good[C, X](s.offset < newOffset, Unit{}, state[C]{src: s.src, offset: newOffset, indent: s.indent, context: s.context, row: newRow, col: newCol})
+		},
+	}
+}
+
+// Chomp until you see a certain string or until you run out of input.
+// The string itself is not chomped.
+func ChompUntilEndOr[C, X any](str s.String) Parser[C, X, Unit] {
+	return &parser[C, X, Unit]{
+		parse: func(s state[C]) pStep[C, X, Unit] {
+			newOffset, newRow, newCol := findSubString(string(str), s.offset, s.row, s.col, s.src)
+			if newOffset == -1 {
+				newOffset = len(s.src)
+			}
+			return good[C, X](s.offset < newOffset, Unit{}, state[C]{src: s.src, offset: newOffset, indent: s.indent, context: s.context, row: newRow, col: newCol})
+		},
+	}
+}
+
+// Run a parser and get the String it chomped instead of its value.
+func GetChompedString[C, X, T any](p Parser[C, X, T]) Parser[C, X, s.String] {
+	return MapChompedString(func(str s.String, _ T) s.String { return str }, p)
+}
+
+// Run a parser and combine the String it chomped with its value.
+func MapChompedString[C, X, A, B any](f func(s.String, A) B, p Parser[C, X, A]) Parser[C, X, B] {
+	return &parser[C, X, B]{
+		parse: func(s0 state[C]) pStep[C, X, B] {
+			step := p.parser().parse(s0)
+			if !step.good {
+				return bad[C, X, B](step.progress, step.bag)
+			}
+			return good[C, X](step.progress, f(s.String(s0.src[s0.offset:step.state.offset]), step.value), step.state)
+		},
+	}
+}
+
+// INDENTATION
+
+// Get the current indentation, which starts at 1.
+func GetIndent[C, X any]() Parser[C, X, basics.Int] {
+	return &parser[C, X, basics.Int]{
+		parse: func(s state[C]) pStep[C, X, basics.Int] {
+			return good[C, X](false, basics.Int(s.indent), s)
+		},
+	}
+}
+
+// Run a parser with the given indentation, restoring the previous indentation afterwards.
+func WithIndent[C, X, T any](newIndent basics.Int, p Parser[C, X, T]) Parser[C, X, T] {
+	return &parser[C, X, T]{
+		parse: func(s0 state[C]) pStep[C, X, T] {
+			inner := s0
+			inner.indent = int(newIndent)
+			step := p.parser().parse(inner)
+			if step.good {
+				step.state.indent = s0.indent
+			}
+			return step
+		},
+	}
+}
+
+// POSITIONS
+
+// Get the current (row, col) position in the source.
+func GetPosition[C, X any]() Parser[C, X, tuple.Tuple2[basics.Int, basics.Int]] {
+	return &parser[C, X, tuple.Tuple2[basics.Int, basics.Int]]{
+		parse: func(s state[C]) pStep[C, X, tuple.Tuple2[basics.Int, basics.Int]] {
+			return good[C, X](false, tuple.Pair(basics.Int(s.row), basics.Int(s.col)), s)
+		},
+	}
+}
+
+// Get the current row, which starts at 1.
+func GetRow[C, X any]() Parser[C, X, basics.Int] {
+	return &parser[C, X, basics.Int]{
+		parse: func(s state[C]) pStep[C, X, basics.Int] {
+			return good[C, X](false, basics.Int(s.row), s)
+		},
+	}
+}
+
+// Get the current column, which starts at 1.
+func GetCol[C, X any]() Parser[C, X, basics.Int] {
+	return &parser[C, X, basics.Int]{
+		parse: func(s state[C]) pStep[C, X, basics.Int] {
+			return good[C, X](false, basics.Int(s.col), s)
+		},
+	}
+}
+
+// Get the current byte offset into the source.
+func GetOffset[C, X any]() Parser[C, X, basics.Int] {
+	return &parser[C, X, basics.Int]{
+		parse: func(s state[C]) pStep[C, X, basics.Int] {
+			return good[C, X](false, basics.Int(s.offset), s)
+		},
+	}
+}
+
+// Get the full source being parsed.
+func GetSource[C, X any]() Parser[C, X, s.String] {
+	return &parser[C, X, s.String]{
+		parse: func(st state[C]) pStep[C, X, s.String] {
+			return good[C, X](false, s.String(st.src), st)
+		},
+	}
+}
+
+// LOW LEVEL HELPERS
+
+func isAsciiCode(code byte, offset int, src string) bool {
+	return offset < len(src) && src[offset] == code
+}
+
+// Check the character at offset. Gives back the offset after the character,
+// -2 if the character was a newline or -1 if it failed the test.
+func isSubChar(isGood func(char.Char) bool, offset int, src string) int {
+	if offset >= len(src) {
+		return -1
+	}
+	r, size := utf8.DecodeRuneInString(src[offset:])
+	if !isGood(char.Char(r)) {
+		return -1
+	}
+	if r == '\n' {
+		return -2
+	}
+	return offset + size
+}
+
+// Check if smallString appears at offset, giving back the new offset, row and col.
+// The offset is -1 when there is no match.
+func isSubString(smallString string, offset int, row int, col int, bigString string) (int, int, int) {
+	if !strings.HasPrefix(bigString[offset:], smallString) {
+		return -1, row, col
+	}
+	row, col = advance(smallString, row, col)
+	return offset + len(smallString), row, col
+}
+
+// Find the next smallString at or after offset, giving back its offset and position.
+// The offset is -1 when there is no match and the position is then the end of bigString.
+func findSubString(smallString string, offset int, row int, col int, bigString string) (int, int, int) {
+	idx := strings.Index(bigString[offset:], smallString)
+	if idx < 0 {
+		row, col = advance(bigString[offset:], row, col)
+		return -1, row, col
+	}
+	row, col = advance(bigString[offset:offset+idx], row, col)
+	return offset + idx, row, col
+}
+
+func advance(chomped string, row int, col int) (int, int) {
+	for _, r := range chomped {
+		if r == '\n' {
+			row, col = row+1, 1
+		} else {
+			col++
+		}
+	}
+	return row, col
+}
@@ -0,0 +1,225 @@
+package advanced
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/char"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/result"
+	"github.com/Confidenceman02/scion-tools/pkg/set"
+	s "github.com/Confidenceman02/scion-tools/pkg/string"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"strings"
+	"testing"
+)
+
+type context string
+
+type problem string
+
+const (
+	definition context = "definition"
+	body       context = "function body"
+	list_      context = "list"
+)
+
+func ok[T any](v T) result.Result[list.List[DeadEnd[context, problem]], T] {
+	return result.Ok[list.List[DeadEnd[context, problem]], T]{Val: v}
+}
+
+func deadEnds[T any](ds ...DeadEnd[context, problem]) result.Result[list.List[DeadEnd[context, problem]], T] {
+	return result.Err[list.List[DeadEnd[context, problem]], T]{Err: list.FromSlice(ds)}
+}
+
+func stack(ls ...Located[context]) list.List[Located[context]] {
+	return list.FromSlice(ls)
+}
+
+func sym(str s.String) Parser[context, problem, Unit] {
+	return Symbol[context](Token[problem]{Str: str, Expecting: problem("expecting " + str)})
+}
+
+var spaces = Spaces[context, problem]()
+
+var integer = Int[context, problem]("expecting int", "invalid int")
+
+var name = Variable[context](VariableConfig[problem]{
+	Start:     func(c char.Char) bool { return 'a' <= c && c <= 'z' },
+	Inner:     func(c char.Char) bool { return 'a' <= c && c <= 'z' || char.IsDigit(c) },
+	Reserved:  set.FromList(list.FromSlice([]s.String{"let", "in"})),
+	Expecting: "expecting name",
+})
+
+// let <name> = <int>
+var letDefinition = InContext(definition, Keep(
+	Map(func(n s.String) func(basics.Int) s.String {
+		return func(i basics.Int) s.String { return n + "=" + s.FromInt(i) }
+	}, ignoreThen(Keyword[context](Token[problem]{Str: "let", Expecting: "expecting let"}), ignoreThen(spaces, Skip(Skip(Skip(name, spaces), sym("=")), spaces)))),
+	InContext(body, integer),
+))
+
+func TestContexts(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Succeeds", func(t *testing.T) {
+		asserts.Equal(ok[s.String]("x=42"), Run(letDefinition, "let x = 42"))
+	})
+	t.Run("Dead ends carry the context stack", func(t *testing.T) {
+		asserts.Equal(
+			deadEnds[s.String](DeadEnd[context, problem]{
+				Row:     2,
+				Col:     3,
+				Problem: "expecting int",
+				ContextStack: stack(
+					Located[context]{Row: 2, Col: 3, Context: body},
+					Located[context]{Row: 1, Col: 1, Context: definition},
+				),
+			}),
+			Run(letDefinition, "let x =\n  oops"),
+		)
+	})
+	t.Run("Contexts are popped after success", func(t *testing.T) {
+		SUT := Skip(letDefinition, ignoreThen(spaces, sym(";")))
+
+		asserts.Equal(
+			deadEnds[s.String](DeadEnd[context, problem]{Row: 1, Col: 11, Problem: "expecting ;", ContextStack: stack()}),
+			Run(SUT, "let x = 42"),
+		)
+	})
+	t.Run("Reserved names fail in context", func(t *testing.T) {
+		asserts.Equal(
+			deadEnds[s.String](DeadEnd[context, problem]{
+				Row:          1,
+				Col:          5,
+				Problem:      "expecting name",
+				ContextStack: stack(Located[context]{Row: 1, Col: 1, Context: definition}),
+			}),
+			Run(letDefinition, "let in = 4"),
+		)
+	})
+	t.Run("Sequences report the context they are in", func(t *testing.T) {
+		SUT := InContext(list_, Sequence(SequenceConfig[context, problem, basics.Int]{
+			Start:     Token[problem]{Str: "[", Expecting: "expecting ["},
+			Separator: Token[problem]{Str: ",", Expecting: "expecting ,"},
+			End:       Token[problem]{Str: "]", Expecting: "expecting ]"},
+			Spaces:    spaces,
+			Item:      integer,
+			Trailing:  Forbidden{},
+		}))
+		inList := stack(Located[context]{Row: 1, Col: 1, Context: list_})
+
+		asserts.Equal(ok(list.FromSlice([]basics.Int{1, 2, 3})), Run(SUT, "[1, 2, 3]"))
+		asserts.Equal(
+			deadEnds[list.List[basics.Int]](
+				DeadEnd[context, problem]{Row: 1, Col: 6, Problem: "expecting ,", ContextStack: inList},
+				DeadEnd[context, problem]{Row: 1, Col: 6, Problem: "expecting ]", ContextStack: inList},
+			),
+			Run(SUT, "[1, 2"),
+		)
+	})
+}
+
+func TestCustomProblems(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Problem", func(t *testing.T) {
+		asserts.Equal(
+			deadEnds[Unit](DeadEnd[context, problem]{Row: 1, Col: 1, Problem: "custom", ContextStack: stack()}),
+			Run(Problem[context, problem, Unit]("custom"), ""),
+		)
+	})
+	t.Run("Int reports invalid numbers", func(t *testing.T) {
+		asserts.Equal(
+			deadEnds[basics.Int](DeadEnd[context, problem]{Row: 1, Col: 1, Problem: "invalid int", ContextStack: stack()}),
+			Run(integer, "1.5"),
+		)
+		asserts.Equal(
+			deadEnds[basics.Int](DeadEnd[context, problem]{Row: 1, Col: 1, Problem: "expecting int", ContextStack: stack()}),
+			Run(integer, "x"),
+		)
+	})
+	t.Run("Number reports integers that do not fit in an Int as invalid", func(t *testing.T) {
+		SUT := Number[context](NumberConfig[problem, basics.Int]{
+			Int:       result.Ok[problem, func(basics.Int) basics.Int]{Val: basics.Identity[basics.Int]},
+			Hex:       result.Ok[problem, func(basics.Int) basics.Int]{Val: basics.Identity[basics.Int]},
+			Octal:     result.Ok[problem, func(basics.Int) basics.Int]{Val: basics.Identity[basics.Int]},
+			Binary:    result.Ok[problem, func(basics.Int) basics.Int]{Val: basics.Identity[basics.Int]},
+			Float:     result.Err[problem, func(basics.Float) basics.Int]{Err: "no float please"},
+			Invalid:   "invalid number",
+			Expecting: "expecting number",
+		})
+		invalid := deadEnds[basics.Int](DeadEnd[context, problem]{Row: 1, Col: 1, Problem: "invalid number", ContextStack: stack()})
+
+		asserts.Equal(ok[basics.Int](math.MaxInt), Run(SUT, "9223372036854775807"))
+		asserts.Equal(ok[basics.Int](math.MaxInt), Run(SUT, "0x7fffffffffffffff"))
+		asserts.Equal(invalid, Run(SUT, "9223372036854775808"))
+		asserts.Equal(invalid, Run(SUT, "99999999999999999999"))
+		asserts.Equal(invalid, Run(SUT, "0x8000000000000000"))
+		asserts.Equal(invalid, Run(SUT, "0o1000000000000000000000"))
+		asserts.Equal(invalid, Run(SUT, s.String("0b"+strings.Repeat("1", 64))))
+	})
+	t.Run("Number reports the problem of a rejected kind", func(t *testing.T) {
+		SUT := Number[context](NumberConfig[problem, basics.Int]{
+			Int:       result.Ok[problem, func(basics.Int) basics.Int]{Val: basics.Identity[basics.Int]},
+			Hex:       result.Err[problem, func(basics.Int) basics.Int]{Err: "no hex please"},
+			Octal:     result.Err[problem, func(basics.Int) basics.Int]{Err: "no octal please"},
+			Binary:    result.Ok[problem, func(basics.Int) basics.Int]{Val: basics.Identity[basics.Int]},
+			Float:     result.Err[problem, func(basics.Float) basics.Int]{Err: "no float please"},
+			Invalid:   "invalid number",
+			Expecting: "expecting number",
+		})
+
+		asserts.Equal(ok[basics.Int](6), Run(SUT, "0b110"))
+		asserts.Equal(
+			deadEnds[basics.Int](DeadEnd[context, problem]{Row: 1, Col: 1, Problem: "no hex please", ContextStack: stack()}),
+			Run(SUT, "0xFF"),
+		)
+		asserts.Equal(
+			deadEnds[basics.Int](DeadEnd[context, problem]{Row: 1, Col: 1, Problem: "no float please", ContextStack: stack()}),
+			Run(SUT, "1.5"),
+		)
+	})
+	t.Run("ChompIf", func(t *testing.T) {
+		SUT := ChompIf[context](char.IsDigit, problem("expecting digit"))
+
+		asserts.Equal(
+			deadEnds[Unit](DeadEnd[context, problem]{Row: 1, Col: 1, Problem: "expecting digit", ContextStack: stack()}),
+			Run(SUT, "a"),
+		)
+	})
+	t.Run("Nestable comments report the closing token", func(t *testing.T) {
+		SUT := MultiComment[context](
+			Token[problem]{Str: "(*", Expecting: "expecting (*"},
+			Token[problem]{Str: "*)", Expecting: "expecting *)"},
+			Nestable{},
+		)
+
+		asserts.Equal(ok(Unit{}), Run(SUT, "(* outer (* inner *) *)"))
+		asserts.Equal(
+			deadEnds[Unit](
+				DeadEnd[context, problem]{Row: 1, Col: 10, Problem: "expecting *)", ContextStack: stack()},
+				DeadEnd[context, problem]{Row: 1, Col: 10, Problem: "expecting (*", ContextStack: stack()},
+				DeadEnd[context, problem]{Row: 1, Col: 10, Problem: "expecting *)", ContextStack: stack()},
+			),
+			Run(SUT, "(* outer "),
+		)
+	})
+}
+
+func TestPositions(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("GetOffset and GetCol", func(t *testing.T) {
+		SUT := ignoreThen(sym("¿"), Map2(func(o basics.Int, c basics.Int) [2]basics.Int { return [2]basics.Int{o, c} },
+			GetOffset[context, problem](),
+			GetCol[context, problem](),
+		))
+
+		asserts.Equal(ok([2]basics.Int{2, 2}), Run(SUT, "¿"))
+	})
+	t.Run("WithIndent", func(t *testing.T) {
+		SUT := WithIndent(8, GetIndent[context, problem]())
+
+		asserts.Equal(ok[basics.Int](8), Run(SUT, ""))
+	})
+}
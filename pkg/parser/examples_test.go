@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/result"
+)
+
+func ExampleRun() {
+	Run(Int, "123456") // Ok 123456
+	Run(Int, "3.1415") // Err [DeadEnd{Row: 1, Col: 1, Problem: ExpectingInt{}}]
+}
+
+func ExampleSequence() {
+	ints := Sequence(SequenceConfig[basics.Int]{
+		Start:     "[",
+		Separator: ",",
+		End:       "]",
+		Spaces:    Spaces,
+		Item:      Int,
+		Trailing:  Forbidden{},
+	})
+
+	Run(ints, "[1, 2, 3]") // Ok [1,2,3]
+	Run(ints, "[1, 2,]")   // Err [DeadEnd{Row: 1, Col: 7, Problem: ExpectingInt{}}]
+}
+
+func ExampleDeadEndsToString() {
+	r := Run(Skip(Int, Symbol(";")), "42")
+
+	result.ResultWith(
+		r,
+		func(e result.Err[list.List[DeadEnd], basics.Int]) any {
+			fmt.Println(DeadEndsToString(e.Err))
+			return nil
+		},
+		func(o result.Ok[list.List[DeadEnd], basics.Int]) any { return nil },
+	)
+
+	// Output:
+	// 1:3: Expecting the symbol `;`
+}
@@ -0,0 +1,481 @@
+// Package parser turns strings into structured data, inspired by the Elm parser package.
+//
+// Parsers are built from small pieces like Int, Symbol and Variable, then combined
+// with Keep, Skip, OneOf and friends. When parsing fails, Run reports every dead end
+// it reached along with the row and column where things went wrong.
+//
+// Rows and columns start at 1 and columns count characters, not bytes.
+// Offsets are byte offsets into the source string.
+//
+// This package is built on the advanced package. Use that instead when you want
+// your own problem type or contexts that explain what was being parsed.
+package parser
+
+import (
+	"fmt"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/char"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/parser/advanced"
+	"github.com/Confidenceman02/scion-tools/pkg/result"
+	"github.com/Confidenceman02/scion-tools/pkg/set"
+	s "github.com/Confidenceman02/scion-tools/pkg/string"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"strings"
+)
+
+// A Parser knows how to turn a String into a value of type T.
+// It is an advanced.Parser that is never in a context and reports a Reason when it gets stuck.
+type Parser[T any] interface {
+	advanced.Parser[never, Reason, T]
+}
+
+// Plain parsers have no contexts, so the context type has no use.
+type never struct{}
+
+// Unit is used for parsers that only consume input, like Symbol and Spaces.
+type Unit = advanced.Unit
+
+// PROBLEMS
+
+// A DeadEnd describes a place where parsing failed and the Reason it failed there.
+type DeadEnd struct {
+	Row     basics.Int
+	Col     basics.Int
+	Problem Reason
+}
+
+// Reason describes why a parser got stuck.
+type Reason interface {
+	reason() _reason
+}
+
+type _reason struct{}
+
+func (r _reason) reason() _reason {
+	return r
+}
+
+// Expecting - A Token did not match.
+type Expecting struct {
+	_reason
+	Token s.String
+}
+
+// ExpectingInt - Int did not find an integer.
+type ExpectingInt struct{ _reason }
+
+// ExpectingHex - Number did not find a hexadecimal number.
+type ExpectingHex struct{ _reason }
+
+// ExpectingOctal - Number did not find an octal number.
+type ExpectingOctal struct{ _reason }
+
+// ExpectingBinary - Number did not find a binary number.
+type ExpectingBinary struct{ _reason }
+
+// ExpectingFloat - Float did not find a floating point number.
+type ExpectingFloat struct{ _reason }
+
+// ExpectingNumber - Number did not find a number it was configured to accept.
+type ExpectingNumber struct{ _reason }
+
+// ExpectingVariable - Variable did not find a name, or found a reserved word.
+type ExpectingVariable struct{ _reason }
+
+// ExpectingSymbol - A Symbol did not match.
+type ExpectingSymbol struct {
+	_reason
+	Symbol s.String
+}
+
+// ExpectingKeyword - A Keyword did not match.
+type ExpectingKeyword struct {
+	_reason
+	Keyword s.String
+}
+
+// ExpectingEnd - End found more input.
+type ExpectingEnd struct{ _reason }
+
+// UnexpectedChar - ChompIf found a character it did not like.
+type UnexpectedChar struct{ _reason }
+
+// Custom - A failure created with Problem.
+type Custom struct {
+	_reason
+	Message s.String
+}
+
+// RUN
+
+// Try a parser. If it succeeds you get the value, otherwise you get the list of
+// dead ends that explain where and why it failed.
+func Run[T any](p Parser[T], src s.String) result.Result[list.List[DeadEnd], T] {
+	return result.MapError(
+		func(deadEnds list.List[advanced.DeadEnd[never, Reason]]) list.List[DeadEnd] {
+			return list.Map(problemToDeadEnd, deadEnds)
+		},
+		advanced.Run[never, Reason, T](p, src),
+	)
+}
+
+func problemToDeadEnd(d advanced.DeadEnd[never, Reason]) DeadEnd {
+	return DeadEnd{Row: d.Row, Col: d.Col, Problem: d.Problem}
+}
+
+// Turn the dead ends from Run into a String, one dead end per line.
+func DeadEndsToString(deadEnds list.List[DeadEnd]) s.String {
+	lines := list.ToSliceMap(func(d DeadEnd) string {
+		return fmt.Sprintf("%d:%d: %s", d.Row, d.Col, reasonToString(d.Problem))
+	}, deadEnds)
+	return s.String(strings.Join(lines, "\n"))
+}
+
+func reasonToString(r Reason) string {
+	switch r := r.(type) {
+	case Expecting:
+		return "Expecting `" + string(r.Token) + "`"
+	case ExpectingInt:
+		return "Expecting an INT"
+	case ExpectingHex:
+		return "Expecting a HEX number"
+	case ExpectingOctal:
+		return "Expecting an OCTAL number"
+	case ExpectingBinary:
+		return "Expecting a BINARY number"
+	case ExpectingFloat:
+		return "Expecting a FLOAT"
+	case ExpectingNumber:
+		return "Expecting a NUMBER"
+	case ExpectingVariable:
+		return "Expecting a VARIABLE"
+	case ExpectingSymbol:
+		return "Expecting the symbol `" + string(r.Symbol) + "`"
+	case ExpectingKeyword:
+		return "Expecting the keyword `" + string(r.Keyword) + "`"
+	case ExpectingEnd:
+		return "Expecting the END of input"
+	case UnexpectedChar:
+		return "Unexpected character"
+	case Custom:
+		return string(r.Message)
+	default:
+		return fmt.Sprintf("%v", r)
+	}
+}
+
+// PRIMITIVES
+
+// A parser that succeeds without chomping any characters.
+func Succeed[T any](a T) Parser[T] {
+	return advanced.Succeed[never, Reason](a)
+}
+
+// Indicate that a parser has reached a dead end.
+// It is best to use this as the last alternative of a OneOf.
+func Problem[T any](msg s.String) Parser[T] {
+	return advanced.Problem[never, Reason, T](Custom{Message: msg})
+}
+
+// MAPPING
+
+// Transform the result of a parser.
+func Map[A, B any](f func(A) B, p Parser[A]) Parser[B] {
+	return advanced.Map[never, Reason](f, p)
+}
+
+// Run two parsers in sequence and combine their results.
+func Map2[A, B, value any](f func(A, B) value, pa Parser[A], pb Parser[B]) Parser[value] {
+	return advanced.Map2[never, Reason](f, pa, pb)
+}
+
+// Keep the value of a parser by handing it to a function parser. This is the |= operator from Elm.
+//
+//	Keep(Skip(Succeed(func(x Int) Point {...}), Symbol("(")), Int)
+func Keep[A, B any](pf Parser[func(A) B], pa Parser[A]) Parser[B] {
+	return advanced.Keep[never, Reason, A, B](pf, pa)
+}
+
+// Run a parser but ignore its value. This is the |. operator from Elm.
+//
+//	Skip(Skip(Symbol("("), Spaces), Symbol(")"))
+func Skip[K, I any](keep Parser[K], ignore Parser[I]) Parser[K] {
+	return advanced.Skip[never, Reason, K, I](keep, ignore)
+}
+
+// Parse one thing and then parse another thing based on the first value.
+func AndThen[A, B any](f func(A) Parser[B], p Parser[A]) Parser[B] {
+	return advanced.AndThen(func(a A) advanced.Parser[never, Reason, B] { return f(a) }, advanced.Parser[never, Reason, A](p))
+}
+
+// Helper to define recursive parsers.
+func Lazy[T any](thunk func() Parser[T]) Parser[T] {
+	return advanced.Lazy(func() advanced.Parser[never, Reason, T] { return thunk() })
+}
+
+// BACKTRACKING
+
+// Try a bunch of different parsers. If a parser fails without chomping any
+// characters, the next one is tried. If it chomped characters, OneOf fails
+// right away unless the parser was made Backtrackable.
+func OneOf[T any](parsers list.List[Parser[T]]) Parser[T] {
+	return advanced.OneOf(list.Map(func(p Parser[T]) advanced.Parser[never, Reason, T] { return p }, parsers))
+}
+
+// Make a parser backtrackable, so OneOf can try other alternatives even when it chomped characters.
+func Backtrackable[T any](p Parser[T]) Parser[T] {
+	return advanced.Backtrackable[never, Reason, T](p)
+}
+
+// Succeed and commit to the current path, as if characters had been chomped.
+// This is the opposite of Backtrackable.
+func Commit[T any](a T) Parser[T] {
+	return advanced.Commit[never, Reason](a)
+}
+
+// LOOPS
+
+// Step decides if a Loop keeps going or is done.
+type Step[S, A any] interface {
+	step() _step[S, A]
+}
+
+type _step[S, A any] struct{}
+
+func (st _step[S, A]) step() _step[S, A] {
+	return st
+}
+
+// Continue - Keep looping with a new state.
+type Continue[S, A any] struct {
+	_step[S, A]
+	State S
+}
+
+// Done - Stop looping and produce a value.
+type Done[S, A any] struct {
+	_step[S, A]
+	Val A
+}
+
+// A parser that can loop indefinitely. Each iteration runs the parser returned by
+// the callback, which decides to Continue with a new state or be Done.
+func Loop[S, A any](st S, callback func(S) Parser[Step[S, A]]) Parser[A] {
+	return advanced.Loop(st, func(loopState S) advanced.Parser[never, Reason, advanced.Step[S, A]] {
+		return advanced.Map[never, Reason](toAdvancedStep[S, A], callback(loopState))
+	})
+}
+
+func toAdvancedStep[S, A any](st Step[S, A]) advanced.Step[S, A] {
+	if done, ok := st.(Done[S, A]); ok {
+		return advanced.Done[S, A]{Val: done.Val}
+	}
+	return advanced.Continue[S, A]{State: st.(Continue[S, A]).State}
+}
+
+// TOKENS
+
+// Parse exactly the given string, reporting Expecting when it is not there.
+func Token(str s.String) Parser[Unit] {
+	return advanced.Symbol[never](toToken(str))
+}
+
+func toToken(str s.String) advanced.Token[Reason] {
+	return advanced.Token[Reason]{Str: str, Expecting: Expecting{Token: str}}
+}
+
+// Parse symbols like ( and ,.
+func Symbol(str s.String) Parser[Unit] {
+	return advanced.Symbol[never](advanced.Token[Reason]{Str: str, Expecting: ExpectingSymbol{Symbol: str}})
+}
+
+// Parse keywords like let, case, and type. Unlike Symbol, the keyword must not be
+// followed by a letter, digit or underscore, so Keyword("let") does not match letters.
+func Keyword(kwd s.String) Parser[Unit] {
+	return advanced.Keyword[never](advanced.Token[Reason]{Str: kwd, Expecting: ExpectingKeyword{Keyword: kwd}})
+}
+
+// Parse an end of input, reporting ExpectingEnd when there is more.
+var End Parser[Unit] = advanced.End[never, Reason](ExpectingEnd{})
+
+// NUMBERS
+
+// Parse integers like 42. Hexadecimal, octal, binary and float syntax is rejected.
+var Int Parser[basics.Int] = advanced.Int[never, Reason](ExpectingInt{}, ExpectingInt{})
+
+// Parse floats like 4.5 or 1e10. Integers like 42 are accepted as well.
+var Float Parser[basics.Float] = advanced.Float[never, Reason](ExpectingFloat{}, ExpectingFloat{})
+
+// NumberConfig says which kinds of numbers Number accepts and how to turn each into a value.
+// Leave a field as Nothing (or unset) to reject that kind of number.
+type NumberConfig[T any] struct {
+	Int    maybe.Maybe[func(basics.Int) T]
+	Hex    maybe.Maybe[func(basics.Int) T]
+	Octal  maybe.Maybe[func(basics.Int) T]
+	Binary maybe.Maybe[func(basics.Int) T]
+	Float  maybe.Maybe[func(basics.Float) T]
+}
+
+// Parse a bunch of different kinds of numbers without backtracking.
+// Hexadecimal numbers look like 0x1F, octal numbers 0o17 and binary numbers 0b101.
+func Number[T any](c NumberConfig[T]) Parser[T] {
+	return advanced.Number[never](advanced.NumberConfig[Reason, T]{
+		Int:       expecting[func(basics.Int) T](ExpectingInt{}, c.Int),
+		Hex:       expecting[func(basics.Int) T](ExpectingHex{}, c.Hex),
+		Octal:     expecting[func(basics.Int) T](ExpectingOctal{}, c.Octal),
+		Binary:    expecting[func(basics.Int) T](ExpectingBinary{}, c.Binary),
+		Float:     expecting[func(basics.Float) T](ExpectingFloat{}, c.Float),
+		Invalid:   ExpectingNumber{},
+		Expecting: ExpectingNumber{},
+	})
+}
+
+// A kind of number that is rejected reports the reason for that kind.
+func expecting[F any](r Reason, m maybe.Maybe[F]) result.Result[Reason, F] {
+	if m == nil {
+		return result.Err[Reason, F]{Err: r}
+	}
+	return result.FromMaybe[Reason, F](r, m)
+}
+
+// VARIABLES
+
+// VariableConfig describes what a variable looks like. Names in Reserved are rejected.
+type VariableConfig struct {
+	Start    func(char.Char) bool
+	Inner    func(char.Char) bool
+	Reserved set.Set[s.String]
+}
+
+// Parse variable names like userName or _tmp.
+func Variable(c VariableConfig) Parser[s.String] {
+	return advanced.Variable[never](advanced.VariableConfig[Reason]{
+		Start:     c.Start,
+		Inner:     c.Inner,
+		Reserved:  c.Reserved,
+		Expecting: ExpectingVariable{},
+	})
+}
+
+// SEQUENCES
+
+// Trailing says whether a Sequence allows a separator after the last item.
+type Trailing = advanced.Trailing
+
+// Forbidden - A trailing separator is an error, like [1,2,3].
+type Forbidden = advanced.Forbidden
+
+// Optional - A trailing separator may appear, like [1,2,3,].
+type Optional = advanced.Optional
+
+// Mandatory - Every item is followed by a separator, like {a;b;c;}.
+type Mandatory = advanced.Mandatory
+
+// SequenceConfig describes a sequence of items like [1, 2, 3].
+type SequenceConfig[T any] struct {
+	Start     s.String
+	Separator s.String
+	End       s.String
+	Spaces    Parser[Unit]
+	Item      Parser[T]
+	Trailing  Trailing
+}
+
+// Handle things like lists and records that have a start, a separator between items and an end.
+func Sequence[T any](c SequenceConfig[T]) Parser[list.List[T]] {
+	return advanced.Sequence(advanced.SequenceConfig[never, Reason, T]{
+		Start:     advanced.Token[Reason]{Str: c.Start, Expecting: ExpectingSymbol{Symbol: c.Start}},
+		Separator: advanced.Token[Reason]{Str: c.Separator, Expecting: ExpectingSymbol{Symbol: c.Separator}},
+		End:       advanced.Token[Reason]{Str: c.End, Expecting: ExpectingSymbol{Symbol: c.End}},
+		Spaces:    c.Spaces,
+		Item:      c.Item,
+		Trailing:  c.Trailing,
+	})
+}
+
+// WHITESPACE
+
+// Parse zero or more ' ', '\n', and '\r' characters.
+var Spaces Parser[Unit] = advanced.Spaces[never, Reason]()
+
+// Parse single line comments that start with the given string, stopping before the newline.
+func LineComment(start s.String) Parser[Unit] {
+	return advanced.LineComment[never](toToken(start))
+}
+
+// Nesting says whether multi-line comments can contain other multi-line comments.
+type Nesting = advanced.Nesting
+
+// NotNestable - The comment ends at the first closing string.
+type NotNestable = advanced.NotNestable
+
+// Nestable - Every opening string needs its own closing string.
+type Nestable = advanced.Nestable
+
+// Parse multi-line comments, including the opening and closing strings.
+func MultiComment(open s.String, close s.String, nesting Nesting) Parser[Unit] {
+	return advanced.MultiComment[never](toToken(open), toToken(close), nesting)
+}
+
+// CHOMPING
+
+// Chomp one character if it passes the test, reporting UnexpectedChar otherwise.
+func ChompIf(isGood func(char.Char) bool) Parser[Unit] {
+	return advanced.ChompIf[never, Reason](isGood, UnexpectedChar{})
+}
+
+// Chomp zero or more characters if they pass the test.
+func ChompWhile(isGood func(char.Char) bool) Parser[Unit] {
+	return advanced.ChompWhile[never, Reason](isGood)
+}
+
+// Chomp until you see a certain string. The string itself is not chomped,
+// and it is a dead end if the string never shows up.
+func ChompUntil(str s.String) Parser[Unit] {
+	return advanced.ChompUntil[never](toToken(str))
+}
+
+// Chomp until you see a certain string or until you run out of input.
+// The string itself is not chomped.
+func ChompUntilEndOr(str s.String) Parser[Unit] {
+	return advanced.ChompUntilEndOr[never, Reason](str)
+}
+
+// Run a parser and get the String it chomped instead of its value.
+func GetChompedString[T any](p Parser[T]) Parser[s.String] {
+	return advanced.GetChompedString[never, Reason, T](p)
+}
+
+// Run a parser and combine the String it chomped with its value.
+func MapChompedString[A, B any](f func(s.String, A) B, p Parser[A]) Parser[B] {
+	return advanced.MapChompedString[never, Reason](f, p)
+}
+
+// INDENTATION
+
+// Get the current indentation, which starts at 1.
+var GetIndent Parser[basics.Int] = advanced.GetIndent[never, Reason]()
+
+// Run a parser with the given indentation, restoring the previous indentation afterwards.
+func WithIndent[T any](newIndent basics.Int, p Parser[T]) Parser[T] {
+	return advanced.WithIndent[never, Reason, T](newIndent, p)
+}
+
+// POSITIONS
+
+// Get the current (row, col) position in the source.
+var GetPosition Parser[tuple.Tuple2[basics.Int, basics.Int]] = advanced.GetPosition[never, Reason]()
+
+// Get the current row, which starts at 1.
+var GetRow Parser[basics.Int] = advanced.GetRow[never, Reason]()
+
+// Get the current column, which starts at 1.
+var GetCol Parser[basics.Int] = advanced.GetCol[never, Reason]()
+
+// Get the current byte offset into the source.
+var GetOffset Parser[basics.Int] = advanced.GetOffset[never, Reason]()
+
+// Get the full source being parsed.
+var GetSource Parser[s.String] = advanced.GetSource[never, Reason]()
@@ -0,0 +1,459 @@
+package parser
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/char"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	"github.com/Confidenceman02/scion-tools/pkg/result"
+	"github.com/Confidenceman02/scion-tools/pkg/set"
+	s "github.com/Confidenceman02/scion-tools/pkg/string"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ok[T any](v T) result.Result[list.List[DeadEnd], T] {
+	return result.Ok[list.List[DeadEnd], T]{Val: v}
+}
+
+func deadEnds[T any](ds ...DeadEnd) result.Result[list.List[DeadEnd], T] {
+	return result.Err[list.List[DeadEnd], T]{Err: list.FromSlice(ds)}
+}
+
+var typeVar = VariableConfig{
+	Start:    isLower,
+	Inner:    func(c char.Char) bool { return isAlphaNum(c) || c == '_' },
+	Reserved: set.FromList(list.FromSlice([]s.String{"let", "in", "case", "of"})),
+}
+
+func TestPrimitives(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Succeed", func(t *testing.T) {
+		asserts.Equal(ok[basics.Int](90210), Run(Succeed[basics.Int](90210), "look at my teeth"))
+	})
+	t.Run("Problem", func(t *testing.T) {
+		asserts.Equal(
+			deadEnds[basics.Int](DeadEnd{Row: 1, Col: 1, Problem: Custom{Message: "nope"}}),
+			Run(Problem[basics.Int]("nope"), "abc"),
+		)
+	})
+	t.Run("End", func(t *testing.T) {
+		asserts.Equal(ok(Unit{}), Run(End, ""))
+		asserts.Equal(deadEnds[Unit](DeadEnd{Row: 1, Col: 1, Problem: ExpectingEnd{}}), Run(End, "x"))
+	})
+}
+
+func TestNumbers(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Int", func(t *testing.T) {
+		asserts.Equal(ok[basics.Int](1234), Run(Int, "1234"))
+		asserts.Equal(ok[basics.Int](0), Run(Int, "0"))
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 1, Problem: ExpectingInt{}}), Run(Int, "1.34"))
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 1, Problem: ExpectingInt{}}), Run(Int, "0x1A"))
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 1, Problem: ExpectingInt{}}), Run(Int, "abc"))
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 1, Problem: ExpectingInt{}}), Run(Int, "99999999999999999999"))
+	})
+	t.Run("Float", func(t *testing.T) {
+		asserts.Equal(ok[basics.Float](123), Run(Float, "123"))
+		asserts.Equal(ok[basics.Float](3.1415), Run(Float, "3.1415"))
+		asserts.Equal(ok[basics.Float](0.1234), Run(Float, "0.1234"))
+		asserts.Equal(ok[basics.Float](1e-42), Run(Float, "1e-42"))
+		asserts.Equal(ok[basics.Float](6.022e23), Run(Float, "6.022e23"))
+		asserts.Equal(deadEnds[basics.Float](DeadEnd{Row: 1, Col: 1, Problem: ExpectingFloat{}}), Run(Float, "0x1A"))
+	})
+	t.Run("Float with a missing exponent points at the exponent", func(t *testing.T) {
+		asserts.Equal(deadEnds[basics.Float](DeadEnd{Row: 1, Col: 3, Problem: ExpectingFloat{}}), Run(Float, "1e"))
+	})
+	t.Run("Number", func(t *testing.T) {
+		SUT := Number(NumberConfig[basics.Int]{
+			Int:    maybe.Just[func(basics.Int) basics.Int]{Value: basics.Identity[basics.Int]},
+			Hex:    maybe.Just[func(basics.Int) basics.Int]{Value: basics.Identity[basics.Int]},
+			Octal:  maybe.Just[func(basics.Int) basics.Int]{Value: basics.Identity[basics.Int]},
+			Binary: maybe.Just[func(basics.Int) basics.Int]{Value: basics.Identity[basics.Int]},
+		})
+
+		asserts.Equal(ok[basics.Int](42), Run(SUT, "42"))
+		asserts.Equal(ok[basics.Int](31), Run(SUT, "0x1F"))
+		asserts.Equal(ok[basics.Int](255), Run(SUT, "0xff"))
+		asserts.Equal(ok[basics.Int](15), Run(SUT, "0o17"))
+		asserts.Equal(ok[basics.Int](5), Run(SUT, "0b101"))
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 1, Problem: ExpectingFloat{}}), Run(SUT, "1.5"))
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 1, Problem: ExpectingNumber{}}), Run(SUT, "0x"))
+	})
+	t.Run("Number with unset kinds", func(t *testing.T) {
+		SUT := Number(NumberConfig[basics.Float]{
+			Float: maybe.Just[func(basics.Float) basics.Float]{Value: basics.Identity[basics.Float]},
+		})
+
+		asserts.Equal(ok[basics.Float](1.5), Run(SUT, "1.5"))
+		asserts.Equal(deadEnds[basics.Float](DeadEnd{Row: 1, Col: 1, Problem: ExpectingInt{}}), Run(SUT, "15"))
+		asserts.Equal(deadEnds[basics.Float](DeadEnd{Row: 1, Col: 1, Problem: ExpectingNumber{}}), Run(SUT, "abc"))
+	})
+	t.Run("Number reports the kind it rejected", func(t *testing.T) {
+		SUT := Number(NumberConfig[basics.Int]{
+			Int: maybe.Just[func(basics.Int) basics.Int]{Value: basics.Identity[basics.Int]},
+		})
+
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 1, Problem: ExpectingHex{}}), Run(SUT, "0x1F"))
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 1, Problem: ExpectingOctal{}}), Run(SUT, "0o17"))
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 1, Problem: ExpectingBinary{}}), Run(SUT, "0b101"))
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 1, Problem: ExpectingFloat{}}), Run(SUT, "1.5"))
+	})
+}
+
+func TestTokens(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Symbol", func(t *testing.T) {
+		asserts.Equal(ok(Unit{}), Run(Symbol("["), "["))
+		asserts.Equal(deadEnds[Unit](DeadEnd{Row: 1, Col: 1, Problem: ExpectingSymbol{Symbol: "["}}), Run(Symbol("["), "4"))
+	})
+	t.Run("Keyword", func(t *testing.T) {
+		asserts.Equal(ok(Unit{}), Run(Keyword("let"), "let"))
+		asserts.Equal(ok(Unit{}), Run(Skip(Keyword("let"), Spaces), "let x"))
+		asserts.Equal(deadEnds[Unit](DeadEnd{Row: 1, Col: 1, Problem: ExpectingKeyword{Keyword: "let"}}), Run(Keyword("let"), "letters"))
+		asserts.Equal(deadEnds[Unit](DeadEnd{Row: 1, Col: 1, Problem: ExpectingKeyword{Keyword: "let"}}), Run(Keyword("let"), "le"))
+	})
+	t.Run("Token", func(t *testing.T) {
+		asserts.Equal(deadEnds[Unit](DeadEnd{Row: 1, Col: 1, Problem: Expecting{Token: "=>"}}), Run(Token("=>"), "->"))
+	})
+}
+
+func TestVariable(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Parses names", func(t *testing.T) {
+		asserts.Equal(ok[s.String]("userName_2"), Run(Variable(typeVar), "userName_2 = 3"))
+	})
+	t.Run("Rejects reserved words", func(t *testing.T) {
+		asserts.Equal(deadEnds[s.String](DeadEnd{Row: 1, Col: 1, Problem: ExpectingVariable{}}), Run(Variable(typeVar), "case"))
+		asserts.Equal(ok[s.String]("cases"), Run(Variable(typeVar), "cases"))
+	})
+	t.Run("Rejects bad starts", func(t *testing.T) {
+		asserts.Equal(deadEnds[s.String](DeadEnd{Row: 1, Col: 1, Problem: ExpectingVariable{}}), Run(Variable(typeVar), "User"))
+	})
+	t.Run("Nil reserved set", func(t *testing.T) {
+		SUT := Variable(VariableConfig{Start: isUpper, Inner: isAlpha})
+
+		asserts.Equal(ok[s.String]("Maybe"), Run(SUT, "Maybe"))
+	})
+}
+
+func TestMapping(t *testing.T) {
+	asserts := assert.New(t)
+	type Point struct{ X, Y basics.Float }
+
+	t.Run("Keep and Skip", func(t *testing.T) {
+		// succeed Point |. symbol "(" |. spaces |= float |. spaces |. symbol "," |. spaces |= float |. spaces |. symbol ")"
+		point := Succeed(func(x basics.Float) func(basics.Float) Point {
+			return func(y basics.Float) Point { return Point{x, y} }
+		})
+		x := Keep(Skip(Skip(point, Symbol("(")), Spaces), Float)
+		y := Keep(Skip(Skip(Skip(x, Spaces), Symbol(",")), Spaces), Float)
+		SUT := Skip(Skip(y, Spaces), Symbol(")"))
+
+		asserts.Equal(ok(Point{3, 4}), Run(SUT, "( 3, 4 )"))
+		asserts.Equal(ok(Point{3, 4}), Run(SUT, "(3,4)"))
+		asserts.Equal(deadEnds[Point](DeadEnd{Row: 1, Col: 5, Problem: ExpectingSymbol{Symbol: ")"}}), Run(SUT, "(3,4"))
+	})
+	t.Run("Map2", func(t *testing.T) {
+		SUT := Map2(basics.Add[basics.Int], Skip(Int, Symbol("+")), Int)
+
+		asserts.Equal(ok[basics.Int](7), Run(SUT, "3+4"))
+	})
+	t.Run("AndThen", func(t *testing.T) {
+		SUT := AndThen(func(n basics.Int) Parser[basics.Int] {
+			if n > 10 {
+				return Problem[basics.Int]("too big")
+			}
+			return Succeed(n)
+		}, Int)
+
+		asserts.Equal(ok[basics.Int](5), Run(SUT, "5"))
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 3, Problem: Custom{Message: "too big"}}), Run(SUT, "11"))
+	})
+	t.Run("Lazy", func(t *testing.T) {
+		var nested Parser[basics.Int]
+		nested = OneOf(list.FromSlice([]Parser[basics.Int]{
+			Int,
+			Skip(ignoreThen(Symbol("("), Lazy(func() Parser[basics.Int] { return nested })), Symbol(")")),
+		}))
+
+		asserts.Equal(ok[basics.Int](4), Run(nested, "(((4)))"))
+	})
+	t.Run("GetChompedString", func(t *testing.T) {
+		SUT := GetChompedString(Skip(ChompIf(isLower), ChompWhile(isAlphaNum)))
+
+		asserts.Equal(ok[s.String]("abc123"), Run(SUT, "abc123 def"))
+	})
+	t.Run("MapChompedString", func(t *testing.T) {
+		SUT := MapChompedString(func(str s.String, n basics.Int) tuple.Tuple2[s.String, basics.Int] {
+			return tuple.Pair(str, n)
+		}, Int)
+
+		asserts.Equal(ok(tuple.Pair[s.String, basics.Int]("42", 42)), Run(SUT, "42 rest"))
+	})
+}
+
+func TestBacktracking(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("OneOf tries the next parser when nothing was chomped", func(t *testing.T) {
+		SUT := OneOf(list.FromSlice([]Parser[s.String]{
+			Map(func(_ Unit) s.String { return "true" }, Keyword("true")),
+			Map(func(_ Unit) s.String { return "false" }, Keyword("false")),
+		}))
+
+		asserts.Equal(ok[s.String]("false"), Run(SUT, "false"))
+	})
+	t.Run("OneOf collects every dead end", func(t *testing.T) {
+		SUT := OneOf(list.FromSlice([]Parser[Unit]{Keyword("true"), Keyword("false")}))
+
+		asserts.Equal(
+			deadEnds[Unit](
+				DeadEnd{Row: 1, Col: 1, Problem: ExpectingKeyword{Keyword: "true"}},
+				DeadEnd{Row: 1, Col: 1, Problem: ExpectingKeyword{Keyword: "false"}},
+			),
+			Run(SUT, "maybe"),
+		)
+	})
+	t.Run("OneOf stops after chomping", func(t *testing.T) {
+		SUT := OneOf(list.FromSlice([]Parser[basics.Int]{
+			Skip(Skip(Succeed[basics.Int](1), Symbol("(")), Symbol("a")),
+			Skip(Skip(Succeed[basics.Int](2), Symbol("(")), Symbol("b")),
+		}))
+
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 2, Problem: ExpectingSymbol{Symbol: "a"}}), Run(SUT, "(b"))
+	})
+	t.Run("Backtrackable", func(t *testing.T) {
+		SUT := OneOf(list.FromSlice([]Parser[basics.Int]{
+			Backtrackable(Skip(Skip(Succeed[basics.Int](1), Symbol("(")), Symbol("a"))),
+			Skip(Skip(Succeed[basics.Int](2), Symbol("(")), Symbol("b")),
+		}))
+
+		asserts.Equal(ok[basics.Int](2), Run(SUT, "(b"))
+	})
+	t.Run("Commit", func(t *testing.T) {
+		SUT := OneOf(list.FromSlice([]Parser[basics.Int]{
+			Skip(Backtrackable(Skip(Succeed[basics.Int](1), Symbol("("))), ignoreThen(Commit(Unit{}), Symbol("a"))),
+			Skip(Skip(Succeed[basics.Int](2), Symbol("(")), Symbol("b")),
+		}))
+
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 2, Problem: ExpectingSymbol{Symbol: "a"}}), Run(SUT, "(b"))
+	})
+}
+
+func TestLoop(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Sums numbers", func(t *testing.T) {
+		SUT := Loop(basics.Int(0), func(total basics.Int) Parser[Step[basics.Int, basics.Int]] {
+			return OneOf(list.FromSlice([]Parser[Step[basics.Int, basics.Int]]{
+				Map(func(n basics.Int) Step[basics.Int, basics.Int] {
+					return Continue[basics.Int, basics.Int]{State: total + n}
+				}, Skip(Skip(Skip(Int, Spaces), OneOf(list.FromSlice([]Parser[Unit]{Symbol("+"), Succeed(Unit{})}))), Spaces)),
+				Map(func(_ Unit) Step[basics.Int, basics.Int] {
+					return Done[basics.Int, basics.Int]{Val: total}
+				}, End),
+			}))
+		})
+
+		asserts.Equal(ok[basics.Int](10), Run(SUT, "1 + 2 + 3 + 4"))
+	})
+	t.Run("Fails with the inner dead end", func(t *testing.T) {
+		SUT := Loop(basics.Int(0), func(n basics.Int) Parser[Step[basics.Int, basics.Int]] {
+			if n == 3 {
+				return Succeed[Step[basics.Int, basics.Int]](Done[basics.Int, basics.Int]{Val: n})
+			}
+			return Map(func(_ Unit) Step[basics.Int, basics.Int] {
+				return Continue[basics.Int, basics.Int]{State: n + 1}
+			}, Symbol("a"))
+		})
+
+		asserts.Equal(ok[basics.Int](3), Run(SUT, "aaa"))
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 3, Problem: ExpectingSymbol{Symbol: "a"}}), Run(SUT, "aab"))
+	})
+}
+
+func TestSequence(t *testing.T) {
+	asserts := assert.New(t)
+	config := func(trailing Trailing) SequenceConfig[basics.Int] {
+		return SequenceConfig[basics.Int]{Start: "[", Separator: ",", End: "]", Spaces: Spaces, Item: Int, Trailing: trailing}
+	}
+	ints := func(xs ...basics.Int) result.Result[list.List[DeadEnd], list.List[basics.Int]] {
+		return ok(list.FromSlice(xs))
+	}
+
+	t.Run("Forbidden", func(t *testing.T) {
+		SUT := Sequence(config(Forbidden{}))
+
+		asserts.Equal(ints(), Run(SUT, "[]"))
+		asserts.Equal(ints(), Run(SUT, "[ ]"))
+		asserts.Equal(ints(1), Run(SUT, "[1]"))
+		asserts.Equal(ints(1, 2, 3), Run(SUT, "[ 1 , 2,3 ]"))
+		asserts.Equal(
+			deadEnds[list.List[basics.Int]](DeadEnd{Row: 1, Col: 6, Problem: ExpectingInt{}}),
+			Run(SUT, "[1,2,]"),
+		)
+	})
+	t.Run("Optional", func(t *testing.T) {
+		SUT := Sequence(config(Optional{}))
+
+		asserts.Equal(ints(), Run(SUT, "[]"))
+		asserts.Equal(ints(1, 2), Run(SUT, "[1,2]"))
+		asserts.Equal(ints(1, 2), Run(SUT, "[1, 2, ]"))
+	})
+	t.Run("Mandatory", func(t *testing.T) {
+		SUT := Sequence(config(Mandatory{}))
+
+		asserts.Equal(ints(), Run(SUT, "[]"))
+		asserts.Equal(ints(1, 2), Run(SUT, "[1,2,]"))
+		asserts.Equal(
+			deadEnds[list.List[basics.Int]](DeadEnd{Row: 1, Col: 3, Problem: ExpectingSymbol{Symbol: ","}}),
+			Run(SUT, "[1]"),
+		)
+	})
+	t.Run("Missing end", func(t *testing.T) {
+		SUT := Sequence(config(Forbidden{}))
+
+		asserts.Equal(
+			deadEnds[list.List[basics.Int]](
+				DeadEnd{Row: 2, Col: 2, Problem: ExpectingSymbol{Symbol: ","}},
+				DeadEnd{Row: 2, Col: 2, Problem: ExpectingSymbol{Symbol: "]"}},
+			),
+			Run(SUT, "[1,\n2"),
+		)
+	})
+}
+
+func TestWhitespace(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Spaces", func(t *testing.T) {
+		asserts.Equal(ok[basics.Int](1), Run(ignoreThen(Spaces, Int), " \n\r 1"))
+		asserts.Equal(ok[basics.Int](1), Run(ignoreThen(Spaces, Int), "1"))
+	})
+	t.Run("LineComment", func(t *testing.T) {
+		SUT := ignoreThen(LineComment("--"), ignoreThen(Spaces, Int))
+
+		asserts.Equal(ok[basics.Int](7), Run(SUT, "-- the answer\n7"))
+		asserts.Equal(ok(Unit{}), Run(Skip(LineComment("#"), End), "# no newline"))
+	})
+	t.Run("MultiComment NotNestable", func(t *testing.T) {
+		SUT := ignoreThen(MultiComment("{-", "-}", NotNestable{}), Int)
+
+		asserts.Equal(ok[basics.Int](1), Run(SUT, "{- a\ncomment -}1"))
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 1, Col: 8, Problem: Expecting{Token: "-}"}}), Run(SUT, "{- open"))
+	})
+	t.Run("MultiComment Nestable", func(t *testing.T) {
+		SUT := ignoreThen(MultiComment("{-", "-}", Nestable{}), Int)
+
+		asserts.Equal(ok[basics.Int](1), Run(SUT, "{- a {- nested -} comment -}1"))
+		asserts.Equal(
+			deadEnds[basics.Int](
+				DeadEnd{Row: 1, Col: 13, Problem: Expecting{Token: "-}"}},
+				DeadEnd{Row: 1, Col: 13, Problem: Expecting{Token: "{-"}},
+				DeadEnd{Row: 1, Col: 13, Problem: Expecting{Token: "-}"}},
+			),
+			Run(SUT, "{- {- one -}"),
+		)
+	})
+}
+
+func TestChomping(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("ChompIf", func(t *testing.T) {
+		asserts.Equal(ok(Unit{}), Run(ChompIf(char.IsDigit), "1"))
+		asserts.Equal(deadEnds[Unit](DeadEnd{Row: 1, Col: 1, Problem: UnexpectedChar{}}), Run(ChompIf(char.IsDigit), "a"))
+		asserts.Equal(deadEnds[Unit](DeadEnd{Row: 1, Col: 1, Problem: UnexpectedChar{}}), Run(ChompIf(char.IsDigit), ""))
+	})
+	t.Run("ChompUntil", func(t *testing.T) {
+		SUT := GetChompedString(ChompUntil("*/"))
+
+		asserts.Equal(ok[s.String]("a comment "), Run(SUT, "a comment */"))
+		asserts.Equal(deadEnds[s.String](DeadEnd{Row: 2, Col: 3, Problem: Expecting{Token: "*/"}}), Run(SUT, "a\nbc"))
+	})
+	t.Run("ChompUntilEndOr", func(t *testing.T) {
+		SUT := GetChompedString(ChompUntilEndOr("\n"))
+
+		asserts.Equal(ok[s.String]("first"), Run(SUT, "first\nsecond"))
+		asserts.Equal(ok[s.String]("only"), Run(SUT, "only"))
+	})
+}
+
+func TestPositions(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("GetPosition counts characters", func(t *testing.T) {
+		SUT := ignoreThen(Symbol("héllo\nwörld "), GetPosition)
+
+		asserts.Equal(ok(tuple.Pair[basics.Int, basics.Int](2, 7)), Run(SUT, "héllo\nwörld !"))
+	})
+	t.Run("GetOffset counts bytes", func(t *testing.T) {
+		SUT := ignoreThen(Symbol("héllo"), GetOffset)
+
+		asserts.Equal(ok[basics.Int](6), Run(SUT, "héllo"))
+	})
+	t.Run("GetRow and GetCol", func(t *testing.T) {
+		SUT := ignoreThen(Spaces, Map2(tuple.Pair[basics.Int, basics.Int], GetRow, GetCol))
+
+		asserts.Equal(ok(tuple.Pair[basics.Int, basics.Int](3, 3)), Run(SUT, "\n\n  x"))
+	})
+	t.Run("GetSource", func(t *testing.T) {
+		asserts.Equal(ok[s.String]("abc"), Run(ignoreThen(Symbol("a"), GetSource), "abc"))
+	})
+	t.Run("WithIndent", func(t *testing.T) {
+		SUT := Map2(tuple.Pair[basics.Int, basics.Int], WithIndent(4, GetIndent), GetIndent)
+
+		asserts.Equal(ok(tuple.Pair[basics.Int, basics.Int](4, 1)), Run(SUT, ""))
+	})
+	t.Run("Dead ends report rows and columns", func(t *testing.T) {
+		SUT := Skip(ignoreThen(Spaces, Int), Symbol(";"))
+
+		asserts.Equal(deadEnds[basics.Int](DeadEnd{Row: 3, Col: 5, Problem: ExpectingSymbol{Symbol: ";"}}), Run(SUT, "\n\n  42"))
+	})
+}
+
+func TestDeadEndsToString(t *testing.T) {
+	asserts := assert.New(t)
+	SUT := OneOf(list.FromSlice([]Parser[Unit]{Symbol("["), Keyword("let"), Problem[Unit]("no good")}))
+
+	result.ResultWith(
+		Run(SUT, "x"),
+		func(e result.Err[list.List[DeadEnd], Unit]) any {
+			asserts.Equal(
+				s.String("1:1: Expecting the symbol `[`\n1:1: Expecting the keyword `let`\n1:1: no good"),
+				DeadEndsToString(e.Err),
+			)
+			return nil
+		},
+		func(o result.Ok[list.List[DeadEnd], Unit]) any {
+			asserts.Fail("Expected the parser to fail")
+			return nil
+		},
+	)
+}
+
+func isLower(c char.Char) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isUpper(c char.Char) bool {
+	return 'A' <= c && c <= 'Z'
+}
+
+func isAlpha(c char.Char) bool {
+	return isLower(c) || isUpper(c)
+}
+
+func isAlphaNum(c char.Char) bool {
+	return isAlpha(c) || char.IsDigit(c)
+}
+
+func ignoreThen[I, K any](ignore Parser[I], keep Parser[K]) Parser[K] {
+	return Map2(func(_ I, k K) K { return k }, ignore, keep)
+}
@@ -0,0 +1,82 @@
+package result
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"github.com/Confidenceman02/scion-tools/pkg/internal"
+)
+
+// A Result is encoded as an object tagged with its variant.
+//
+//	{"tag": "Ok", "value": 42}
+//	{"tag": "Err", "value": "not a number"}
+type taggedJSON struct {
+	Tag   string          `json:"tag"`
+	Value json.RawMessage `json:"value"`
+}
+
+func (o Ok[E, V]) MarshalJSON() ([]byte, error) {
+	return marshalTagged("Ok", o.Val)
+}
+
+func (e Err[E, V]) MarshalJSON() ([]byte, error) {
+	return marshalTagged("Err", e.Err)
+}
+
+func marshalTagged(tag string, v any) ([]byte, error) {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(taggedJSON{Tag: tag, Value: value})
+}
+
+// Decode a Result from an object tagged with its variant.
+func FromJSON[E, V any](data []byte) (Result[E, V], error) {
+	var tagged taggedJSON
+	if err := json.Unmarshal(data, &tagged); err != nil {
+		return nil, err
+	}
+	switch tagged.Tag {
+	case "Ok":
+		v, err := internal.UnmarshalJSON[V](tagged.Value)
+		if err != nil {
+			return nil, err
+		}
+		return Ok[E, V]{Val: v}, nil
+	case "Err":
+		e, err := internal.UnmarshalJSON[E](tagged.Value)
+		if err != nil {
+			return nil, err
+		}
+		return Err[E, V]{Err: e}, nil
+	default:
+		return nil, fmt.Errorf("result: unknown tag %q, expecting \"Ok\" or \"Err\"", tagged.Tag)
+	}
+}
+
+// Register sets up Result[E, V] to be decoded inside other values and sent through encoding/gob.
+func Register[E, V any]() {
+	gob.Register(Ok[E, V]{})
+	gob.Register(Err[E, V]{})
+	internal.RegisterJSON(FromJSON[E, V])
+}
+
+// A Field holds a Result as a struct field that encoding/json can decode.
+type Field[E, V any] struct {
+	Result Result[E, V]
+}
+
+func (f Field[E, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Result)
+}
+
+func (f *Field[E, V]) UnmarshalJSON(data []byte) error {
+	r, err := FromJSON[E, V](data)
+	if err != nil {
+		return err
+	}
+	f.Result = r
+	return nil
+}
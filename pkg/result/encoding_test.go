@@ -0,0 +1,84 @@
+package result
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	. "github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type payload struct {
+	Parsed Result[string, Int]
+}
+
+func TestJSON(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Marshal Ok", func(t *testing.T) {
+		SUT, err := json.Marshal(payload{Parsed: Ok[string, Int]{Val: 42}})
+
+		asserts.NoError(err)
+		asserts.Equal(`{"Parsed":{"tag":"Ok","value":42}}`, string(SUT))
+	})
+	t.Run("Marshal Err", func(t *testing.T) {
+		SUT, err := json.Marshal(payload{Parsed: Err[string, Int]{Err: "not a number"}})
+
+		asserts.NoError(err)
+		asserts.Equal(`{"Parsed":{"tag":"Err","value":"not a number"}}`, string(SUT))
+	})
+	t.Run("FromJSON", func(t *testing.T) {
+		SUT1, err1 := FromJSON[string, Int]([]byte(`{"tag": "Ok", "value": 42}`))
+		SUT2, err2 := FromJSON[string, Int]([]byte(`{"value": "oops", "tag": "Err"}`))
+
+		asserts.NoError(err1)
+		asserts.NoError(err2)
+		asserts.Equal(Ok[string, Int]{Val: 42}, SUT1)
+		asserts.Equal(Err[string, Int]{Err: "oops"}, SUT2)
+	})
+	t.Run("FromJSON with an unknown tag", func(t *testing.T) {
+		_, err := FromJSON[string, Int]([]byte(`{"tag": "Maybe", "value": 42}`))
+
+		asserts.EqualError(err, `result: unknown tag "Maybe", expecting "Ok" or "Err"`)
+	})
+	t.Run("Round trip", func(t *testing.T) {
+		for _, r := range []Result[string, Int]{Ok[string, Int]{Val: 1}, Err[string, Int]{Err: "e"}} {
+			data, err := json.Marshal(r)
+			asserts.NoError(err)
+
+			SUT, err := FromJSON[string, Int](data)
+
+			asserts.NoError(err)
+			asserts.Equal(r, SUT)
+		}
+	})
+	t.Run("Round trip a struct with a Field", func(t *testing.T) {
+		type job struct {
+			Outcome Field[string, Int]
+		}
+		for _, r := range []Result[string, Int]{Ok[string, Int]{Val: 1}, Err[string, Int]{Err: "e"}} {
+			data, err := json.Marshal(job{Outcome: Field[string, Int]{Result: r}})
+			asserts.NoError(err)
+
+			var SUT job
+			asserts.NoError(json.Unmarshal(data, &SUT))
+
+			asserts.Equal(r, SUT.Outcome.Result)
+		}
+	})
+}
+
+func TestGob(t *testing.T) {
+	asserts := assert.New(t)
+	Register[string, Int]()
+
+	for _, r := range []Result[string, Int]{Ok[string, Int]{Val: 42}, Err[string, Int]{Err: "e"}} {
+		var buf bytes.Buffer
+		asserts.NoError(gob.NewEncoder(&buf).Encode(payload{Parsed: r}))
+		var SUT payload
+		asserts.NoError(gob.NewDecoder(&buf).Decode(&SUT))
+
+		asserts.Equal(payload{Parsed: r}, SUT)
+	}
+}
@@ -3,6 +3,8 @@ package result
 
 import (
 	"fmt"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/internal/hashing"
 	"github.com/Confidenceman02/scion-tools/pkg/maybe"
 	"reflect"
 )
@@ -29,6 +31,27 @@ type Ok[E, V any] struct {
 	Val V
 }
 
+// EQUALITY
+
+// Results are equal when they are the same variant holding equal values, compared with basics.Eq.
+func (o Ok[E, V]) Equal(r Result[E, V]) bool {
+	y, ok := r.(Ok[E, V])
+	return ok && basics.Eq(o.Val, y.Val)
+}
+
+func (e Err[E, V]) Equal(r Result[E, V]) bool {
+	y, ok := r.(Err[E, V])
+	return ok && basics.Eq(e.Err, y.Err)
+}
+
+func (o Ok[E, V]) Hash() uint64 {
+	return hashing.Combine(hashing.Uint64(1), basics.Hash(o.Val))
+}
+
+func (e Err[E, V]) Hash() uint64 {
+	return hashing.Combine(hashing.Uint64(0), basics.Hash(e.Err))
+}
+
 // Mapping
 
 // Apply a function to a result. If the result is Ok, it will be converted. If the result is an Err, the same error value will propagate through.
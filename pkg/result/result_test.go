@@ -2,8 +2,10 @@ package result
 
 import (
 	. "github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/dict"
 	m "github.com/Confidenceman02/scion-tools/pkg/maybe"
 	s "github.com/Confidenceman02/scion-tools/pkg/string"
+	"github.com/Confidenceman02/scion-tools/pkg/tuple"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -119,3 +121,29 @@ func TestAndThen(t *testing.T) {
 		)
 	})
 }
+
+func TestEquality(t *testing.T) {
+	asserts := assert.New(t)
+	inserted := dict.Insert(2, "b", dict.Insert(1, "a", dict.Singleton[Int, string](3, "c")))
+	sorted := dict.FromSortedSlice([]tuple.Tuple2[Int, string]{tuple.Pair[Int, string](1, "a"), tuple.Pair[Int, string](2, "b"), tuple.Pair[Int, string](3, "c")})
+
+	t.Run("Ok", func(t *testing.T) {
+		var x, y Result[string, dict.Dict[Int, string]] = Ok[string, dict.Dict[Int, string]]{Val: inserted}, Ok[string, dict.Dict[Int, string]]{Val: sorted}
+
+		asserts.True(Eq(x, y))
+		asserts.Equal(Hash(x), Hash(y))
+	})
+	t.Run("Err", func(t *testing.T) {
+		var x, y Result[dict.Dict[Int, string], Int] = Err[dict.Dict[Int, string], Int]{Err: inserted}, Err[dict.Dict[Int, string], Int]{Err: sorted}
+
+		asserts.True(Eq(x, y))
+		asserts.Equal(Hash(x), Hash(y))
+	})
+	t.Run("Ok and Err", func(t *testing.T) {
+		var x, y Result[Int, Int] = Ok[Int, Int]{Val: 1}, Err[Int, Int]{Err: 1}
+
+		asserts.False(Eq(x, y))
+		asserts.False(Eq(y, x))
+		asserts.NotEqual(Hash(x), Hash(y))
+	})
+}
@@ -0,0 +1,64 @@
+package set
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	. "github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/internal"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+)
+
+// A set is encoded as a JSON array, sorted from lowest to highest.
+func (s *set[K]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(list.ToSlice(ToList[K](s)))
+}
+
+// Decode a set from a JSON array, removing any duplicates.
+func FromJSON[K Comparable[K]](data []byte) (Set[K], error) {
+	xs, err := list.FromJSON[K](data)
+	if err != nil {
+		return nil, err
+	}
+	return FromList(xs), nil
+}
+
+// Register sets up Set[K] to be decoded inside other values and sent through encoding/gob.
+func Register[K Comparable[K]]() {
+	gob.Register(&set[K]{})
+	internal.RegisterJSON(FromJSON[K])
+}
+
+// A Field holds a Set as a struct field that encoding/json can decode.
+// A Field with no Set is encoded as an empty set.
+type Field[K Comparable[K]] struct {
+	Set Set[K]
+}
+
+func (f Field[K]) MarshalJSON() ([]byte, error) {
+	if f.Set == nil {
+		return json.Marshal(Empty[K]())
+	}
+	return json.Marshal(f.Set)
+}
+
+func (f *Field[K]) UnmarshalJSON(data []byte) error {
+	st, err := FromJSON[K](data)
+	if err != nil {
+		return err
+	}
+	f.Set = st
+	return nil
+}
+
+func (s *set[K]) GobEncode() ([]byte, error) {
+	return internal.GobEncode(list.ToSlice(ToList[K](s)))
+}
+
+func (s *set[K]) GobDecode(data []byte) error {
+	var xs []K
+	if err := internal.GobDecode(data, &xs); err != nil {
+		return err
+	}
+	s.d = FromList(list.FromSlice(xs)).set_().d
+	return nil
+}
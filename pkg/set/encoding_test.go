@@ -0,0 +1,70 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type payload struct {
+	Tags Set[basics.Int]
+}
+
+func TestJSON(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Marshal", func(t *testing.T) {
+		SUT, err := json.Marshal(payload{Tags: FromList(list.FromSlice([]basics.Int{3, 1, 2}))})
+
+		asserts.NoError(err)
+		asserts.Equal(`{"Tags":[1,2,3]}`, string(SUT))
+	})
+	t.Run("FromJSON", func(t *testing.T) {
+		SUT, err := FromJSON[basics.Int]([]byte(`[3, 1, 3, 2]`))
+
+		asserts.NoError(err)
+		asserts.Equal([]basics.Int{1, 2, 3}, list.ToSlice(ToList(SUT)))
+	})
+	t.Run("Round trip", func(t *testing.T) {
+		data, err := json.Marshal(set1To100)
+		asserts.NoError(err)
+
+		SUT, err := FromJSON[basics.Int](data)
+
+		asserts.NoError(err)
+		asserts.Equal(ToList(set1To100), ToList(SUT))
+	})
+	t.Run("Round trip a struct with a Field", func(t *testing.T) {
+		type post struct {
+			Tags  Field[basics.Int]
+			Empty Field[basics.Int]
+		}
+		data, err := json.Marshal(post{Tags: Field[basics.Int]{Set: FromList(list.FromSlice([]basics.Int{3, 1, 2}))}})
+		asserts.NoError(err)
+		asserts.Equal(`{"Tags":[1,2,3],"Empty":[]}`, string(data))
+
+		var SUT post
+		asserts.NoError(json.Unmarshal(data, &SUT))
+
+		asserts.Equal([]basics.Int{1, 2, 3}, list.ToSlice(ToList(SUT.Tags.Set)))
+		asserts.True(IsEmpty(SUT.Empty.Set))
+	})
+}
+
+func TestGob(t *testing.T) {
+	asserts := assert.New(t)
+	Register[basics.Int]()
+
+	for _, s := range []Set[basics.Int]{set1To50, Empty[basics.Int]()} {
+		var buf bytes.Buffer
+		asserts.NoError(gob.NewEncoder(&buf).Encode(payload{Tags: s}))
+		var SUT payload
+		asserts.NoError(gob.NewDecoder(&buf).Decode(&SUT))
+
+		asserts.Equal(ToList(s), ToList(SUT.Tags))
+	}
+}
@@ -0,0 +1,151 @@
+package set
+
+import (
+	"cmp"
+	. "github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"iter"
+	"slices"
+)
+
+// Ordered is a set of values of a native Go ordered type, like int, string or time.Duration,
+// so values don't have to be wrapped in Int or String.
+//
+// It holds a Set of [Native] values, and converting between the two with [OrderedFrom]
+// and Set copies nothing, so every function of this package can still be used through Set.
+// The zero value is an empty set.
+type Ordered[K cmp.Ordered] struct {
+	s Set[Native[K]]
+}
+
+// Create an empty ordered set.
+func EmptyOrdered[K cmp.Ordered]() Ordered[K] {
+	return Ordered[K]{s: Empty[Native[K]]()}
+}
+
+// Use a set of Native values as an ordered set. This takes O(1) time.
+func OrderedFrom[K cmp.Ordered](s Set[Native[K]]) Ordered[K] {
+	return Ordered[K]{s: s}
+}
+
+// Convert a slice into an ordered set, removing any duplicates. This takes O(n log n) time to sort the values.
+func OrderedFromSlice[K cmp.Ordered](xs []K) Ordered[K] {
+	sorted := slices.CompactFunc(slices.Sorted(slices.Values(xs)), func(a, b K) bool {
+		return cmp.Compare(a, b) == 0
+	})
+	wrapped := make([]Native[K], len(sorted))
+	for i, x := range sorted {
+		wrapped[i] = Wrap(x)
+	}
+	return OrderedFrom(FromSortedSlice(wrapped))
+}
+
+// Get the set of Native values that the ordered set holds. This takes O(1) time.
+func (o Ordered[K]) Set() Set[Native[K]] {
+	if o.s == nil {
+		return Empty[Native[K]]()
+	}
+	return o.s
+}
+
+// Insert a value.
+func (o Ordered[K]) Insert(k K) Ordered[K] {
+	return OrderedFrom(Insert(Wrap(k), o.Set()))
+}
+
+// Remove a value. If the value is not found, no changes are made.
+func (o Ordered[K]) Remove(k K) Ordered[K] {
+	return OrderedFrom(Remove(Wrap(k), o.Set()))
+}
+
+// Determine if the set is empty.
+func (o Ordered[K]) IsEmpty() bool {
+	return IsEmpty(o.Set())
+}
+
+// Determine if a value is in the set.
+func (o Ordered[K]) Member(k K) bool {
+	return Member(Wrap(k), o.Set())
+}
+
+// Determine the number of values in the set.
+func (o Ordered[K]) Size() Int {
+	return Size(o.Set())
+}
+
+// Convert the set into a list, sorted from lowest to highest.
+func (o Ordered[K]) ToList() list.List[K] {
+	return Foldr(
+		func(k Native[K], xs list.List[K]) list.List[K] { return list.Cons(k.Value, xs) },
+		list.Empty[K](),
+		o.Set(),
+	)
+}
+
+// Get a sequence of the values, from lowest to highest.
+func (o Ordered[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range All(o.Set()) {
+			if !yield(k.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Only keep values that pass the given test.
+func (o Ordered[K]) Filter(isGood func(K) bool) Ordered[K] {
+	return OrderedFrom(Filter(func(k Native[K]) bool { return isGood(k.Value) }, o.Set()))
+}
+
+// Get the union of two sets. Keep all values.
+func (o Ordered[K]) Union(other Ordered[K]) Ordered[K] {
+	return OrderedFrom(Union(o.Set(), other.Set()))
+}
+
+// Get the intersection of two sets. Keeps values that appear in both sets.
+func (o Ordered[K]) Intersect(other Ordered[K]) Ordered[K] {
+	return OrderedFrom(Intersect(o.Set(), other.Set()))
+}
+
+// Get the difference between this set and the other. Keeps values that do not appear in the other set.
+func (o Ordered[K]) Diff(other Ordered[K]) Ordered[K] {
+	return OrderedFrom(Diff(o.Set(), other.Set()))
+}
+
+// Ordered sets are equal when the sets they hold are.
+func (o Ordered[K]) Equal(y Ordered[K]) bool {
+	return o.Set().set_().Equal(y.Set())
+}
+
+func (o Ordered[K]) Hash() uint64 {
+	return o.Set().set_().Hash()
+}
+
+// Ordered sets are written to JSON as an array, sorted from lowest to highest.
+func (o Ordered[K]) MarshalJSON() ([]byte, error) {
+	return o.Set().set_().MarshalJSON()
+}
+
+// Ordered is a struct, so unlike Set it can be decoded with json.Unmarshal.
+func (o *Ordered[K]) UnmarshalJSON(data []byte) error {
+	s, err := FromJSON[Native[K]](data)
+	if err != nil {
+		return err
+	}
+	o.s = s
+	return nil
+}
+
+func (o Ordered[K]) GobEncode() ([]byte, error) {
+	return o.Set().set_().GobEncode()
+}
+
+func (o *Ordered[K]) GobDecode(data []byte) error {
+	s := &set[Native[K]]{}
+	if err := s.GobDecode(data); err != nil {
+		return err
+	}
+	o.s = s
+	return nil
+}
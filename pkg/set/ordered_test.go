@@ -0,0 +1,109 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrdered(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Insert and Member with native values", func(t *testing.T) {
+		SUT := EmptyOrdered[string]().Insert("b").Insert("a").Insert("b")
+
+		asserts.True(SUT.Member("a"))
+		asserts.False(SUT.Member("c"))
+		asserts.Equal(basics.Int(2), SUT.Size())
+	})
+
+	t.Run("The zero value is empty", func(t *testing.T) {
+		var SUT Ordered[time.Duration]
+
+		asserts.True(SUT.IsEmpty())
+		asserts.True(SUT.Insert(time.Second).Member(time.Second))
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		s := OrderedFromSlice([]int{1, 2, 3})
+		SUT := s.Remove(2)
+
+		asserts.False(SUT.Member(2))
+		asserts.True(s.Member(2))
+	})
+
+	t.Run("OrderedFromSlice sorts and removes duplicates", func(t *testing.T) {
+		SUT := OrderedFromSlice([]int{3, 1, 2, 3, 1})
+
+		asserts.Equal([]int{1, 2, 3}, list.ToSlice(SUT.ToList()))
+	})
+
+	t.Run("OrderedFromSlice keeps one NaN", func(t *testing.T) {
+		SUT := OrderedFromSlice([]float64{math.NaN(), 1, math.NaN()})
+
+		asserts.Equal(basics.Int(2), SUT.Size())
+		asserts.True(SUT.Member(math.NaN()))
+	})
+
+	t.Run("All", func(t *testing.T) {
+		var xs []int
+		for x := range OrderedFromSlice([]int{2, 1}).All() {
+			xs = append(xs, x)
+		}
+
+		asserts.Equal([]int{1, 2}, xs)
+	})
+
+	t.Run("Filter, Union, Intersect and Diff", func(t *testing.T) {
+		x := OrderedFromSlice([]int{1, 2})
+		y := OrderedFromSlice([]int{2, 3})
+
+		asserts.Equal([]int{2}, list.ToSlice(x.Filter(func(k int) bool { return k > 1 }).ToList()))
+		asserts.Equal([]int{1, 2, 3}, list.ToSlice(x.Union(y).ToList()))
+		asserts.Equal([]int{2}, list.ToSlice(x.Intersect(y).ToList()))
+		asserts.Equal([]int{1}, list.ToSlice(x.Diff(y).ToList()))
+	})
+
+	t.Run("Converting to and from Set copies nothing", func(t *testing.T) {
+		s := Singleton(basics.Wrap("a"))
+		SUT := OrderedFrom(s)
+
+		asserts.Same(s, SUT.Set())
+		asserts.True(SUT.Member("a"))
+	})
+
+	t.Run("Equality", func(t *testing.T) {
+		x := EmptyOrdered[int]().Insert(1).Insert(2).Insert(3)
+		y := EmptyOrdered[int]().Insert(3).Insert(2).Insert(1)
+
+		asserts.True(basics.Eq(x, y))
+		asserts.Equal(basics.Hash(x), basics.Hash(y))
+		asserts.False(basics.Eq(x, y.Remove(1)))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(OrderedFromSlice([]string{"b", "a"}))
+		asserts.NoError(err)
+		asserts.Equal(`["a","b"]`, string(data))
+
+		var SUT Ordered[string]
+		asserts.NoError(json.Unmarshal(data, &SUT))
+		asserts.Equal([]string{"a", "b"}, list.ToSlice(SUT.ToList()))
+	})
+
+	t.Run("Gob", func(t *testing.T) {
+		var buf bytes.Buffer
+		asserts.NoError(gob.NewEncoder(&buf).Encode(OrderedFromSlice([]int{2, 1})))
+
+		var SUT Ordered[int]
+		asserts.NoError(gob.NewDecoder(&buf).Decode(&SUT))
+		asserts.Equal([]int{1, 2}, list.ToSlice(SUT.ToList()))
+	})
+}
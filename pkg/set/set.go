@@ -1,13 +1,19 @@
 //	A set of unique values. The values can be any comparable type. This includes Int, Float, Char, String, and tuples or lists of comparable types.
 //
 // Insert, remove, and query operations all take O(log n) time.
+//
+// Ordered is the one exception to the data-last functions of this package. Its operations are
+// methods, like s.Insert(x), for the same reason as dict.Ordered: as functions they would need
+// names of their own next to Insert, Member and the rest.
 package set
 
 import (
 	. "github.com/Confidenceman02/scion-tools/pkg/basics"
 	"github.com/Confidenceman02/scion-tools/pkg/dict"
 	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
 	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"iter"
 )
 
 type Set[K Comparable[K]] interface {
@@ -45,6 +51,56 @@ func Remove[K Comparable[K]](k K, s Set[K]) Set[K] {
 	return &set[K]{d: dict.Remove(k, s.set_().d)}
 }
 
+// BUILDER
+
+// A Builder makes a set by changing it in place, skipping the copying done by Insert and Remove.
+// Call Freeze to get the set, which never changes afterwards, even when the builder is used again.
+//
+// A Builder is not safe for use by multiple goroutines at the same time.
+type Builder[K Comparable[K]] struct {
+	b *dict.Builder[K, struct{}]
+}
+
+// Create an empty builder.
+func NewBuilder[K Comparable[K]]() *Builder[K] {
+	return &Builder[K]{b: dict.NewBuilder[K, struct{}]()}
+}
+
+// Create a builder starting with the values of a set. The set is left untouched.
+func NewBuilderFrom[K Comparable[K]](s Set[K]) *Builder[K] {
+	return &Builder[K]{b: dict.NewBuilderFrom(s.set_().d)}
+}
+
+// Insert a value.
+func (b *Builder[K]) Insert(k K) {
+	b.b.Insert(k, struct{}{})
+}
+
+// Remove a value. If the value is not found, no changes are made.
+func (b *Builder[K]) Remove(k K) {
+	b.b.Remove(k)
+}
+
+// Determine if a value is in the builder.
+func (b *Builder[K]) Member(k K) bool {
+	return maybe.MaybeWith(
+		b.b.Get(k),
+		func(_ maybe.Just[struct{}]) bool { return true },
+		func(_ maybe.Nothing) bool { return false },
+	)
+}
+
+// Determine the number of values in the builder.
+func (b *Builder[K]) Size() Int {
+	return b.b.Size()
+}
+
+// Get the set built so far. The builder can keep being used, but
+// its changes will not show up in the frozen set.
+func (b *Builder[K]) Freeze() Set[K] {
+	return &set[K]{d: b.b.Freeze()}
+}
+
 // QUERY
 
 // Determine if a set is empty.
@@ -62,6 +118,42 @@ func Size[K Comparable[K]](s Set[K]) Int {
 	return dict.Size(s.set_().d)
 }
 
+// ORDER STATISTICS
+
+// Get the number of values in a set that are lower than the given value.
+// The value does not have to be in the set. This takes O(log n) time.
+func Rank[K Comparable[K]](k K, s Set[K]) Int {
+	return dict.Rank(k, s.set_().d)
+}
+
+// Get the value at an index, where index 0 is the lowest value.
+// Returns Nothing when the index is out of range. This takes O(log n) time.
+func At[K Comparable[K]](i Int, s Set[K]) maybe.Maybe[K] {
+	return maybe.Map(tuple.First[K, struct{}], dict.At(i, s.set_().d))
+}
+
+// Keep the n lowest values. This takes O(log n) time.
+func TakeSmallest[K Comparable[K]](n Int, s Set[K]) Set[K] {
+	return &set[K]{dict.TakeSmallest(n, s.set_().d)}
+}
+
+// Drop the n lowest values. This takes O(log n) time.
+func DropSmallest[K Comparable[K]](n Int, s Set[K]) Set[K] {
+	return &set[K]{dict.DropSmallest(n, s.set_().d)}
+}
+
+// Get the value at a percentile of the set, using the nearest-rank method.
+// The percentile is clamped between 0 and 100, where 0 is the lowest value and 100 is the highest.
+// Returns Nothing if the set is empty.
+func Percentile[K Comparable[K]](p Float, s Set[K]) maybe.Maybe[K] {
+	return maybe.Map(tuple.First[K, struct{}], dict.Percentile(p, s.set_().d))
+}
+
+// Get the median value. When there is an even number of values, this is the lower of the two middle values.
+func Median[K Comparable[K]](s Set[K]) maybe.Maybe[K] {
+	return maybe.Map(tuple.First[K, struct{}], dict.Median(s.set_().d))
+}
+
 // COMBINE
 
 // Get the union of two sets. Keep all values.
@@ -87,8 +179,58 @@ func ToList[K Comparable[K]](s Set[K]) list.List[K] {
 }
 
 // Convert a list into a set, removing any duplicates.
+// When the list is already sorted this takes O(n) time, otherwise O(n log n).
 func FromList[K Comparable[K]](xs list.List[K]) Set[K] {
-	return list.Foldl[K, Set[K]](Insert, Empty[K](), xs)
+	return &set[K]{dict.FromList(list.Map(withUnit[K], xs))}
+}
+
+// Convert a list that is sorted from lowest to highest, without duplicates, into a set.
+// This takes O(n) time. Panics if the values are not sorted or have duplicates.
+func FromSortedList[K Comparable[K]](xs list.List[K]) Set[K] {
+	return &set[K]{dict.FromSortedList(list.Map(withUnit[K], xs))}
+}
+
+// Convert a slice that is sorted from lowest to highest, without duplicates, into a set.
+// This takes O(n) time. Panics if the values are not sorted or have duplicates.
+func FromSortedSlice[K Comparable[K]](xs []K) Set[K] {
+	pairs := make([]tuple.Tuple2[K, struct{}], len(xs))
+	for i, x := range xs {
+		pairs[i] = withUnit(x)
+	}
+	return &set[K]{dict.FromSortedSlice(pairs)}
+}
+
+func withUnit[K Comparable[K]](k K) tuple.Tuple2[K, struct{}] {
+	return tuple.Pair(k, struct{}{})
+}
+
+// EQUALITY
+
+// Sets are equal when they have the same values, however their trees are shaped.
+func (s *set[K]) Equal(y Set[K]) bool {
+	return Eq(s.d, y.set_().d)
+}
+
+// The hash only depends on the values, so equal sets have the same hash.
+func (s *set[K]) Hash() uint64 {
+	return Hash(s.d)
+}
+
+// ITERATORS
+
+// Get a sequence of the values in a set, from lowest to highest.
+// The tree is walked lazily, so stopping early skips the rest of the set.
+func All[K Comparable[K]](s Set[K]) iter.Seq[K] {
+	return dict.KeysSeq(s.set_().d)
+}
+
+// Build a set from a sequence of values, removing any duplicates.
+func Collect[K Comparable[K]](seq iter.Seq[K]) Set[K] {
+	b := NewBuilder[K]()
+	for k := range seq {
+		b.Insert(k)
+	}
+	return b.Freeze()
 }
 
 // TRANSFORM
@@ -4,8 +4,10 @@ import (
 	"testing"
 
 	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/char"
 	"github.com/Confidenceman02/scion-tools/pkg/dict"
 	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
 	"github.com/Confidenceman02/scion-tools/pkg/tuple"
 	"github.com/stretchr/testify/assert"
 )
@@ -42,7 +44,7 @@ func TestBuildFunctions(t *testing.T) {
 			asserts.Equal(set42, Insert(42, Empty[basics.Int]()))
 		})
 		t.Run("adds new element to a set of 100", func(t *testing.T) {
-			asserts.Equal(FromList(list.Range(1, 101)), Insert(101, set1To100))
+			asserts.Equal(ToList(FromList(list.Range(1, 101))), ToList(Insert(101, set1To100)))
 		})
 		t.Run("leaves existing element intact if it contains a given element", func(t *testing.T) {
 			asserts.Equal(set42, Insert(42, set42))
@@ -93,6 +95,24 @@ func TestQueryFunctions(t *testing.T) {
 		t.Run("returns false for element not in set of 100", func(t *testing.T) {
 			asserts.False(Member(-1, set1To100))
 		})
+		t.Run("works with tuple.Tuple3 elements", func(t *testing.T) {
+			SUT := FromList(list.FromSlice([]tuple.Tuple3[basics.Int, basics.Int, basics.Int]{
+				tuple.Triple[basics.Int, basics.Int, basics.Int](1, 2, 3),
+				tuple.Triple[basics.Int, basics.Int, basics.Int](1, 2, 3),
+				tuple.Triple[basics.Int, basics.Int, basics.Int](0, 9, 9),
+			}))
+
+			asserts.Equal(basics.Int(2), Size(SUT))
+			asserts.True(Member(tuple.Triple[basics.Int, basics.Int, basics.Int](0, 9, 9), SUT))
+			asserts.False(Member(tuple.Triple[basics.Int, basics.Int, basics.Int](0, 9, 8), SUT))
+		})
+		t.Run("works with char.Char elements", func(t *testing.T) {
+			SUT := FromList(list.FromSlice([]char.Char{'c', 'a', 'b', 'a'}))
+
+			asserts.True(Member('a', SUT))
+			asserts.False(Member('d', SUT))
+			asserts.Equal([]char.Char{'a', 'b', 'c'}, list.ToSlice(ToList(SUT)))
+		})
 	})
 	t.Run("Size", func(t *testing.T) {
 		t.Run("returns 0 for empty set", func(t *testing.T) {
@@ -107,6 +127,89 @@ func TestQueryFunctions(t *testing.T) {
 	})
 }
 
+func TestOrderStatistics(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Rank", func(t *testing.T) {
+		t.Run("counts the values lower than a member", func(t *testing.T) {
+			asserts.Equal(basics.Int(41), Rank(42, set1To100))
+		})
+		t.Run("counts the values lower than a missing value", func(t *testing.T) {
+			asserts.Equal(basics.Int(0), Rank(-1, set1To100))
+			asserts.Equal(basics.Int(100), Rank(500, set1To100))
+		})
+	})
+	t.Run("At", func(t *testing.T) {
+		t.Run("returns the value at an index", func(t *testing.T) {
+			asserts.Equal(maybe.Just[basics.Int]{Value: 1}, At(0, set1To100))
+			asserts.Equal(maybe.Just[basics.Int]{Value: 42}, At(41, set1To100))
+			asserts.Equal(maybe.Just[basics.Int]{Value: 100}, At(99, set1To100))
+		})
+		t.Run("returns Nothing out of range", func(t *testing.T) {
+			asserts.Equal(maybe.Nothing{}, At(100, set1To100))
+			asserts.Equal(maybe.Nothing{}, At(-1, set1To100))
+			asserts.Equal(maybe.Nothing{}, At(0, Empty[basics.Int]()))
+		})
+	})
+	t.Run("TakeSmallest", func(t *testing.T) {
+		asserts.Equal(list.ToSlice(list.Range(1, 50)), list.ToSlice(ToList(TakeSmallest(50, set1To100))))
+		asserts.Equal(basics.Int(0), Size(TakeSmallest(0, set1To100)))
+		asserts.Equal(basics.Int(100), Size(TakeSmallest(500, set1To100)))
+	})
+	t.Run("DropSmallest", func(t *testing.T) {
+		asserts.Equal(list.ToSlice(list.Range(51, 100)), list.ToSlice(ToList(DropSmallest(50, set1To100))))
+		asserts.Equal(basics.Int(100), Size(DropSmallest(-3, set1To100)))
+		asserts.True(IsEmpty(DropSmallest(100, set1To100)))
+	})
+	t.Run("Percentile", func(t *testing.T) {
+		asserts.Equal(maybe.Just[basics.Int]{Value: 1}, Percentile(0, set1To100))
+		asserts.Equal(maybe.Just[basics.Int]{Value: 90}, Percentile(90, set1To100))
+		asserts.Equal(maybe.Just[basics.Int]{Value: 100}, Percentile(100, set1To100))
+		asserts.Equal(maybe.Nothing{}, Percentile(50, Empty[basics.Int]()))
+	})
+	t.Run("Median", func(t *testing.T) {
+		asserts.Equal(maybe.Just[basics.Int]{Value: 50}, Median(set1To100))
+		asserts.Equal(maybe.Just[basics.Int]{Value: 42}, Median(set42))
+		asserts.Equal(maybe.Nothing{}, Median(Empty[basics.Int]()))
+	})
+}
+
+func TestBuilder(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Insert, Remove and Freeze", func(t *testing.T) {
+		b := NewBuilder[basics.Int]()
+		for i := basics.Int(1); i <= 100; i++ {
+			b.Insert(i)
+		}
+		b.Insert(42)
+		b.Remove(100)
+		b.Remove(500)
+
+		asserts.Equal(list.ToSlice(list.Range(1, 99)), list.ToSlice(ToList(b.Freeze())))
+	})
+	t.Run("Member and Size", func(t *testing.T) {
+		b := NewBuilder[basics.Int]()
+		b.Insert(42)
+
+		asserts.True(b.Member(42))
+		asserts.False(b.Member(1))
+		asserts.Equal(basics.Int(1), b.Size())
+	})
+	t.Run("Frozen sets never change", func(t *testing.T) {
+		b := NewBuilderFrom(set1To50)
+		frozen := b.Freeze()
+		for i := basics.Int(1); i <= 50; i += 2 {
+			b.Remove(i)
+		}
+		b.Insert(500)
+
+		asserts.Equal(list.ToSlice(list.Range(1, 50)), list.ToSlice(ToList(frozen)))
+		asserts.Equal(list.ToSlice(list.Range(1, 50)), list.ToSlice(ToList(set1To50)))
+		asserts.Equal(basics.Int(26), Size(b.Freeze()))
+	})
+}
+
 func TestCombineFunctions(t *testing.T) {
 	asserts := assert.New(t)
 
@@ -118,10 +221,10 @@ func TestCombineFunctions(t *testing.T) {
 			asserts.Equal(dict.Keys(set1To100.set_().d), dict.Keys(Union(set1To100, set1To100).set_().d))
 		})
 		t.Run("with subset doesn't change anything", func(t *testing.T) {
-			asserts.Equal(set1To100, Union(set1To100, set42))
+			asserts.Equal(ToList(set1To100), ToList(Union(set1To100, set42)))
 		})
 		t.Run("with superset returns superset", func(t *testing.T) {
-			asserts.Equal(set1To100, Union(set42, set1To100))
+			asserts.Equal(ToList(set1To100), ToList(Union(set42, set1To100)))
 		})
 		t.Run("contains elements of both singletons", func(t *testing.T) {
 			asserts.Equal(list.FromSlice([]basics.Int{1, 42}), dict.Keys(Union(set42, Singleton[basics.Int](1)).set_().d))
@@ -135,7 +238,7 @@ func TestCombineFunctions(t *testing.T) {
 			asserts.Equal(Empty[basics.Int](), Intersect(set42, Empty[basics.Int]()))
 		})
 		t.Run("with itself doesn't change anything", func(t *testing.T) {
-			asserts.Equal(set1To100, Intersect(set1To100, set1To100))
+			asserts.Equal(ToList(set1To100), ToList(Intersect(set1To100, set1To100)))
 		})
 		t.Run("with subset returns subset", func(t *testing.T) {
 			asserts.Equal(set42, Intersect(set1To100, set42))
@@ -147,7 +250,7 @@ func TestCombineFunctions(t *testing.T) {
 			asserts.Equal(Empty[basics.Int](), Intersect(set42, Singleton[basics.Int](1)))
 		})
 		t.Run("consists of common elements only", func(t *testing.T) {
-			asserts.Equal(set51To100, Intersect(set1To100, set51To150))
+			asserts.Equal(ToList(set51To100), ToList(Intersect(set1To100, set51To150)))
 		})
 	})
 	t.Run("Diff", func(t *testing.T) {
@@ -158,7 +261,7 @@ func TestCombineFunctions(t *testing.T) {
 			asserts.Equal(Empty[basics.Int](), Diff(set1To100, set1To100))
 		})
 		t.Run("with subset returns set without subset", func(t *testing.T) {
-			asserts.Equal(Remove(42, set1To100), Diff(set1To100, set42))
+			asserts.Equal(ToList(Remove(42, set1To100)), ToList(Diff(set1To100, set42)))
 		})
 		t.Run("with superset returns empty set", func(t *testing.T) {
 			asserts.Equal(Empty[basics.Int](), Diff(set42, set1To100))
@@ -186,6 +289,25 @@ func TestListFunctions(t *testing.T) {
 			asserts.Equal(set1To100, FromList(list.Cons(1, list.Range(1, 100))))
 		})
 	})
+	t.Run("FromSortedList", func(t *testing.T) {
+		t.Run("returns set with list elements", func(t *testing.T) {
+			asserts.Equal(list.Range(1, 100), ToList(FromSortedList(list.Range(1, 100))))
+		})
+		t.Run("panics on unsorted list", func(t *testing.T) {
+			asserts.Panics(func() { FromSortedList(list.FromSlice([]basics.Int{2, 1})) })
+		})
+	})
+	t.Run("FromSortedSlice", func(t *testing.T) {
+		t.Run("returns set with slice elements", func(t *testing.T) {
+			asserts.Equal(set1To100, FromSortedSlice(list.ToSlice(list.Range(1, 100))))
+		})
+		t.Run("returns empty set for empty slice", func(t *testing.T) {
+			asserts.Equal(Empty[basics.Int](), FromSortedSlice([]basics.Int{}))
+		})
+		t.Run("panics on duplicates", func(t *testing.T) {
+			asserts.Panics(func() { FromSortedSlice([]basics.Int{1, 1}) })
+		})
+	})
 	t.Run("ToList", func(t *testing.T) {
 		t.Run("returns empty list for empty set", func(t *testing.T) {
 			asserts.Equal(list.Empty[basics.Int](), ToList(Empty[basics.Int]()))
@@ -199,6 +321,38 @@ func TestListFunctions(t *testing.T) {
 	})
 }
 
+func TestIterators(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("All", func(t *testing.T) {
+		var SUT []basics.Int
+		for k := range All(set1To50) {
+			SUT = append(SUT, k)
+		}
+
+		asserts.Equal(list.ToSlice(list.Range(1, 50)), SUT)
+	})
+	t.Run("Collect", func(t *testing.T) {
+		SUT := Collect(list.Values(list.FromSlice([]basics.Int{3, 1, 2, 3, 1})))
+
+		asserts.Equal([]basics.Int{1, 2, 3}, list.ToSlice(ToList(SUT)))
+	})
+}
+
+func TestEquality(t *testing.T) {
+	asserts := assert.New(t)
+	inserted := list.Foldl(Insert[basics.Int], Empty[basics.Int](), list.Range(1, 50))
+
+	t.Run("Same values with different tree shapes", func(t *testing.T) {
+		asserts.True(basics.Eq(inserted, set1To50))
+		asserts.Equal(basics.Hash(inserted), basics.Hash(set1To50))
+	})
+	t.Run("Different values", func(t *testing.T) {
+		asserts.False(basics.Eq(inserted, Remove(50, set1To50)))
+		asserts.False(basics.Eq(inserted, Insert(0, Remove(50, set1To50))))
+	})
+}
+
 func TestTransformFunctions(t *testing.T) {
 	asserts := assert.New(t)
 
@@ -213,7 +367,7 @@ func TestTransformFunctions(t *testing.T) {
 
 	t.Run("Foldl", func(t *testing.T) {
 		t.Run("with insert and empty set acts as identity function", func(t *testing.T) {
-			asserts.Equal(set1To100, Foldl(Insert[basics.Int], Empty[basics.Int](), set1To100))
+			asserts.Equal(ToList(set1To100), ToList(Foldl(Insert[basics.Int], Empty[basics.Int](), set1To100)))
 		})
 		t.Run("with counter ans zero acts as size function", func(t *testing.T) {
 			asserts.Equal(basics.Int(100), Foldl(func(_, count basics.Int) basics.Int { return basics.Add(count, 1) }, 0, set1To100))
@@ -0,0 +1,308 @@
+// Package stream has lazy sequences that only work out their elements when they are needed.
+// A stream can be longer than memory allows or never end at all, like the lines of a large log
+// file or the pages of a paginated API, and still be mapped, filtered and zipped like a list.
+//
+// Streams are memoised, so each element is worked out at most once however many times the
+// stream is walked. This also means that holding on to the start of a stream keeps every
+// element that has been worked out so far in memory, so long streams should be walked
+// without keeping a reference to their head.
+package stream
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/internal"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	. "github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"iter"
+	"sync"
+)
+
+type Stream[T any] interface {
+	force() *internal.Cons_[T, Stream[T]]
+}
+
+type empty[T any] struct{}
+
+func (empty[T]) force() *internal.Cons_[T, Stream[T]] {
+	return nil
+}
+
+// A lazy stream works out its first cell the first time it is forced and keeps it.
+// The function is dropped once it has run so whatever it holds on to can be freed.
+type lazy[T any] struct {
+	once sync.Once
+	next func() *internal.Cons_[T, Stream[T]]
+	cell *internal.Cons_[T, Stream[T]]
+}
+
+func (l *lazy[T]) force() *internal.Cons_[T, Stream[T]] {
+	l.once.Do(func() {
+		l.cell = l.next()
+		l.next = nil
+	})
+	return l.cell
+}
+
+func delay[T any](next func() *internal.Cons_[T, Stream[T]]) Stream[T] {
+	return &lazy[T]{next: next}
+}
+
+// Put off making a stream until it is forced.
+func suspend[T any](s func() Stream[T]) Stream[T] {
+	return delay(func() *internal.Cons_[T, Stream[T]] { return s().force() })
+}
+
+func cell[T any](x T, rest Stream[T]) *internal.Cons_[T, Stream[T]] {
+	return &internal.Cons_[T, Stream[T]]{A: x, B: rest}
+}
+
+// Create
+
+// Create a stream with no elements.
+func Empty[T any]() Stream[T] {
+	return empty[T]{}
+}
+
+// Create a stream with only one element.
+func Singleton[T any](x T) Stream[T] {
+	return Cons(x, Empty[T]())
+}
+
+// Add an element to the front of a stream.
+func Cons[T any](x T, rest Stream[T]) Stream[T] {
+	return delay(func() *internal.Cons_[T, Stream[T]] { return cell(x, rest) })
+}
+
+// Build a stream from a seed value. The function gets the seed and gives back Nothing to end the stream,
+// or Just the next element and the seed for the rest of the stream.
+//
+//	Unfold(func(n Int) maybe.Maybe[Tuple2[Int, Int]] {
+//		if n > 3 {
+//			return maybe.Nothing{}
+//		}
+//		return maybe.Just[Tuple2[Int, Int]]{Value: Pair(n*n, n+1)}
+//	}, 1) // [1, 4, 9]
+func Unfold[S, A any](f func(S) maybe.Maybe[Tuple2[A, S]], seed S) Stream[A] {
+	return delay(func() *internal.Cons_[A, Stream[A]] {
+		next, ok := f(seed).(maybe.Just[Tuple2[A, S]])
+		if !ok {
+			return nil
+		}
+		return cell(First(next.Value), Unfold(f, Second(next.Value)))
+	})
+}
+
+// Create an endless stream by applying a function to the previous element, starting with x.
+//
+//	Iterate(func(n Int) Int { return n * 2 }, 1) // [1, 2, 4, 8, ...]
+func Iterate[A any](f func(A) A, x A) Stream[A] {
+	return Cons(x, suspend(func() Stream[A] { return Iterate(f, f(x)) }))
+}
+
+// Create an endless stream of the same element. The stream is a single cell that points back at itself.
+func Repeat[T any](x T) Stream[T] {
+	s := &lazy[T]{}
+	s.next = func() *internal.Cons_[T, Stream[T]] { return cell[T](x, s) }
+	return s
+}
+
+// Create a stream of numbers, every element increasing by one.
+// You give the lowest and highest number that should be in the stream.
+func Range(low basics.Int, hi basics.Int) Stream[basics.Int] {
+	return delay(func() *internal.Cons_[basics.Int, Stream[basics.Int]] {
+		if low > hi {
+			return nil
+		}
+		return cell(low, Range(low+1, hi))
+	})
+}
+
+// Walk a list as a stream.
+func FromList[T any](xs list.List[T]) Stream[T] {
+	return delay(func() *internal.Cons_[T, Stream[T]] {
+		c := xs.Cons()
+		if c == nil {
+			return nil
+		}
+		return cell(c.A, FromList(c.B))
+	})
+}
+
+// Transform
+
+// Apply a function to every element of a stream. The function is applied to an element when it is first needed.
+func Map[A, B any](f func(A) B, s Stream[A]) Stream[B] {
+	return delay(func() *internal.Cons_[B, Stream[B]] {
+		c := s.force()
+		if c == nil {
+			return nil
+		}
+		return cell(f(c.A), Map(f, c.B))
+	})
+}
+
+// Keep elements that satisfy the test.
+// Finding the next element of an endless stream that has none that pass never finishes.
+func Filter[T any](isGood func(T) bool, s Stream[T]) Stream[T] {
+	return delay(func() *internal.Cons_[T, Stream[T]] {
+		for c := s.force(); c != nil; c = c.B.force() {
+			if isGood(c.A) {
+				return cell(c.A, Filter(isGood, c.B))
+			}
+		}
+		return nil
+	})
+}
+
+// Apply a function that may succeed to every element, only keeping the successes.
+func FilterMap[A, B any](f func(A) maybe.Maybe[B], s Stream[A]) Stream[B] {
+	return delay(func() *internal.Cons_[B, Stream[B]] {
+		for c := s.force(); c != nil; c = c.B.force() {
+			if j, ok := f(c.A).(maybe.Just[B]); ok {
+				return cell(j.Value, FilterMap(f, c.B))
+			}
+		}
+		return nil
+	})
+}
+
+// Reduce a stream from the left. This walks the whole stream, so it never finishes on an endless stream.
+func Foldl[A, B any](f func(A, B) B, acc B, s Stream[A]) B {
+	for c := s.force(); c != nil; c = c.B.force() {
+		acc = f(c.A, acc)
+	}
+	return acc
+}
+
+// Combine
+
+// Put two streams together. The second stream is only forced once the first one runs out.
+func Append[T any](xs Stream[T], ys Stream[T]) Stream[T] {
+	return delay(func() *internal.Cons_[T, Stream[T]] {
+		c := xs.force()
+		if c == nil {
+			return ys.force()
+		}
+		return cell(c.A, Append(c.B, ys))
+	})
+}
+
+// Concatenate a stream of streams into a single stream.
+func Concat[T any](ss Stream[Stream[T]]) Stream[T] {
+	return delay(func() *internal.Cons_[T, Stream[T]] {
+		for o := ss.force(); o != nil; o = o.B.force() {
+			if c := o.A.force(); c != nil {
+				return cell(c.A, Append(c.B, Concat(o.B)))
+			}
+		}
+		return nil
+	})
+}
+
+// Pair up the elements of two streams. If one stream is longer, the extra elements are dropped.
+func Zip[A, B any](xs Stream[A], ys Stream[B]) Stream[Tuple2[A, B]] {
+	return delay(func() *internal.Cons_[Tuple2[A, B], Stream[Tuple2[A, B]]] {
+		x := xs.force()
+		if x == nil {
+			return nil
+		}
+		y := ys.force()
+		if y == nil {
+			return nil
+		}
+		return cell(Pair(x.A, y.A), Zip(x.B, y.B))
+	})
+}
+
+// Sub-streams
+
+// Take the first n elements of a stream. Elements after the first n are never forced.
+func Take[T any](n basics.Int, s Stream[T]) Stream[T] {
+	if n <= 0 {
+		return Empty[T]()
+	}
+	return delay(func() *internal.Cons_[T, Stream[T]] {
+		c := s.force()
+		if c == nil {
+			return nil
+		}
+		return cell(c.A, Take(n-1, c.B))
+	})
+}
+
+// Drop the first n elements of a stream.
+func Drop[T any](n basics.Int, s Stream[T]) Stream[T] {
+	return delay(func() *internal.Cons_[T, Stream[T]] {
+		c := s.force()
+		for ; c != nil && n > 0; n-- {
+			c = c.B.force()
+		}
+		return c
+	})
+}
+
+// Take elements from the front of a stream while they satisfy the test.
+// The first element that fails the test is forced, but nothing after it.
+func TakeWhile[T any](isGood func(T) bool, s Stream[T]) Stream[T] {
+	return delay(func() *internal.Cons_[T, Stream[T]] {
+		c := s.force()
+		if c == nil || !isGood(c.A) {
+			return nil
+		}
+		return cell(c.A, TakeWhile(isGood, c.B))
+	})
+}
+
+// Deconstruct
+
+// Determine if a stream is empty. This forces the first element.
+func IsEmpty[T any](s Stream[T]) bool {
+	return s.force() == nil
+}
+
+// Extract the first element of a stream.
+func Head[T any](s Stream[T]) maybe.Maybe[T] {
+	c := s.force()
+	if c == nil {
+		return maybe.Nothing{}
+	}
+	return maybe.Just[T]{Value: c.A}
+}
+
+// Extract the rest of the stream. Nothing after the first element is forced.
+func Tail[T any](s Stream[T]) maybe.Maybe[Stream[T]] {
+	c := s.force()
+	if c == nil {
+		return maybe.Nothing{}
+	}
+	return maybe.Just[Stream[T]]{Value: c.B}
+}
+
+// Utils
+
+// Force every element of a stream into a list. This never finishes on an endless stream, so Take from it first.
+func ToList[T any](s Stream[T]) list.List[T] {
+	var xs []T
+	for c := s.force(); c != nil; c = c.B.force() {
+		xs = append(xs, c.A)
+	}
+	return list.FromSlice(xs)
+}
+
+// Iterators
+
+// Get a sequence of the elements of a stream, forcing each one as it is yielded.
+//
+//	for line := range Values(lines) {
+//		fmt.Println(line)
+//	}
+func Values[T any](s Stream[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for c := s.force(); c != nil; c = c.B.force() {
+			if !yield(c.A) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package stream
+
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/list"
+	"github.com/Confidenceman02/scion-tools/pkg/maybe"
+	. "github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func ints(s Stream[basics.Int]) []basics.Int {
+	return list.ToSlice(ToList(s))
+}
+
+// Count how many times a function is applied.
+func counted[A, B any](f func(A) B) (func(A) B, *int) {
+	calls := 0
+	return func(a A) B {
+		calls++
+		return f(a)
+	}, &calls
+}
+
+func naturals() Stream[basics.Int] {
+	return Iterate(func(n basics.Int) basics.Int { return n + 1 }, 0)
+}
+
+func TestCreate(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Empty, Singleton and Cons", func(t *testing.T) {
+		asserts.Equal([]basics.Int{}, ints(Empty[basics.Int]()))
+		asserts.Equal([]basics.Int{1}, ints(Singleton[basics.Int](1)))
+		asserts.Equal([]basics.Int{1, 2}, ints(Cons(1, Singleton[basics.Int](2))))
+	})
+
+	t.Run("Unfold", func(t *testing.T) {
+		SUT := Unfold(func(n basics.Int) maybe.Maybe[Tuple2[basics.Int, basics.Int]] {
+			if n > 3 {
+				return maybe.Nothing{}
+			}
+			return maybe.Just[Tuple2[basics.Int, basics.Int]]{Value: Pair(n*n, n+1)}
+		}, 1)
+
+		asserts.Equal([]basics.Int{1, 4, 9}, ints(SUT))
+	})
+
+	t.Run("Iterate and Repeat are endless", func(t *testing.T) {
+		asserts.Equal([]basics.Int{1, 2, 4, 8}, ints(Take(4, Iterate(func(n basics.Int) basics.Int { return n * 2 }, 1))))
+		asserts.Equal([]basics.Int{7, 7, 7}, ints(Take(3, Repeat[basics.Int](7))))
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		asserts.Equal([]basics.Int{1, 2, 3}, ints(Range(1, 3)))
+		asserts.Equal([]basics.Int{}, ints(Range(3, 1)))
+	})
+
+	t.Run("FromList and ToList", func(t *testing.T) {
+		xs := list.Range(1, 5)
+
+		asserts.Equal(list.ToSlice(xs), list.ToSlice(ToList(FromList(xs))))
+	})
+}
+
+func TestTransform(t *testing.T) {
+	asserts := assert.New(t)
+	even := func(n basics.Int) bool { return n%2 == 0 }
+
+	t.Run("Map", func(t *testing.T) {
+		asserts.Equal([]basics.Int{2, 4, 6}, ints(Map(func(n basics.Int) basics.Int { return n * 2 }, Range(1, 3))))
+	})
+
+	t.Run("Filter an endless stream", func(t *testing.T) {
+		asserts.Equal([]basics.Int{0, 2, 4}, ints(Take(3, Filter(even, naturals()))))
+	})
+
+	t.Run("FilterMap", func(t *testing.T) {
+		SUT := FilterMap(func(n basics.Int) maybe.Maybe[basics.Int] {
+			if even(n) {
+				return maybe.Just[basics.Int]{Value: n * 10}
+			}
+			return maybe.Nothing{}
+		}, Range(1, 6))
+
+		asserts.Equal([]basics.Int{20, 40, 60}, ints(SUT))
+	})
+
+	t.Run("Foldl", func(t *testing.T) {
+		asserts.Equal(basics.Int(15), Foldl(basics.Add[basics.Int], 0, Range(1, 5)))
+	})
+
+	t.Run("Long streams", func(t *testing.T) {
+		SUT := Foldl(basics.Add[basics.Int], 0, Filter(even, Range(1, 1_000_000)))
+
+		asserts.Equal(basics.Int(250_000_500_000), SUT)
+	})
+}
+
+func TestCombine(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Zip drops extra elements", func(t *testing.T) {
+		SUT := Zip(naturals(), FromList(list.FromSlice([]string{"a", "b"})))
+
+		asserts.Equal(
+			[]Tuple2[basics.Int, string]{Pair(basics.Int(0), "a"), Pair(basics.Int(1), "b")},
+			list.ToSlice(ToList(SUT)),
+		)
+	})
+
+	t.Run("Append", func(t *testing.T) {
+		asserts.Equal([]basics.Int{1, 2, 3, 4}, ints(Append(Range(1, 2), Range(3, 4))))
+	})
+
+	t.Run("Concat skips empty streams", func(t *testing.T) {
+		SUT := Concat(FromList(list.FromSlice([]Stream[basics.Int]{Empty[basics.Int](), Range(1, 2), Empty[basics.Int](), Range(3, 3)})))
+
+		asserts.Equal([]basics.Int{1, 2, 3}, ints(SUT))
+	})
+
+	t.Run("Concat an endless stream of streams", func(t *testing.T) {
+		SUT := Concat(Map(func(n basics.Int) Stream[basics.Int] { return Range(1, n) }, naturals()))
+
+		asserts.Equal([]basics.Int{1, 1, 2, 1, 2, 3}, ints(Take(6, SUT)))
+	})
+}
+
+func TestSubStreams(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Take and Drop", func(t *testing.T) {
+		asserts.Equal([]basics.Int{1, 2}, ints(Take(2, Range(1, 5))))
+		asserts.Equal([]basics.Int{1, 2, 3}, ints(Take(10, Range(1, 3))))
+		asserts.Equal([]basics.Int{}, ints(Take(-1, Range(1, 3))))
+		asserts.Equal([]basics.Int{4, 5}, ints(Drop(3, Range(1, 5))))
+		asserts.Equal([]basics.Int{}, ints(Drop(10, Range(1, 5))))
+	})
+
+	t.Run("TakeWhile", func(t *testing.T) {
+		SUT := TakeWhile(func(n basics.Int) bool { return n < 4 }, naturals())
+
+		asserts.Equal([]basics.Int{0, 1, 2, 3}, ints(SUT))
+	})
+}
+
+func TestDeconstruct(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Head and Tail", func(t *testing.T) {
+		asserts.Equal(maybe.Just[basics.Int]{Value: 0}, Head(naturals()))
+		asserts.Equal(maybe.Nothing{}, Head(Empty[basics.Int]()))
+		asserts.Equal(
+			maybe.Just[basics.Int]{Value: 1},
+			maybe.AndThen(Head[basics.Int], Tail(naturals())),
+		)
+		asserts.Equal(maybe.Nothing{}, Tail(Empty[basics.Int]()))
+	})
+
+	t.Run("IsEmpty", func(t *testing.T) {
+		asserts.True(IsEmpty(Empty[basics.Int]()))
+		asserts.True(IsEmpty(Filter(func(basics.Int) bool { return false }, Range(1, 10))))
+		asserts.False(IsEmpty(Repeat[basics.Int](1)))
+	})
+
+	t.Run("Values", func(t *testing.T) {
+		var xs []basics.Int
+		for x := range Values(naturals()) {
+			if x > 2 {
+				break
+			}
+			xs = append(xs, x)
+		}
+
+		asserts.Equal([]basics.Int{0, 1, 2}, xs)
+	})
+}
+
+func TestLaziness(t *testing.T) {
+	asserts := assert.New(t)
+	double := func(n basics.Int) basics.Int { return n * 2 }
+
+	t.Run("Nothing is worked out until it is needed", func(t *testing.T) {
+		f, calls := counted(double)
+		next, steps := counted(func(n basics.Int) basics.Int { return n + 1 })
+		SUT := Map(f, Iterate(next, 0))
+
+		asserts.Equal(0, *calls)
+		asserts.Equal(0, *steps)
+
+		Head(SUT)
+		asserts.Equal(1, *calls)
+		asserts.Equal(0, *steps)
+	})
+
+	t.Run("Elements are worked out once", func(t *testing.T) {
+		f, calls := counted(double)
+		SUT := Map(f, Range(1, 5))
+
+		ints(SUT)
+		ints(SUT)
+		Head(SUT)
+		asserts.Equal(5, *calls)
+	})
+
+	t.Run("Take does not force the element after the last one", func(t *testing.T) {
+		f, calls := counted(double)
+		ints(Take(3, Map(f, naturals())))
+
+		asserts.Equal(3, *calls)
+	})
+
+	t.Run("TakeWhile stops at the first failing element", func(t *testing.T) {
+		f, calls := counted(double)
+		ints(TakeWhile(func(n basics.Int) bool { return n < 6 }, Map(f, naturals())))
+
+		asserts.Equal(4, *calls)
+	})
+
+	t.Run("Filter and FilterMap only force what they need", func(t *testing.T) {
+		isGood, tests := counted(func(n basics.Int) bool { return n%3 == 0 })
+		f, calls := counted(func(n basics.Int) maybe.Maybe[basics.Int] { return maybe.Just[basics.Int]{Value: n} })
+
+		asserts.Equal([]basics.Int{0, 3}, ints(Take(2, Filter(isGood, naturals()))))
+		asserts.Equal(4, *tests)
+		asserts.Equal([]basics.Int{0, 1}, ints(Take(2, FilterMap(f, naturals()))))
+		asserts.Equal(2, *calls)
+	})
+
+	t.Run("Unfold is called once per element", func(t *testing.T) {
+		f, calls := counted(func(n basics.Int) maybe.Maybe[Tuple2[basics.Int, basics.Int]] {
+			return maybe.Just[Tuple2[basics.Int, basics.Int]]{Value: Pair(n, n+1)}
+		})
+		SUT := Unfold(f, 0)
+
+		ints(Take(3, SUT))
+		ints(Take(3, SUT))
+		asserts.Equal(3, *calls)
+	})
+
+	t.Run("Zip and Append only force what they need", func(t *testing.T) {
+		f, calls := counted(double)
+		g, gCalls := counted(double)
+
+		ToList(Take(2, Zip(Map(f, naturals()), Map(g, naturals()))))
+		asserts.Equal(2, *calls)
+		asserts.Equal(2, *gCalls)
+
+		ints(Take(2, Append(Range(1, 2), Map(f, naturals()))))
+		asserts.Equal(2, *calls)
+	})
+
+	t.Run("Tail does not force the rest", func(t *testing.T) {
+		f, calls := counted(double)
+		Tail(Map(f, naturals()))
+
+		asserts.Equal(1, *calls)
+	})
+
+	t.Run("Streams can be shared between goroutines", func(t *testing.T) {
+		f, calls := counted(double)
+		var mu sync.Mutex
+		SUT := Map(func(n basics.Int) basics.Int {
+			mu.Lock()
+			defer mu.Unlock()
+			return f(n)
+		}, Range(1, 100))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ints(SUT)
+			}()
+		}
+		wg.Wait()
+
+		asserts.Equal(100, *calls)
+	})
+}
@@ -5,9 +5,11 @@ import (
 	"github.com/Confidenceman02/scion-tools/pkg/basics"
 	"github.com/Confidenceman02/scion-tools/pkg/bitwise"
 	"github.com/Confidenceman02/scion-tools/pkg/char"
+	"github.com/Confidenceman02/scion-tools/pkg/internal/hashing"
 	"github.com/Confidenceman02/scion-tools/pkg/list"
 	"github.com/Confidenceman02/scion-tools/pkg/maybe"
 	"github.com/Confidenceman02/scion-tools/pkg/tuple"
+	"iter"
 	"math"
 	"regexp"
 	"strconv"
@@ -27,6 +29,12 @@ func (i String) Cmp(y basics.Comparable[String]) int {
 func (i String) T() String {
 	return i
 }
+func (i String) Equal(y String) bool {
+	return i == y
+}
+func (i String) Hash() uint64 {
+	return hashing.String(string(i))
+}
 
 // Strings
 
@@ -284,6 +292,22 @@ func FromInt(x basics.Int) String {
 	return String(strconv.FormatInt(int64(x), 10))
 }
 
+// Try to convert a string into an Int64, failing on improperly formatted strings or values that do not fit in 64 bits.
+func ToInt64(x String) maybe.Maybe[basics.Int64] {
+	i, err := strconv.ParseInt(string(x), 10, 64)
+
+	if err != nil {
+		return maybe.Nothing{}
+	} else {
+		return maybe.Just[basics.Int64]{Value: basics.Int64(i)}
+	}
+}
+
+// Convert an Int64 to a String.
+func FromInt64(x basics.Int64) String {
+	return String(strconv.FormatInt(int64(x), 10))
+}
+
 // Float conversions
 
 // Try to convert a string into a float, failing on improperly formatted strings.
@@ -302,6 +326,22 @@ func FromFloat(x basics.Float) String {
 	return String(strconv.FormatFloat(float64(x), 'g', -1, 32))
 }
 
+// Try to convert a string into a Float64, failing on improperly formatted strings.
+func ToFloat64(x String) maybe.Maybe[basics.Float64] {
+	f, err := strconv.ParseFloat(string(x), 64)
+
+	if err != nil {
+		return maybe.Nothing{}
+	} else {
+		return maybe.Just[basics.Float64]{Value: basics.Float64(f)}
+	}
+}
+
+// Convert a Float64 to a String.
+func FromFloat64(x basics.Float64) String {
+	return String(strconv.FormatFloat(float64(x), 'g', -1, 64))
+}
+
 // Char Conversions
 
 // Create a string from a given character.
@@ -343,6 +383,21 @@ func FromList(chars list.List[char.Char]) String {
 	)
 }
 
+// Get a sequence of the characters in a string, without building a list.
+//
+//	for c := range Chars("abc") {
+//		fmt.Println(c)
+//	}
+func Chars(str String) iter.Seq[char.Char] {
+	return func(yield func(char.Char) bool) {
+		for _, r := range str {
+			if !yield(char.Char(r)) {
+				return
+			}
+		}
+	}
+}
+
 // Formatting
 
 // Convert a string to all upper case. Useful for case-insensitive comparisons and VIRTUAL YELLING.
@@ -361,10 +416,10 @@ func Pad(n basics.Int, char char.Char, str String) String {
 
 	return basics.Append(
 		basics.Append(
-			Repeat(basics.Ceiling(half), FromChar(char)),
+			Repeat(basics.Ceiling[basics.Int](half), FromChar(char)),
 			str,
 		),
-		Repeat(basics.Floor(half), FromChar(char)),
+		Repeat(basics.Floor[basics.Int](half), FromChar(char)),
 	).T()
 }
 
@@ -203,6 +203,32 @@ func TestIntConversions(t *testing.T) {
 		asserts.Equal(String("-42"), SUT2)
 	})
 
+	t.Run("ToInt64", func(t *testing.T) {
+		SUT1 := ToInt64("9007199254740993")
+		SUT2 := ToInt64("-42")
+		SUT3 := ToInt64("+7")
+		SUT4 := ToInt64("3.1")
+		SUT5 := ToInt64("31a")
+		SUT6 := ToInt64("")
+		SUT7 := ToInt64("9223372036854775808")
+
+		asserts.Equal(maybe.Just[basics.Int64]{Value: basics.Int64(9007199254740993)}, SUT1)
+		asserts.Equal(maybe.Just[basics.Int64]{Value: basics.Int64(-42)}, SUT2)
+		asserts.Equal(maybe.Just[basics.Int64]{Value: basics.Int64(7)}, SUT3)
+		asserts.Equal(maybe.Nothing{}, SUT4)
+		asserts.Equal(maybe.Nothing{}, SUT5)
+		asserts.Equal(maybe.Nothing{}, SUT6)
+		asserts.Equal(maybe.Nothing{}, SUT7)
+	})
+
+	t.Run("FromInt64", func(t *testing.T) {
+		SUT1 := FromInt64(9007199254740993)
+		SUT2 := FromInt64(-42)
+
+		asserts.Equal(String("9007199254740993"), SUT1)
+		asserts.Equal(String("-42"), SUT2)
+	})
+
 	t.Run("ToFloat", func(t *testing.T) {
 		SUT1 := ToFloat("123")
 		SUT2 := ToFloat("-42")
@@ -224,6 +250,31 @@ func TestIntConversions(t *testing.T) {
 		asserts.Equal(String("-42"), SUT2)
 		asserts.Equal(String("3.1"), SUT3)
 	})
+
+	t.Run("ToFloat64", func(t *testing.T) {
+		SUT1 := ToFloat64("123")
+		SUT2 := ToFloat64("-42")
+		SUT3 := ToFloat64("1700000000.123456")
+		SUT4 := ToFloat64("31a")
+
+		asserts.Equal(maybe.Just[basics.Float64]{Value: 123.0}, SUT1)
+		asserts.Equal(maybe.Just[basics.Float64]{Value: -42.0}, SUT2)
+		asserts.Equal(maybe.Just[basics.Float64]{Value: 1700000000.123456}, SUT3)
+		asserts.Equal(maybe.Nothing{}, SUT4)
+	})
+
+	t.Run("FromFloat64", func(t *testing.T) {
+		SUT1 := FromFloat64(123)
+		SUT2 := FromFloat64(-42)
+		tenth := basics.Float64(0.1)
+		SUT3 := FromFloat64(tenth + 0.2)
+		SUT4 := FromFloat64(1700000000.123456)
+
+		asserts.Equal(String("123"), SUT1)
+		asserts.Equal(String("-42"), SUT2)
+		asserts.Equal(String("0.30000000000000004"), SUT3)
+		asserts.Equal(String("1.700000000123456e+09"), SUT4)
+	})
 }
 
 func TestCharConversions(t *testing.T) {
@@ -267,6 +318,26 @@ func TestListConversions(t *testing.T) {
 		asserts.Equal(String("🙈🙉🙊"), SUT2)
 	})
 
+	t.Run("Chars", func(t *testing.T) {
+		var SUT []char.Char
+		for c := range Chars("a🙈b") {
+			SUT = append(SUT, c)
+		}
+
+		asserts.Equal([]char.Char{'a', '🙈', 'b'}, SUT)
+		asserts.Equal(list.ToSlice(ToList("a🙈b")), list.ToSlice(list.Collect(Chars("a🙈b"))))
+	})
+	t.Run("Chars stops early", func(t *testing.T) {
+		var SUT []char.Char
+		for c := range Chars("abc") {
+			if c == 'b' {
+				break
+			}
+			SUT = append(SUT, c)
+		}
+
+		asserts.Equal([]char.Char{'a'}, SUT)
+	})
 }
 
 func TestFormatting(t *testing.T) {
@@ -394,3 +465,12 @@ func TestBasicsComparisons(t *testing.T) {
 	asserts.Equal(String("xyz"), basics.Max(String("abc"), String("xyz")))
 	asserts.Equal(String("abc"), basics.Min(String("abc"), String("xyz")))
 }
+
+func TestEquality(t *testing.T) {
+	asserts := assert.New(t)
+
+	asserts.True(basics.Eq[String]("abc", "abc"))
+	asserts.False(basics.Eq[String]("abc", "abd"))
+	asserts.Equal(basics.Hash(String("abc")), basics.Hash(String("ab")+String("c")))
+	asserts.NotEqual(basics.Hash(String("abc")), basics.Hash(String("abd")))
+}
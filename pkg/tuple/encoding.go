@@ -0,0 +1,153 @@
+package tuple
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"github.com/Confidenceman02/scion-tools/pkg/internal"
+)
+
+// A Tuple2 is encoded as a JSON array of two values.
+func (t *tuple2[A, B]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.a, t.b})
+}
+
+// A Tuple3 is encoded as a JSON array of three values.
+func (t *tuple3[A, B, C]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.a, t.b, t.c})
+}
+
+// Decode a Tuple2 from a JSON array of two values.
+func PairFromJSON[A, B any](data []byte) (Tuple2[A, B], error) {
+	raw, err := unmarshalArray(data, 2)
+	if err != nil {
+		return nil, err
+	}
+	a, err := internal.UnmarshalJSON[A](raw[0])
+	if err != nil {
+		return nil, err
+	}
+	b, err := internal.UnmarshalJSON[B](raw[1])
+	if err != nil {
+		return nil, err
+	}
+	return Pair(a, b), nil
+}
+
+// Decode a Tuple3 from a JSON array of three values.
+func TripleFromJSON[A, B, C any](data []byte) (Tuple3[A, B, C], error) {
+	raw, err := unmarshalArray(data, 3)
+	if err != nil {
+		return nil, err
+	}
+	a, err := internal.UnmarshalJSON[A](raw[0])
+	if err != nil {
+		return nil, err
+	}
+	b, err := internal.UnmarshalJSON[B](raw[1])
+	if err != nil {
+		return nil, err
+	}
+	c, err := internal.UnmarshalJSON[C](raw[2])
+	if err != nil {
+		return nil, err
+	}
+	return Triple(a, b, c), nil
+}
+
+func unmarshalArray(data []byte, n int) ([]json.RawMessage, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw) != n {
+		return nil, fmt.Errorf("tuple: expecting an array of %d values but got %d", n, len(raw))
+	}
+	return raw, nil
+}
+
+// RegisterPair sets up Tuple2[A, B] to be decoded inside other values and sent through encoding/gob.
+func RegisterPair[A, B any]() {
+	gob.Register(&tuple2[A, B]{})
+	internal.RegisterJSON(PairFromJSON[A, B])
+}
+
+// RegisterTriple sets up Tuple3[A, B, C] to be decoded inside other values and sent through encoding/gob.
+func RegisterTriple[A, B, C any]() {
+	gob.Register(&tuple3[A, B, C]{})
+	internal.RegisterJSON(TripleFromJSON[A, B, C])
+}
+
+// A PairField holds a Tuple2 as a struct field that encoding/json can decode.
+type PairField[A, B any] struct {
+	Tuple2 Tuple2[A, B]
+}
+
+func (f PairField[A, B]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Tuple2)
+}
+
+func (f *PairField[A, B]) UnmarshalJSON(data []byte) error {
+	t, err := PairFromJSON[A, B](data)
+	if err != nil {
+		return err
+	}
+	f.Tuple2 = t
+	return nil
+}
+
+// A TripleField holds a Tuple3 as a struct field, like PairField does for a Tuple2.
+type TripleField[A, B, C any] struct {
+	Tuple3 Tuple3[A, B, C]
+}
+
+func (f TripleField[A, B, C]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Tuple3)
+}
+
+func (f *TripleField[A, B, C]) UnmarshalJSON(data []byte) error {
+	t, err := TripleFromJSON[A, B, C](data)
+	if err != nil {
+		return err
+	}
+	f.Tuple3 = t
+	return nil
+}
+
+// The fields of a tuple are unexported, so they are sent as an exported struct.
+type gobTuple2[A, B any] struct {
+	A A
+	B B
+}
+
+type gobTuple3[A, B, C any] struct {
+	A A
+	B B
+	C C
+}
+
+func (t *tuple2[A, B]) GobEncode() ([]byte, error) {
+	return internal.GobEncode(gobTuple2[A, B]{A: t.a, B: t.b})
+}
+
+func (t *tuple2[A, B]) GobDecode(data []byte) error {
+	var g gobTuple2[A, B]
+	if err := internal.GobDecode(data, &g); err != nil {
+		return err
+	}
+	t._tuple2 = &_tuple2[A, B]{a: g.A, b: g.B}
+	return nil
+}
+
+func (t *tuple3[A, B, C]) GobEncode() ([]byte, error) {
+	return internal.GobEncode(gobTuple3[A, B, C]{A: t.a, B: t.b, C: t.c})
+}
+
+func (t *tuple3[A, B, C]) GobDecode(data []byte) error {
+	var g gobTuple3[A, B, C]
+	if err := internal.GobDecode(data, &g); err != nil {
+		return err
+	}
+	t._tuple3 = &_tuple3[A, B, C]{a: g.A, b: g.B, c: g.C}
+	return nil
+}
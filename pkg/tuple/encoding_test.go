@@ -0,0 +1,100 @@
+package tuple
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/char"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type payload struct {
+	Point Tuple2[basics.Int, basics.Int]
+	Named Tuple3[string, basics.Int, bool]
+}
+
+func TestJSON(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Marshal", func(t *testing.T) {
+		SUT, err := json.Marshal(payload{Point: Pair[basics.Int, basics.Int](1, 2), Named: Triple[string, basics.Int, bool]("a", 1, true)})
+
+		asserts.NoError(err)
+		asserts.Equal(`{"Point":[1,2],"Named":["a",1,true]}`, string(SUT))
+	})
+	t.Run("PairFromJSON", func(t *testing.T) {
+		SUT, err := PairFromJSON[basics.Int, string]([]byte(`[1, "a"]`))
+
+		asserts.NoError(err)
+		asserts.Equal(Pair[basics.Int, string](1, "a"), SUT)
+	})
+	t.Run("TripleFromJSON", func(t *testing.T) {
+		SUT, err := TripleFromJSON[basics.Int, string, bool]([]byte(`[1, "a", false]`))
+
+		asserts.NoError(err)
+		asserts.Equal(Triple[basics.Int, string, bool](1, "a", false), SUT)
+	})
+	t.Run("FromJSON with the wrong number of values", func(t *testing.T) {
+		_, err1 := PairFromJSON[basics.Int, basics.Int]([]byte(`[1, 2, 3]`))
+		_, err2 := TripleFromJSON[basics.Int, basics.Int, basics.Int]([]byte(`[1, 2]`))
+
+		asserts.EqualError(err1, "tuple: expecting an array of 2 values but got 3")
+		asserts.EqualError(err2, "tuple: expecting an array of 3 values but got 2")
+	})
+	t.Run("Round trip characters", func(t *testing.T) {
+		x := Pair[char.Char, char.Char]('a', 'b')
+		data, err := json.Marshal(x)
+		asserts.NoError(err)
+		asserts.Equal(`["a","b"]`, string(data))
+
+		SUT, err := PairFromJSON[char.Char, char.Char](data)
+
+		asserts.NoError(err)
+		asserts.Equal(x, SUT)
+	})
+	t.Run("Round trip nested tuples", func(t *testing.T) {
+		RegisterPair[basics.Int, basics.Int]()
+		x := Pair(Pair[basics.Int, basics.Int](1, 2), "b")
+		data, err := json.Marshal(x)
+		asserts.NoError(err)
+
+		SUT, err := PairFromJSON[Tuple2[basics.Int, basics.Int], string](data)
+
+		asserts.NoError(err)
+		asserts.Equal(x, SUT)
+	})
+	t.Run("Round trip a struct with Fields", func(t *testing.T) {
+		type shape struct {
+			Point PairField[basics.Int, basics.Int]
+			Color TripleField[basics.Int, basics.Int, basics.Int]
+		}
+		x := shape{
+			Point: PairField[basics.Int, basics.Int]{Tuple2: Pair[basics.Int, basics.Int](1, 2)},
+			Color: TripleField[basics.Int, basics.Int, basics.Int]{Tuple3: Triple[basics.Int, basics.Int, basics.Int](255, 0, 0)},
+		}
+		data, err := json.Marshal(x)
+		asserts.NoError(err)
+		asserts.Equal(`{"Point":[1,2],"Color":[255,0,0]}`, string(data))
+
+		var SUT shape
+		asserts.NoError(json.Unmarshal(data, &SUT))
+
+		asserts.Equal(x, SUT)
+	})
+}
+
+func TestGob(t *testing.T) {
+	asserts := assert.New(t)
+	RegisterPair[basics.Int, basics.Int]()
+	RegisterTriple[string, basics.Int, bool]()
+	p := payload{Point: Pair[basics.Int, basics.Int](1, 2), Named: Triple[string, basics.Int, bool]("a", 0, true)}
+
+	var buf bytes.Buffer
+	asserts.NoError(gob.NewEncoder(&buf).Encode(p))
+	var SUT payload
+	asserts.NoError(gob.NewDecoder(&buf).Decode(&SUT))
+
+	asserts.Equal(p, SUT)
+}
@@ -1,7 +1,23 @@
 package tuple
 
+import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
+	"github.com/Confidenceman02/scion-tools/pkg/internal"
+	"github.com/Confidenceman02/scion-tools/pkg/internal/hashing"
+)
+
+// A 2-tuple. Tuples of Comparable values are Comparable, ordered by their first value and then by their second.
 type Tuple2[A, B any] interface {
 	tuple2() *_tuple2[A, B]
+	Cmp(basics.Comparable[Tuple2[A, B]]) int
+	T() Tuple2[A, B]
+}
+
+// A 3-tuple. Tuples of Comparable values are Comparable, ordered by their values from first to third.
+type Tuple3[A, B, C any] interface {
+	tuple3() *_tuple3[A, B, C]
+	Cmp(basics.Comparable[Tuple3[A, B, C]]) int
+	T() Tuple3[A, B, C]
 }
 
 type _tuple2[A, B any] struct {
@@ -17,6 +33,70 @@ type tuple2[A, B any] struct {
 	*_tuple2[A, B]
 }
 
+type _tuple3[A, B, C any] struct {
+	a A
+	b B
+	c C
+}
+
+func (t *_tuple3[A, B, C]) tuple3() *_tuple3[A, B, C] {
+	return t
+}
+
+type tuple3[A, B, C any] struct {
+	*_tuple3[A, B, C]
+}
+
+// Comparable
+
+func (t *tuple2[A, B]) Cmp(y basics.Comparable[Tuple2[A, B]]) int {
+	t2 := y.T().tuple2()
+	if ord := internal.CmpHelp(t.a, t2.a); ord != 0 {
+		return ord
+	}
+	return internal.CmpHelp(t.b, t2.b)
+}
+
+func (t *tuple2[A, B]) T() Tuple2[A, B] {
+	return t
+}
+
+func (t *tuple3[A, B, C]) Cmp(y basics.Comparable[Tuple3[A, B, C]]) int {
+	t2 := y.T().tuple3()
+	if ord := internal.CmpHelp(t.a, t2.a); ord != 0 {
+		return ord
+	}
+	if ord := internal.CmpHelp(t.b, t2.b); ord != 0 {
+		return ord
+	}
+	return internal.CmpHelp(t.c, t2.c)
+}
+
+func (t *tuple3[A, B, C]) T() Tuple3[A, B, C] {
+	return t
+}
+
+// Equality
+
+// Tuples are equal when their values are equal, compared with basics.Eq.
+func (t *tuple2[A, B]) Equal(y Tuple2[A, B]) bool {
+	t2 := y.tuple2()
+	return basics.Eq(t.a, t2.a) && basics.Eq(t.b, t2.b)
+}
+
+func (t *tuple2[A, B]) Hash() uint64 {
+	return hashing.Combine(basics.Hash(t.a), basics.Hash(t.b))
+}
+
+func (t *tuple3[A, B, C]) Equal(y Tuple3[A, B, C]) bool {
+	t3 := y.tuple3()
+	return basics.Eq(t.a, t3.a) && basics.Eq(t.b, t3.b) && basics.Eq(t.c, t3.c)
+}
+
+func (t *tuple3[A, B, C]) Hash() uint64 {
+	return hashing.Combine(hashing.Combine(basics.Hash(t.a), basics.Hash(t.b)), basics.Hash(t.c))
+}
+
 // Create
 
 // Create a 2-tuple.
@@ -24,6 +104,11 @@ func Pair[A, B any](a A, b B) Tuple2[A, B] {
 	return &tuple2[A, B]{&_tuple2[A, B]{a: a, b: b}}
 }
 
+// Create a 3-tuple.
+func Triple[A, B, C any](a A, b B, c C) Tuple3[A, B, C] {
+	return &tuple3[A, B, C]{&_tuple3[A, B, C]{a: a, b: b, c: c}}
+}
+
 // Access
 
 // Extract the first value from a tuple.
@@ -36,6 +121,21 @@ func Second[A, B any](t Tuple2[A, B]) B {
 	return t.tuple2().b
 }
 
+// Extract the first value from a 3-tuple.
+func First3[A, B, C any](t Tuple3[A, B, C]) A {
+	return t.tuple3().a
+}
+
+// Extract the second value from a 3-tuple.
+func Second3[A, B, C any](t Tuple3[A, B, C]) B {
+	return t.tuple3().b
+}
+
+// Extract the third value from a 3-tuple.
+func Third[A, B, C any](t Tuple3[A, B, C]) C {
+	return t.tuple3().c
+}
+
 // Map
 
 // Transform the first value in a tuple.
@@ -52,3 +152,39 @@ func MapSecond[A, B, C any](f func(B) A, t Tuple2[C, B]) Tuple2[C, A] {
 func MapBoth[A, B, C, D any](f func(A) C, g func(B) D, t Tuple2[A, B]) Tuple2[C, D] {
 	return Pair(f(First(t)), g(Second(t)))
 }
+
+// Transform the first value in a 3-tuple.
+func MapFirst3[A, B, C, D any](f func(A) D, t Tuple3[A, B, C]) Tuple3[D, B, C] {
+	return Triple(f(First3(t)), Second3(t), Third(t))
+}
+
+// Transform the second value in a 3-tuple.
+func MapSecond3[A, B, C, D any](f func(B) D, t Tuple3[A, B, C]) Tuple3[A, D, C] {
+	return Triple(First3(t), f(Second3(t)), Third(t))
+}
+
+// Transform the third value in a 3-tuple.
+func MapThird[A, B, C, D any](f func(C) D, t Tuple3[A, B, C]) Tuple3[A, B, D] {
+	return Triple(First3(t), Second3(t), f(Third(t)))
+}
+
+// Transform all three parts of a 3-tuple.
+func MapAll[A, B, C, D, E, F any](f func(A) D, g func(B) E, h func(C) F, t Tuple3[A, B, C]) Tuple3[D, E, F] {
+	return Triple(f(First3(t)), g(Second3(t)), h(Third(t)))
+}
+
+// Polar Coordinates
+//
+// These live alongside Tuple2 rather than in basics, which tuple depends on.
+
+// Convert Cartesian coordinates (x,y) to polar coordinates (r,θ).
+func ToPolar[F basics.Floating](t Tuple2[F, F]) Tuple2[F, F] {
+	x, y := First(t), Second(t)
+	return Pair(basics.Sqrt(x*x+y*y), basics.Atan2(y, x))
+}
+
+// Convert polar coordinates (r,θ) to Cartesian coordinates (x,y).
+func FromPolar[F basics.Floating](t Tuple2[F, F]) Tuple2[F, F] {
+	radius, theta := First(t), Second(t)
+	return Pair(radius*basics.Cos(theta), radius*basics.Sin(theta))
+}
@@ -1,7 +1,9 @@
 package tuple
 
 import (
+	"github.com/Confidenceman02/scion-tools/pkg/basics"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -10,4 +12,146 @@ func TestTuple2(t *testing.T) {
 	t.Run("Pair", func(t *testing.T) {
 		asserts.Equal(&tuple2[int, int]{&_tuple2[int, int]{1, 2}}, Pair(1, 2))
 	})
+	t.Run("First and Second", func(t *testing.T) {
+		SUT := Pair(basics.Int(1), "a")
+
+		asserts.Equal(basics.Int(1), First(SUT))
+		asserts.Equal("a", Second(SUT))
+	})
+	t.Run("MapBoth", func(t *testing.T) {
+		SUT := MapBoth(basics.Negate[basics.Int], func(s string) int { return len(s) }, Pair(basics.Int(1), "ab"))
+
+		asserts.Equal(Pair(basics.Int(-1), 2), SUT)
+	})
+	t.Run("Compare", func(t *testing.T) {
+		p := func(a basics.Int, b basics.Float) Tuple2[basics.Int, basics.Float] { return Pair(a, b) }
+
+		asserts.Equal(basics.EQ{}, basics.Compare(p(1, 2), p(1, 2)))
+		asserts.Equal(basics.LT{}, basics.Compare(p(1, 2), p(2, 1)))
+		asserts.Equal(basics.LT{}, basics.Compare(p(1, 2), p(1, 3)))
+		asserts.Equal(basics.GT{}, basics.Compare(p(2, 0), p(1, 3)))
+		asserts.Equal(basics.GT{}, basics.Compare(p(1, 3), p(1, 2)))
+		asserts.Equal(p(1, 3), basics.Max(p(1, 2), p(1, 3)))
+	})
+	t.Run("Compare nested tuples", func(t *testing.T) {
+		p := func(a, b, c basics.Int) Tuple2[Tuple2[basics.Int, basics.Int], basics.Int] {
+			return Pair(Pair(a, b), c)
+		}
+
+		asserts.Equal(basics.LT{}, basics.Compare(p(1, 2, 9), p(1, 3, 0)))
+		asserts.Equal(basics.GT{}, basics.Compare(p(1, 3, 1), p(1, 3, 0)))
+	})
+	t.Run("Compare non comparable values", func(t *testing.T) {
+		asserts.Panics(func() { basics.Compare(Pair(1, 2), Pair(1, 3)) })
+	})
+}
+
+func TestTuple3(t *testing.T) {
+	asserts := assert.New(t)
+	t.Run("Triple", func(t *testing.T) {
+		asserts.Equal(&tuple3[int, string, bool]{&_tuple3[int, string, bool]{1, "a", true}}, Triple(1, "a", true))
+	})
+	t.Run("First3, Second3 and Third", func(t *testing.T) {
+		SUT := Triple(basics.Int(1), "a", true)
+
+		asserts.Equal(basics.Int(1), First3(SUT))
+		asserts.Equal("a", Second3(SUT))
+		asserts.Equal(true, Third(SUT))
+	})
+	t.Run("MapFirst3", func(t *testing.T) {
+		asserts.Equal(Triple(basics.Int(2), "a", true), MapFirst3(func(i basics.Int) basics.Int { return i + 1 }, Triple(basics.Int(1), "a", true)))
+	})
+	t.Run("MapSecond3", func(t *testing.T) {
+		asserts.Equal(Triple(basics.Int(1), 1, true), MapSecond3(func(s string) int { return len(s) }, Triple(basics.Int(1), "a", true)))
+	})
+	t.Run("MapThird", func(t *testing.T) {
+		asserts.Equal(Triple(basics.Int(1), "a", false), MapThird(basics.Not, Triple(basics.Int(1), "a", true)))
+	})
+	t.Run("MapAll", func(t *testing.T) {
+		SUT := MapAll(basics.Negate[basics.Int], func(s string) int { return len(s) }, basics.Not, Triple(basics.Int(1), "ab", true))
+
+		asserts.Equal(Triple(basics.Int(-1), 2, false), SUT)
+	})
+	t.Run("Compare", func(t *testing.T) {
+		tr := func(a, b, c basics.Int) Tuple3[basics.Int, basics.Int, basics.Int] { return Triple(a, b, c) }
+
+		asserts.Equal(basics.EQ{}, basics.Compare(tr(1, 2, 3), tr(1, 2, 3)))
+		asserts.Equal(basics.LT{}, basics.Compare(tr(1, 2, 3), tr(2, 0, 0)))
+		asserts.Equal(basics.LT{}, basics.Compare(tr(1, 2, 3), tr(1, 3, 0)))
+		asserts.Equal(basics.LT{}, basics.Compare(tr(1, 2, 3), tr(1, 2, 4)))
+		asserts.Equal(basics.GT{}, basics.Compare(tr(1, 2, 4), tr(1, 2, 3)))
+	})
+}
+
+func TestPolarCoordinates(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("ToPolar", func(t *testing.T) {
+		SUT1 := ToPolar(Pair[basics.Float, basics.Float](3, 4))
+		SUT2 := ToPolar(Pair[basics.Float, basics.Float](5, 12))
+		SUT3 := ToPolar(Pair[basics.Float, basics.Float](0, -1))
+
+		asserts.Equal(basics.Float(5), First(SUT1))
+		asserts.InDelta(0.927295, float64(Second(SUT1)), 1e-6)
+		asserts.Equal(basics.Float(13), First(SUT2))
+		asserts.InDelta(1.176005, float64(Second(SUT2)), 1e-6)
+		asserts.Equal(basics.Float(1), First(SUT3))
+		asserts.Equal(-basics.Pi/2, Second(SUT3))
+	})
+
+	t.Run("FromPolar", func(t *testing.T) {
+		SUT1 := FromPolar(Pair(basics.Float(2), basics.Degrees(basics.Float(90))))
+		SUT2 := FromPolar(Pair(basics.Float(5), basics.Float(0)))
+
+		asserts.InDelta(0, float64(First(SUT1)), 1e-6)
+		asserts.InDelta(2, float64(Second(SUT1)), 1e-6)
+		asserts.Equal(Pair[basics.Float, basics.Float](5, 0), SUT2)
+	})
+
+	t.Run("Float64", func(t *testing.T) {
+		SUT1 := ToPolar(Pair[basics.Float64, basics.Float64](0, -1))
+		SUT2 := FromPolar(Pair(basics.Float64(2), basics.Pi64))
+
+		asserts.Equal(Pair(basics.Float64(1), -basics.Pi64/2), SUT1)
+		asserts.InDelta(-2, float64(First(SUT2)), 1e-12)
+		asserts.InDelta(0, float64(Second(SUT2)), 1e-12)
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		SUT := FromPolar(ToPolar(Pair[basics.Float, basics.Float](3, 4)))
+
+		asserts.InDelta(3, float64(First(SUT)), 1e-5)
+		asserts.InDelta(4, float64(Second(SUT)), 1e-5)
+	})
+}
+
+func TestEquality(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("Tuple2", func(t *testing.T) {
+		x, y := Pair[caseless, basics.Int]("Go", 1), Pair[caseless, basics.Int]("GO", 1)
+
+		asserts.True(basics.Eq(x, y))
+		asserts.Equal(basics.Hash(x), basics.Hash(y))
+		asserts.False(basics.Eq(x, Pair[caseless, basics.Int]("Go", 2)))
+		asserts.NotEqual(basics.Hash(Pair[basics.Int, basics.Int](1, 2)), basics.Hash(Pair[basics.Int, basics.Int](2, 1)))
+	})
+	t.Run("Tuple3", func(t *testing.T) {
+		x, y := Triple[basics.Int, caseless, bool](1, "Go", true), Triple[basics.Int, caseless, bool](1, "gO", true)
+
+		asserts.True(basics.Eq(x, y))
+		asserts.Equal(basics.Hash(x), basics.Hash(y))
+		asserts.False(basics.Eq(x, Triple[basics.Int, caseless, bool](1, "Go", false)))
+	})
+}
+
+// A string that is equal to the same string in any case.
+type caseless string
+
+func (c caseless) Equal(y caseless) bool {
+	return strings.EqualFold(string(c), string(y))
+}
+
+func (c caseless) Hash() uint64 {
+	return basics.Hash(strings.ToLower(string(c)))
 }